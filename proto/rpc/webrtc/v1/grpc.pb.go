@@ -31,6 +31,10 @@ type PacketMessage struct {
 
 	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	Eom  bool   `protobuf:"varint,2,opt,name=eom,proto3" json:"eom,omitempty"`
+	// compressed indicates that the message this packet belongs to is
+	// compressed with the compressor negotiated for its direction of the
+	// stream. It is set on every packet of a compressed message.
+	Compressed bool `protobuf:"varint,3,opt,name=compressed,proto3" json:"compressed,omitempty"`
 }
 
 func (x *PacketMessage) Reset() {
@@ -77,6 +81,13 @@ func (x *PacketMessage) GetEom() bool {
 	return false
 }
 
+func (x *PacketMessage) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
 // A Stream represents an instance of a gRPC stream between
 // a client and a server.
 type Stream struct {
@@ -85,6 +96,12 @@ type Stream struct {
 	unknownFields protoimpl.UnknownFields
 
 	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// channel_id identifies the data channel that all frames of the stream
+	// are sent on, in both directions. It is the SCTP stream identifier of
+	// the data channel. Zero refers to the primary "data" channel; other
+	// values refer to channels from the pool the server opens when data
+	// channel pooling is enabled.
+	ChannelId uint32 `protobuf:"varint,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
 }
 
 func (x *Stream) Reset() {
@@ -124,6 +141,13 @@ func (x *Stream) GetId() uint64 {
 	return 0
 }
 
+func (x *Stream) GetChannelId() uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return 0
+}
+
 // A Request is a frame coming from a client. It is always
 // associated with a stream where the client assigns the stream
 // identifier. Servers will drop frames where the stream identifier
@@ -135,10 +159,12 @@ type Request struct {
 
 	Stream *Stream `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
 	// Types that are assignable to Type:
-	//
 	//	*Request_Headers
 	//	*Request_Message
 	//	*Request_RstStream
+	//	*Request_WindowUpdate
+	//	*Request_Ping
+	//	*Request_Pong
 	Type isRequest_Type `protobuf_oneof:"type"`
 }
 
@@ -207,6 +233,27 @@ func (x *Request) GetRstStream() bool {
 	return false
 }
 
+func (x *Request) GetWindowUpdate() *WindowUpdate {
+	if x, ok := x.GetType().(*Request_WindowUpdate); ok {
+		return x.WindowUpdate
+	}
+	return nil
+}
+
+func (x *Request) GetPing() *Ping {
+	if x, ok := x.GetType().(*Request_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (x *Request) GetPong() *Pong {
+	if x, ok := x.GetType().(*Request_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
 type isRequest_Type interface {
 	isRequest_Type()
 }
@@ -223,12 +270,30 @@ type Request_RstStream struct {
 	RstStream bool `protobuf:"varint,4,opt,name=rst_stream,json=rstStream,proto3,oneof"`
 }
 
+type Request_WindowUpdate struct {
+	WindowUpdate *WindowUpdate `protobuf:"bytes,5,opt,name=window_update,json=windowUpdate,proto3,oneof"`
+}
+
+type Request_Ping struct {
+	Ping *Ping `protobuf:"bytes,6,opt,name=ping,proto3,oneof"`
+}
+
+type Request_Pong struct {
+	Pong *Pong `protobuf:"bytes,7,opt,name=pong,proto3,oneof"`
+}
+
 func (*Request_Headers) isRequest_Type() {}
 
 func (*Request_Message) isRequest_Type() {}
 
 func (*Request_RstStream) isRequest_Type() {}
 
+func (*Request_WindowUpdate) isRequest_Type() {}
+
+func (*Request_Ping) isRequest_Type() {}
+
+func (*Request_Pong) isRequest_Type() {}
+
 // RequestHeaders describe the unary or streaming call to make.
 type RequestHeaders struct {
 	state         protoimpl.MessageState
@@ -238,6 +303,25 @@ type RequestHeaders struct {
 	Method   string               `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
 	Metadata *Metadata            `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	Timeout  *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// compression names the compressor request messages may be compressed
+	// with. It is only set once the server has listed it in
+	// ResponseHeaders.accepted_compression on an earlier stream.
+	Compression string `protobuf:"bytes,4,opt,name=compression,proto3" json:"compression,omitempty"`
+	// accepted_compression lists, in order of preference, the compressors
+	// the client can decompress response messages with.
+	AcceptedCompression []string `protobuf:"bytes,5,rep,name=accepted_compression,json=acceptedCompression,proto3" json:"accepted_compression,omitempty"`
+	// window_size, when non-zero, is how many bytes of response messages the
+	// server may send on this stream before waiting for a WindowUpdate. It
+	// also tells the server that the client understands WindowUpdate frames.
+	WindowSize uint32 `protobuf:"varint,6,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
+	// connection_window_size is how many bytes of response messages the server
+	// may send across all flow controlled streams of the connection before
+	// waiting for a WindowUpdate. Only the first value a server sees is used.
+	ConnectionWindowSize uint32 `protobuf:"varint,7,opt,name=connection_window_size,json=connectionWindowSize,proto3" json:"connection_window_size,omitempty"`
+	// flow_controlled indicates that the client limits request messages on
+	// this stream to the windows the server advertised in ResponseHeaders of
+	// an earlier stream, so the server must send WindowUpdate frames for them.
+	FlowControlled bool `protobuf:"varint,8,opt,name=flow_controlled,json=flowControlled,proto3" json:"flow_controlled,omitempty"`
 }
 
 func (x *RequestHeaders) Reset() {
@@ -291,6 +375,41 @@ func (x *RequestHeaders) GetTimeout() *durationpb.Duration {
 	return nil
 }
 
+func (x *RequestHeaders) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *RequestHeaders) GetAcceptedCompression() []string {
+	if x != nil {
+		return x.AcceptedCompression
+	}
+	return nil
+}
+
+func (x *RequestHeaders) GetWindowSize() uint32 {
+	if x != nil {
+		return x.WindowSize
+	}
+	return 0
+}
+
+func (x *RequestHeaders) GetConnectionWindowSize() uint32 {
+	if x != nil {
+		return x.ConnectionWindowSize
+	}
+	return 0
+}
+
+func (x *RequestHeaders) GetFlowControlled() bool {
+	if x != nil {
+		return x.FlowControlled
+	}
+	return false
+}
+
 // A RequestMessage contains individual gRPC messages and a potential
 // end-of-stream (EOS) marker.
 type RequestMessage struct {
@@ -365,10 +484,13 @@ type Response struct {
 
 	Stream *Stream `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
 	// Types that are assignable to Type:
-	//
 	//	*Response_Headers
 	//	*Response_Message
 	//	*Response_Trailers
+	//	*Response_WindowUpdate
+	//	*Response_Ping
+	//	*Response_Pong
+	//	*Response_GoAway
 	Type isResponse_Type `protobuf_oneof:"type"`
 }
 
@@ -437,6 +559,34 @@ func (x *Response) GetTrailers() *ResponseTrailers {
 	return nil
 }
 
+func (x *Response) GetWindowUpdate() *WindowUpdate {
+	if x, ok := x.GetType().(*Response_WindowUpdate); ok {
+		return x.WindowUpdate
+	}
+	return nil
+}
+
+func (x *Response) GetPing() *Ping {
+	if x, ok := x.GetType().(*Response_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (x *Response) GetPong() *Pong {
+	if x, ok := x.GetType().(*Response_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
+func (x *Response) GetGoAway() *GoAway {
+	if x, ok := x.GetType().(*Response_GoAway); ok {
+		return x.GoAway
+	}
+	return nil
+}
+
 type isResponse_Type interface {
 	isResponse_Type()
 }
@@ -453,12 +603,36 @@ type Response_Trailers struct {
 	Trailers *ResponseTrailers `protobuf:"bytes,4,opt,name=trailers,proto3,oneof"`
 }
 
+type Response_WindowUpdate struct {
+	WindowUpdate *WindowUpdate `protobuf:"bytes,5,opt,name=window_update,json=windowUpdate,proto3,oneof"`
+}
+
+type Response_Ping struct {
+	Ping *Ping `protobuf:"bytes,6,opt,name=ping,proto3,oneof"`
+}
+
+type Response_Pong struct {
+	Pong *Pong `protobuf:"bytes,7,opt,name=pong,proto3,oneof"`
+}
+
+type Response_GoAway struct {
+	GoAway *GoAway `protobuf:"bytes,8,opt,name=go_away,json=goAway,proto3,oneof"`
+}
+
 func (*Response_Headers) isResponse_Type() {}
 
 func (*Response_Message) isResponse_Type() {}
 
 func (*Response_Trailers) isResponse_Type() {}
 
+func (*Response_WindowUpdate) isResponse_Type() {}
+
+func (*Response_Ping) isResponse_Type() {}
+
+func (*Response_Pong) isResponse_Type() {}
+
+func (*Response_GoAway) isResponse_Type() {}
+
 // ResponseHeaders contain custom metadata that are sent to the client
 // before any message or trailers (unless only trailers are sent).
 type ResponseHeaders struct {
@@ -467,6 +641,22 @@ type ResponseHeaders struct {
 	unknownFields protoimpl.UnknownFields
 
 	Metadata *Metadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// compression names the compressor response messages may be compressed
+	// with. It is picked from RequestHeaders.accepted_compression.
+	Compression string `protobuf:"bytes,2,opt,name=compression,proto3" json:"compression,omitempty"`
+	// accepted_compression lists the compressors from
+	// RequestHeaders.accepted_compression that the server can decompress
+	// request messages with.
+	AcceptedCompression []string `protobuf:"bytes,3,rep,name=accepted_compression,json=acceptedCompression,proto3" json:"accepted_compression,omitempty"`
+	// window_size is set when the client sent RequestHeaders.window_size. It
+	// means response messages on this stream are flow controlled, and is how
+	// many bytes of request messages the client may send on later streams
+	// before waiting for a WindowUpdate.
+	WindowSize uint32 `protobuf:"varint,4,opt,name=window_size,json=windowSize,proto3" json:"window_size,omitempty"`
+	// connection_window_size is how many bytes of request messages the client
+	// may send across all flow controlled streams of the connection before
+	// waiting for a WindowUpdate.
+	ConnectionWindowSize uint32 `protobuf:"varint,5,opt,name=connection_window_size,json=connectionWindowSize,proto3" json:"connection_window_size,omitempty"`
 }
 
 func (x *ResponseHeaders) Reset() {
@@ -506,6 +696,34 @@ func (x *ResponseHeaders) GetMetadata() *Metadata {
 	return nil
 }
 
+func (x *ResponseHeaders) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *ResponseHeaders) GetAcceptedCompression() []string {
+	if x != nil {
+		return x.AcceptedCompression
+	}
+	return nil
+}
+
+func (x *ResponseHeaders) GetWindowSize() uint32 {
+	if x != nil {
+		return x.WindowSize
+	}
+	return 0
+}
+
+func (x *ResponseHeaders) GetConnectionWindowSize() uint32 {
+	if x != nil {
+		return x.ConnectionWindowSize
+	}
+	return 0
+}
+
 // ResponseMessage contains the data of a response to a call.
 type ResponseMessage struct {
 	state         protoimpl.MessageState
@@ -606,6 +824,191 @@ func (x *ResponseTrailers) GetMetadata() *Metadata {
 	return nil
 }
 
+// A WindowUpdate lets the sender of messages send more bytes once the
+// receiver has read earlier ones, much like an HTTP/2 WINDOW_UPDATE frame. A
+// WindowUpdate on a stream with an id of zero applies to the whole connection.
+// Every PacketMessage uses as many bytes of a window as its data is long plus
+// 16, so that empty messages are flow controlled as well. Senders may send
+// while their windows are positive.
+type WindowUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Increment uint32 `protobuf:"varint,1,opt,name=increment,proto3" json:"increment,omitempty"`
+}
+
+func (x *WindowUpdate) Reset() {
+	*x = WindowUpdate{}
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WindowUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindowUpdate) ProtoMessage() {}
+
+func (x *WindowUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindowUpdate.ProtoReflect.Descriptor instead.
+func (*WindowUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WindowUpdate) GetIncrement() uint32 {
+	if x != nil {
+		return x.Increment
+	}
+	return 0
+}
+
+// A Ping asks the peer to answer with a Pong carrying the same nonce. Either
+// side may send pings to detect a dead connection and to measure the round
+// trip time. Pings and pongs are sent on a stream with an id of zero. Peers
+// that never answer a ping are assumed to not support them.
+type Ping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *Ping) Reset() {
+	*x = Ping{}
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ping) ProtoMessage() {}
+
+func (x *Ping) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ping.ProtoReflect.Descriptor instead.
+func (*Ping) Descriptor() ([]byte, []int) {
+	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Ping) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// A Pong answers a Ping.
+type Pong struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *Pong) Reset() {
+	*x = Pong{}
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pong) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pong) ProtoMessage() {}
+
+func (x *Pong) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pong.ProtoReflect.Descriptor instead.
+func (*Pong) Descriptor() ([]byte, []int) {
+	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Pong) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// A GoAway tells the client that the server is shutting down. Streams already
+// started keep going until they are done but new ones are refused, so clients
+// should connect again for them. It is sent on a stream with an id of zero.
+type GoAway struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GoAway) Reset() {
+	*x = GoAway{}
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GoAway) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GoAway) ProtoMessage() {}
+
+func (x *GoAway) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GoAway.ProtoReflect.Descriptor instead.
+func (*GoAway) Descriptor() ([]byte, []int) {
+	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{12}
+}
+
 // Strings are a series of values.
 type Strings struct {
 	state         protoimpl.MessageState
@@ -617,7 +1020,7 @@ type Strings struct {
 
 func (x *Strings) Reset() {
 	*x = Strings{}
-	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[9]
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -629,7 +1032,7 @@ func (x *Strings) String() string {
 func (*Strings) ProtoMessage() {}
 
 func (x *Strings) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[9]
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -642,7 +1045,7 @@ func (x *Strings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Strings.ProtoReflect.Descriptor instead.
 func (*Strings) Descriptor() ([]byte, []int) {
-	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{9}
+	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Strings) GetValues() []string {
@@ -664,7 +1067,7 @@ type Metadata struct {
 
 func (x *Metadata) Reset() {
 	*x = Metadata{}
-	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[10]
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -676,7 +1079,7 @@ func (x *Metadata) String() string {
 func (*Metadata) ProtoMessage() {}
 
 func (x *Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[10]
+	mi := &file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -689,7 +1092,7 @@ func (x *Metadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
 func (*Metadata) Descriptor() ([]byte, []int) {
-	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{10}
+	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Metadata) GetMd() map[string]*Strings {
@@ -708,37 +1111,65 @@ var file_proto_rpc_webrtc_v1_grpc_proto_rawDesc = []byte{
 	0x74, 0x63, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70,
-	0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x35,
+	0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x55,
 	0x0a, 0x0d, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
 	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64,
 	0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x03, 0x65, 0x6f, 0x6d, 0x22, 0x18, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22,
-	0xe9, 0x01, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x73,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76,
-	0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x12, 0x3f, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x52, 0x03, 0x65, 0x6f, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x64, 0x22, 0x37, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x95,
+	0x03, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x3f, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62,
+	0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x12, 0x3f, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65,
-	0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48,
-	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x73, 0x12, 0x3f, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77,
-	0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x72, 0x73, 0x74, 0x53, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x0e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x16,
-	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x39, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74,
-	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1f, 0x0a, 0x0a, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x72, 0x73, 0x74, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x48, 0x0a, 0x0d, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0c,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x04,
+	0x70, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x2f, 0x0a,
+	0x04, 0x70, 0x6f, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x6e, 0x67, 0x42, 0x06,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0xed, 0x02, 0x0a, 0x0e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x12, 0x39, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x33, 0x0a, 0x07,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x14, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x5f,
+	0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x13, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x27, 0x0a,
+	0x0f, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x66, 0x6c, 0x6f, 0x77, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x61, 0x73,
 	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
 	0x68, 0x61, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x70, 0x61,
@@ -747,7 +1178,7 @@ var file_proto_rpc_webrtc_v1_grpc_proto_rawDesc = []byte{
 	0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x4d,
 	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x4d, 0x65,
 	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x03, 0x65, 0x6f, 0x73, 0x22, 0x90, 0x02, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70,
+	0x28, 0x08, 0x52, 0x03, 0x65, 0x6f, 0x73, 0x22, 0xf4, 0x03, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70,
 	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63,
 	0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
@@ -764,40 +1195,73 @@ var file_proto_rpc_webrtc_v1_grpc_proto_rawDesc = []byte{
 	0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72,
 	0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x72,
 	0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x69, 0x6c, 0x65,
-	0x72, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x4c, 0x0a, 0x0f, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x39, 0x0a,
-	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72,
-	0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x5c, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x70,
-	0x61, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e,
-	0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x79, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x22, 0x21, 0x0a, 0x07, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x73, 0x22, 0x96, 0x01, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x12, 0x35, 0x0a, 0x02, 0x6d, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63,
-	0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4d, 0x64, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x52, 0x02, 0x6d, 0x64, 0x1a, 0x53, 0x0a, 0x07, 0x4d, 0x64, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x32, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e,
-	0x67, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x27, 0x5a,
-	0x25, 0x67, 0x6f, 0x2e, 0x76, 0x69, 0x61, 0x6d, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x75, 0x74, 0x69,
-	0x6c, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x77, 0x65, 0x62,
-	0x72, 0x74, 0x63, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x73, 0x12, 0x48, 0x0a, 0x0d, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0c,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x2f, 0x0a, 0x04,
+	0x70, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x2f, 0x0a,
+	0x04, 0x70, 0x6f, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x6e, 0x67, 0x12, 0x36,
+	0x0a, 0x07, 0x67, 0x6f, 0x5f, 0x61, 0x77, 0x61, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72,
+	0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x6f, 0x41, 0x77, 0x61, 0x79, 0x48, 0x00, 0x52, 0x06,
+	0x67, 0x6f, 0x41, 0x77, 0x61, 0x79, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0xf8,
+	0x01, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a,
+	0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x31, 0x0a, 0x14, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x61,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x14, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x57,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x5c, 0x0a, 0x0f, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x0e,
+	0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x79, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0x2c, 0x0a, 0x0c, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x69, 0x6e, 0x63, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x22, 0x1c, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x1c,
+	0x0a, 0x04, 0x50, 0x6f, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x08, 0x0a, 0x06,
+	0x47, 0x6f, 0x41, 0x77, 0x61, 0x79, 0x22, 0x21, 0x0a, 0x07, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x96, 0x01, 0x0a, 0x08, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x35, 0x0a, 0x02, 0x6d, 0x64, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77,
+	0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x2e, 0x4d, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x02, 0x6d, 0x64, 0x1a, 0x53, 0x0a,
+	0x07, 0x4d, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x32, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x6f, 0x2e, 0x76, 0x69, 0x61, 0x6d, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x75, 0x74, 0x69, 0x6c, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x70,
+	0x63, 0x2f, 0x77, 0x65, 0x62, 0x72, 0x74, 0x63, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -812,7 +1276,7 @@ func file_proto_rpc_webrtc_v1_grpc_proto_rawDescGZIP() []byte {
 	return file_proto_rpc_webrtc_v1_grpc_proto_rawDescData
 }
 
-var file_proto_rpc_webrtc_v1_grpc_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_rpc_webrtc_v1_grpc_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_proto_rpc_webrtc_v1_grpc_proto_goTypes = []any{
 	(*PacketMessage)(nil),       // 0: proto.rpc.webrtc.v1.PacketMessage
 	(*Stream)(nil),              // 1: proto.rpc.webrtc.v1.Stream
@@ -823,34 +1287,45 @@ var file_proto_rpc_webrtc_v1_grpc_proto_goTypes = []any{
 	(*ResponseHeaders)(nil),     // 6: proto.rpc.webrtc.v1.ResponseHeaders
 	(*ResponseMessage)(nil),     // 7: proto.rpc.webrtc.v1.ResponseMessage
 	(*ResponseTrailers)(nil),    // 8: proto.rpc.webrtc.v1.ResponseTrailers
-	(*Strings)(nil),             // 9: proto.rpc.webrtc.v1.Strings
-	(*Metadata)(nil),            // 10: proto.rpc.webrtc.v1.Metadata
-	nil,                         // 11: proto.rpc.webrtc.v1.Metadata.MdEntry
-	(*durationpb.Duration)(nil), // 12: google.protobuf.Duration
-	(*status.Status)(nil),       // 13: google.rpc.Status
+	(*WindowUpdate)(nil),        // 9: proto.rpc.webrtc.v1.WindowUpdate
+	(*Ping)(nil),                // 10: proto.rpc.webrtc.v1.Ping
+	(*Pong)(nil),                // 11: proto.rpc.webrtc.v1.Pong
+	(*GoAway)(nil),              // 12: proto.rpc.webrtc.v1.GoAway
+	(*Strings)(nil),             // 13: proto.rpc.webrtc.v1.Strings
+	(*Metadata)(nil),            // 14: proto.rpc.webrtc.v1.Metadata
+	nil,                         // 15: proto.rpc.webrtc.v1.Metadata.MdEntry
+	(*durationpb.Duration)(nil), // 16: google.protobuf.Duration
+	(*status.Status)(nil),       // 17: google.rpc.Status
 }
 var file_proto_rpc_webrtc_v1_grpc_proto_depIdxs = []int32{
 	1,  // 0: proto.rpc.webrtc.v1.Request.stream:type_name -> proto.rpc.webrtc.v1.Stream
 	3,  // 1: proto.rpc.webrtc.v1.Request.headers:type_name -> proto.rpc.webrtc.v1.RequestHeaders
 	4,  // 2: proto.rpc.webrtc.v1.Request.message:type_name -> proto.rpc.webrtc.v1.RequestMessage
-	10, // 3: proto.rpc.webrtc.v1.RequestHeaders.metadata:type_name -> proto.rpc.webrtc.v1.Metadata
-	12, // 4: proto.rpc.webrtc.v1.RequestHeaders.timeout:type_name -> google.protobuf.Duration
-	0,  // 5: proto.rpc.webrtc.v1.RequestMessage.packet_message:type_name -> proto.rpc.webrtc.v1.PacketMessage
-	1,  // 6: proto.rpc.webrtc.v1.Response.stream:type_name -> proto.rpc.webrtc.v1.Stream
-	6,  // 7: proto.rpc.webrtc.v1.Response.headers:type_name -> proto.rpc.webrtc.v1.ResponseHeaders
-	7,  // 8: proto.rpc.webrtc.v1.Response.message:type_name -> proto.rpc.webrtc.v1.ResponseMessage
-	8,  // 9: proto.rpc.webrtc.v1.Response.trailers:type_name -> proto.rpc.webrtc.v1.ResponseTrailers
-	10, // 10: proto.rpc.webrtc.v1.ResponseHeaders.metadata:type_name -> proto.rpc.webrtc.v1.Metadata
-	0,  // 11: proto.rpc.webrtc.v1.ResponseMessage.packet_message:type_name -> proto.rpc.webrtc.v1.PacketMessage
-	13, // 12: proto.rpc.webrtc.v1.ResponseTrailers.status:type_name -> google.rpc.Status
-	10, // 13: proto.rpc.webrtc.v1.ResponseTrailers.metadata:type_name -> proto.rpc.webrtc.v1.Metadata
-	11, // 14: proto.rpc.webrtc.v1.Metadata.md:type_name -> proto.rpc.webrtc.v1.Metadata.MdEntry
-	9,  // 15: proto.rpc.webrtc.v1.Metadata.MdEntry.value:type_name -> proto.rpc.webrtc.v1.Strings
-	16, // [16:16] is the sub-list for method output_type
-	16, // [16:16] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	9,  // 3: proto.rpc.webrtc.v1.Request.window_update:type_name -> proto.rpc.webrtc.v1.WindowUpdate
+	10, // 4: proto.rpc.webrtc.v1.Request.ping:type_name -> proto.rpc.webrtc.v1.Ping
+	11, // 5: proto.rpc.webrtc.v1.Request.pong:type_name -> proto.rpc.webrtc.v1.Pong
+	14, // 6: proto.rpc.webrtc.v1.RequestHeaders.metadata:type_name -> proto.rpc.webrtc.v1.Metadata
+	16, // 7: proto.rpc.webrtc.v1.RequestHeaders.timeout:type_name -> google.protobuf.Duration
+	0,  // 8: proto.rpc.webrtc.v1.RequestMessage.packet_message:type_name -> proto.rpc.webrtc.v1.PacketMessage
+	1,  // 9: proto.rpc.webrtc.v1.Response.stream:type_name -> proto.rpc.webrtc.v1.Stream
+	6,  // 10: proto.rpc.webrtc.v1.Response.headers:type_name -> proto.rpc.webrtc.v1.ResponseHeaders
+	7,  // 11: proto.rpc.webrtc.v1.Response.message:type_name -> proto.rpc.webrtc.v1.ResponseMessage
+	8,  // 12: proto.rpc.webrtc.v1.Response.trailers:type_name -> proto.rpc.webrtc.v1.ResponseTrailers
+	9,  // 13: proto.rpc.webrtc.v1.Response.window_update:type_name -> proto.rpc.webrtc.v1.WindowUpdate
+	10, // 14: proto.rpc.webrtc.v1.Response.ping:type_name -> proto.rpc.webrtc.v1.Ping
+	11, // 15: proto.rpc.webrtc.v1.Response.pong:type_name -> proto.rpc.webrtc.v1.Pong
+	12, // 16: proto.rpc.webrtc.v1.Response.go_away:type_name -> proto.rpc.webrtc.v1.GoAway
+	14, // 17: proto.rpc.webrtc.v1.ResponseHeaders.metadata:type_name -> proto.rpc.webrtc.v1.Metadata
+	0,  // 18: proto.rpc.webrtc.v1.ResponseMessage.packet_message:type_name -> proto.rpc.webrtc.v1.PacketMessage
+	17, // 19: proto.rpc.webrtc.v1.ResponseTrailers.status:type_name -> google.rpc.Status
+	14, // 20: proto.rpc.webrtc.v1.ResponseTrailers.metadata:type_name -> proto.rpc.webrtc.v1.Metadata
+	15, // 21: proto.rpc.webrtc.v1.Metadata.md:type_name -> proto.rpc.webrtc.v1.Metadata.MdEntry
+	13, // 22: proto.rpc.webrtc.v1.Metadata.MdEntry.value:type_name -> proto.rpc.webrtc.v1.Strings
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_proto_rpc_webrtc_v1_grpc_proto_init() }
@@ -862,11 +1337,18 @@ func file_proto_rpc_webrtc_v1_grpc_proto_init() {
 		(*Request_Headers)(nil),
 		(*Request_Message)(nil),
 		(*Request_RstStream)(nil),
+		(*Request_WindowUpdate)(nil),
+		(*Request_Ping)(nil),
+		(*Request_Pong)(nil),
 	}
 	file_proto_rpc_webrtc_v1_grpc_proto_msgTypes[5].OneofWrappers = []any{
 		(*Response_Headers)(nil),
 		(*Response_Message)(nil),
 		(*Response_Trailers)(nil),
+		(*Response_WindowUpdate)(nil),
+		(*Response_Ping)(nil),
+		(*Response_Pong)(nil),
+		(*Response_GoAway)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -874,7 +1356,7 @@ func file_proto_rpc_webrtc_v1_grpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_rpc_webrtc_v1_grpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -23,6 +23,8 @@ const (
 	SignalingService_CallUpdate_FullMethodName           = "/proto.rpc.webrtc.v1.SignalingService/CallUpdate"
 	SignalingService_Answer_FullMethodName               = "/proto.rpc.webrtc.v1.SignalingService/Answer"
 	SignalingService_OptionalWebRTCConfig_FullMethodName = "/proto.rpc.webrtc.v1.SignalingService/OptionalWebRTCConfig"
+	SignalingService_ListOnlineHosts_FullMethodName      = "/proto.rpc.webrtc.v1.SignalingService/ListOnlineHosts"
+	SignalingService_GetHostStatus_FullMethodName        = "/proto.rpc.webrtc.v1.SignalingService/GetHostStatus"
 )
 
 // SignalingServiceClient is the client API for SignalingService service.
@@ -54,6 +56,14 @@ type SignalingServiceClient interface {
 	// OptionalWebRTCConfig returns any WebRTC configuration the caller may want to use.
 	// The host to get a config for must be in the rpc-host metadata field.
 	OptionalWebRTCConfig(ctx context.Context, in *OptionalWebRTCConfigRequest, opts ...grpc.CallOption) (*OptionalWebRTCConfigResponse, error)
+	// ListOnlineHosts lists the hosts that have answerers connected that the
+	// caller may see. Servers refuse it with PERMISSION_DENIED unless they are
+	// configured to decide which hosts each caller may see.
+	ListOnlineHosts(ctx context.Context, in *ListOnlineHostsRequest, opts ...grpc.CallOption) (*ListOnlineHostsResponse, error)
+	// GetHostStatus returns whether a host has answerers connected without
+	// placing a call to it.
+	// The host to get the status of must be in the rpc-host metadata field.
+	GetHostStatus(ctx context.Context, in *GetHostStatusRequest, opts ...grpc.CallOption) (*GetHostStatusResponse, error)
 }
 
 type signalingServiceClient struct {
@@ -116,6 +126,26 @@ func (c *signalingServiceClient) OptionalWebRTCConfig(ctx context.Context, in *O
 	return out, nil
 }
 
+func (c *signalingServiceClient) ListOnlineHosts(ctx context.Context, in *ListOnlineHostsRequest, opts ...grpc.CallOption) (*ListOnlineHostsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOnlineHostsResponse)
+	err := c.cc.Invoke(ctx, SignalingService_ListOnlineHosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingServiceClient) GetHostStatus(ctx context.Context, in *GetHostStatusRequest, opts ...grpc.CallOption) (*GetHostStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHostStatusResponse)
+	err := c.cc.Invoke(ctx, SignalingService_GetHostStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SignalingServiceServer is the server API for SignalingService service.
 // All implementations must embed UnimplementedSignalingServiceServer
 // for forward compatibility.
@@ -145,6 +175,14 @@ type SignalingServiceServer interface {
 	// OptionalWebRTCConfig returns any WebRTC configuration the caller may want to use.
 	// The host to get a config for must be in the rpc-host metadata field.
 	OptionalWebRTCConfig(context.Context, *OptionalWebRTCConfigRequest) (*OptionalWebRTCConfigResponse, error)
+	// ListOnlineHosts lists the hosts that have answerers connected that the
+	// caller may see. Servers refuse it with PERMISSION_DENIED unless they are
+	// configured to decide which hosts each caller may see.
+	ListOnlineHosts(context.Context, *ListOnlineHostsRequest) (*ListOnlineHostsResponse, error)
+	// GetHostStatus returns whether a host has answerers connected without
+	// placing a call to it.
+	// The host to get the status of must be in the rpc-host metadata field.
+	GetHostStatus(context.Context, *GetHostStatusRequest) (*GetHostStatusResponse, error)
 	mustEmbedUnimplementedSignalingServiceServer()
 }
 
@@ -167,6 +205,12 @@ func (UnimplementedSignalingServiceServer) Answer(grpc.BidiStreamingServer[Answe
 func (UnimplementedSignalingServiceServer) OptionalWebRTCConfig(context.Context, *OptionalWebRTCConfigRequest) (*OptionalWebRTCConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OptionalWebRTCConfig not implemented")
 }
+func (UnimplementedSignalingServiceServer) ListOnlineHosts(context.Context, *ListOnlineHostsRequest) (*ListOnlineHostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOnlineHosts not implemented")
+}
+func (UnimplementedSignalingServiceServer) GetHostStatus(context.Context, *GetHostStatusRequest) (*GetHostStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHostStatus not implemented")
+}
 func (UnimplementedSignalingServiceServer) mustEmbedUnimplementedSignalingServiceServer() {}
 func (UnimplementedSignalingServiceServer) testEmbeddedByValue()                          {}
 
@@ -242,6 +286,42 @@ func _SignalingService_OptionalWebRTCConfig_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SignalingService_ListOnlineHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOnlineHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServiceServer).ListOnlineHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalingService_ListOnlineHosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServiceServer).ListOnlineHosts(ctx, req.(*ListOnlineHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SignalingService_GetHostStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHostStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServiceServer).GetHostStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SignalingService_GetHostStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServiceServer).GetHostStatus(ctx, req.(*GetHostStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SignalingService_ServiceDesc is the grpc.ServiceDesc for SignalingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -257,6 +337,14 @@ var SignalingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "OptionalWebRTCConfig",
 			Handler:    _SignalingService_OptionalWebRTCConfig_Handler,
 		},
+		{
+			MethodName: "ListOnlineHosts",
+			Handler:    _SignalingService_ListOnlineHosts_Handler,
+		},
+		{
+			MethodName: "GetHostStatus",
+			Handler:    _SignalingService_GetHostStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,674 @@
+// Package callqueuetest provides a behavioral test suite for implementations of rpc.WebRTCCallQueue.
+package callqueuetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/viamrobotics/webrtc/v3"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"go.viam.com/test"
+
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/testutils"
+)
+
+// Setup is what a Factory returns for every test in the suite.
+type Setup struct {
+	// CallerQueue is the queue offers are sent through.
+	CallerQueue rpc.WebRTCCallQueue
+
+	// AnswererQueue is the queue offers are received through. It may be the same queue
+	// as the CallerQueue.
+	AnswererQueue rpc.WebRTCCallQueue
+
+	// WaitForAnswererOnline is optional and is used by queues that only eventually learn of
+	// answerers coming online. It should block until an answerer waiting in RecvOffer is
+	// known to be online for all the given hosts.
+	WaitForAnswererOnline func(ctx context.Context, hosts []string) error
+
+	// Teardown is called at the end of the test to close the queues.
+	Teardown func()
+}
+
+// A Factory returns a fresh Setup for a single test in the suite. Offers sent through its queues
+// should expire after offerDeadline.
+type Factory func(t *testing.T, offerDeadline time.Duration) Setup
+
+// RunWebRTCCallQueueSuite runs a set of behavioral tests that every WebRTCCallQueue is
+// expected to pass. The tests cover offer deadlines, caller and answerer cancellation,
+// trickle and non-trickle offers, receiving offers across multiple hosts and by priority
+// class in order, and updates to inactive offers.
+//
+// Queues may either reject offers to hosts with no answerer online with rpc.ErrHostOffline
+// or accept them until the offer deadline passes.
+func RunWebRTCCallQueueSuite(t *testing.T, factory Factory) {
+	t.Helper()
+
+	setupWithOfferDeadline := func(
+		t *testing.T,
+		offerDeadline time.Duration,
+	) (rpc.WebRTCCallQueue, rpc.WebRTCCallQueue, func(hosts ...string)) {
+		t.Helper()
+		s := factory(t, offerDeadline)
+		t.Cleanup(s.Teardown)
+		waitForAnswererOnline := func(hosts ...string) {
+			t.Helper()
+			if s.WaitForAnswererOnline == nil {
+				return
+			}
+			test.That(t, s.WaitForAnswererOnline(context.Background(), hosts), test.ShouldBeNil)
+		}
+		return s.CallerQueue, s.AnswererQueue, waitForAnswererOnline
+	}
+	setup := func(t *testing.T) (rpc.WebRTCCallQueue, rpc.WebRTCCallQueue, func(hosts ...string)) {
+		t.Helper()
+		return setupWithOfferDeadline(t, rpc.DefaultWebRTCCallOfferDeadline())
+	}
+
+	t.Run("sending an offer for too long should signal done or error due to offline host", func(t *testing.T) {
+		callerQueue, _, _ := setupWithOfferDeadline(t, time.Second)
+
+		host := uuid.NewString()
+		_, _, ansCtx, _, err := callerQueue.SendOfferInit(context.Background(), host, "somesdp", false)
+		if err != nil {
+			test.That(t, err, test.ShouldBeError, rpc.ErrHostOffline)
+			return
+		}
+		<-ansCtx
+	})
+
+	t.Run("recv can get caller updates and done", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		recvErrCh := make(chan error)
+		recvCandCh := make(chan webrtc.ICECandidateInit)
+		c1, c2, c3 := newCandidate("c1"), newCandidate("c2"), newCandidate("c3")
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			if err != nil {
+				recvErrCh <- err
+				return
+			}
+
+			sdp := "world"
+			recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{InitialSDP: &sdp})
+			recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{Candidate: c1})
+			recvErrCh <- offer.AnswererDone(context.Background())
+			recvCandCh <- <-offer.CallerCandidates()
+			recvCandCh <- <-offer.CallerCandidates()
+			<-offer.CallerDone()
+			close(done)
+		}()
+		waitForAnswererOnline(host)
+
+		newUUID, answers, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer cancel()
+		test.That(t, newUUID, test.ShouldNotBeEmpty)
+		ans := <-answers
+		test.That(t, ans.InitialSDP, test.ShouldNotBeNil)
+		test.That(t, *ans.InitialSDP, test.ShouldEqual, "world")
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		ans = <-answers
+		test.That(t, ans.Candidate, test.ShouldNotBeNil)
+		test.That(t, ans.Candidate, test.ShouldResemble, c1)
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		<-answersDone
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		test.That(t, callerQueue.SendOfferUpdate(context.Background(), host, newUUID, *c2), test.ShouldBeNil)
+		test.That(t, <-recvCandCh, test.ShouldResemble, *c2)
+		test.That(t, callerQueue.SendOfferUpdate(context.Background(), host, newUUID, *c3), test.ShouldBeNil)
+		test.That(t, <-recvCandCh, test.ShouldResemble, *c3)
+		test.That(t, callerQueue.SendOfferDone(context.Background(), host, newUUID), test.ShouldBeNil)
+	})
+
+	t.Run("recv can get caller updates and error", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		recvErrCh := make(chan error)
+		recvCandCh := make(chan webrtc.ICECandidateInit)
+		c1, c2, c3 := newCandidate("c1"), newCandidate("c2"), newCandidate("c3")
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			if err != nil {
+				recvErrCh <- err
+				return
+			}
+
+			sdp := "world"
+			recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{InitialSDP: &sdp})
+			recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{Candidate: c1})
+			recvErrCh <- offer.AnswererDone(context.Background())
+			recvCandCh <- <-offer.CallerCandidates()
+			recvCandCh <- <-offer.CallerCandidates()
+			<-offer.CallerDone()
+			recvErrCh <- offer.CallerErr()
+			close(done)
+		}()
+		waitForAnswererOnline(host)
+
+		newUUID, answers, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer cancel()
+		test.That(t, newUUID, test.ShouldNotBeEmpty)
+		ans := <-answers
+		test.That(t, ans.InitialSDP, test.ShouldNotBeNil)
+		test.That(t, *ans.InitialSDP, test.ShouldEqual, "world")
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		ans = <-answers
+		test.That(t, ans.Candidate, test.ShouldNotBeNil)
+		test.That(t, ans.Candidate, test.ShouldResemble, c1)
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		<-answersDone
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		test.That(t, callerQueue.SendOfferUpdate(context.Background(), host, newUUID, *c2), test.ShouldBeNil)
+		test.That(t, <-recvCandCh, test.ShouldResemble, *c2)
+		test.That(t, callerQueue.SendOfferUpdate(context.Background(), host, newUUID, *c3), test.ShouldBeNil)
+		test.That(t, <-recvCandCh, test.ShouldResemble, *c3)
+		test.That(t, callerQueue.SendOfferError(context.Background(), host, newUUID, errors.New("whoops")), test.ShouldBeNil)
+		test.That(t, <-recvErrCh, test.ShouldBeError, errors.New("whoops"))
+	})
+
+	t.Run("canceling an offer should eventually close answerer responses", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+
+		// We need to have an answerer online to handle this host before sending the offer so it doesn't immediately fail, but we don't care what
+		// happens to the offer, just that it gets cleaned up.
+		answerCtx, answerCancel := context.WithCancel(context.Background())
+		answererDone := make(chan error, 1)
+		defer func() {
+			answerCancel()
+			select {
+			case answererErr := <-answererDone:
+				if answererErr != nil && !errors.Is(answererErr, context.Canceled) {
+					t.Errorf("unexpected error: %v", answererErr)
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("RecvOffer goroutine did not complete")
+			}
+		}()
+		go func() {
+			_, answererErr := answererQueue.RecvOffer(answerCtx, []string{host})
+			answererDone <- answererErr
+		}()
+		waitForAnswererOnline(host)
+
+		newUUID, _, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		cancel()
+		test.That(t, newUUID, test.ShouldNotBeEmpty)
+		<-answersDone
+	})
+
+	t.Run("canceling a receive should return the context error", func(t *testing.T) {
+		_, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		answerCtx, answerCancel := context.WithCancel(context.Background())
+		defer answerCancel()
+		answererDone := make(chan error, 1)
+		go func() {
+			_, answererErr := answererQueue.RecvOffer(answerCtx, []string{host})
+			answererDone <- answererErr
+		}()
+		waitForAnswererOnline(host)
+
+		answerCancel()
+		select {
+		case answererErr := <-answererDone:
+			test.That(t, answererErr, test.ShouldNotBeNil)
+			test.That(t, errors.Is(answererErr, context.Canceled), test.ShouldBeTrue)
+		case <-time.After(5 * time.Second):
+			t.Fatal("RecvOffer did not return after its context was canceled")
+		}
+	})
+
+	t.Run("sending successfully with an sdp", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		hosts := []string{uuid.NewString(), uuid.NewString()}
+		for idx, host := range hosts {
+			t.Run(fmt.Sprintf("%d", idx), func(t *testing.T) {
+				recvErrCh := make(chan error)
+				c1 := newCandidate("c1")
+				done := make(chan struct{})
+				defer func() { <-done }()
+				go func() {
+					offer, err := answererQueue.RecvOffer(context.Background(), hosts)
+					if err != nil {
+						recvErrCh <- err
+						return
+					}
+
+					sdp := "world"
+					recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{InitialSDP: &sdp})
+					recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{Candidate: c1})
+					recvErrCh <- offer.AnswererDone(context.Background())
+					close(done)
+				}()
+				waitForAnswererOnline(host)
+
+				newUUID, answers, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", false)
+				test.That(t, err, test.ShouldBeNil)
+				defer cancel()
+				test.That(t, newUUID, test.ShouldNotBeEmpty)
+				ans := <-answers
+				test.That(t, ans.InitialSDP, test.ShouldNotBeNil)
+				test.That(t, *ans.InitialSDP, test.ShouldEqual, "world")
+				test.That(t, <-recvErrCh, test.ShouldBeNil)
+				ans = <-answers
+				test.That(t, ans.Candidate, test.ShouldNotBeNil)
+				test.That(t, ans.Candidate, test.ShouldResemble, c1)
+				test.That(t, <-recvErrCh, test.ShouldBeNil)
+				<-answersDone
+				test.That(t, <-recvErrCh, test.ShouldBeNil)
+			})
+		}
+	})
+
+	t.Run("sending successfully with an error", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		recvErrCh := make(chan error)
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			if err != nil {
+				recvErrCh <- err
+				return
+			}
+
+			recvErrCh <- offer.AnswererRespond(context.Background(), rpc.WebRTCCallAnswer{Err: errors.New("whoops")})
+			recvErrCh <- offer.AnswererDone(context.Background())
+			close(done)
+		}()
+		waitForAnswererOnline(host)
+
+		newUUID, answers, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer cancel()
+		test.That(t, newUUID, test.ShouldNotBeEmpty)
+		test.That(t, (<-answers).Err.Error(), test.ShouldContainSubstring, "whoops")
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+		<-answersDone
+		test.That(t, <-recvErrCh, test.ShouldBeNil)
+	})
+
+	t.Run("offers should carry trickle settings and deadlines", func(t *testing.T) {
+		for _, disableTrickle := range []bool{false, true} {
+			t.Run(fmt.Sprintf("disable trickle=%t", disableTrickle), func(t *testing.T) {
+				callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+				host := uuid.NewString()
+				offerCh := make(chan rpc.WebRTCCallOfferExchange, 1)
+				recvErrCh := make(chan error, 1)
+				go func() {
+					offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+					if err != nil {
+						recvErrCh <- err
+						return
+					}
+					offerCh <- offer
+				}()
+				waitForAnswererOnline(host)
+
+				sentAt := time.Now()
+				newUUID, _, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", disableTrickle)
+				test.That(t, err, test.ShouldBeNil)
+				defer func() {
+					cancel()
+					<-answersDone
+				}()
+
+				var offer rpc.WebRTCCallOfferExchange
+				select {
+				case offer = <-offerCh:
+				case err := <-recvErrCh:
+					t.Fatal(err)
+				}
+				test.That(t, offer.UUID(), test.ShouldEqual, newUUID)
+				test.That(t, offer.SDP(), test.ShouldEqual, "hello")
+				test.That(t, offer.DisableTrickleICE(), test.ShouldEqual, disableTrickle)
+				test.That(t, offer.Deadline(), test.ShouldHappenAfter, sentAt)
+				test.That(t, offer.Deadline(), test.ShouldHappenOnOrBefore,
+					time.Now().Add(rpc.DefaultWebRTCCallOfferDeadline()))
+				test.That(t, offer.AnswererDone(context.Background()), test.ShouldBeNil)
+			})
+		}
+	})
+
+	t.Run("offers should carry the trace context of their caller", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		offerCh := make(chan rpc.WebRTCCallOfferExchange, 1)
+		recvErrCh := make(chan error, 1)
+		go func() {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			if err != nil {
+				recvErrCh <- err
+				return
+			}
+			offerCh <- offer
+		}()
+		waitForAnswererOnline(host)
+
+		spanContext := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    oteltrace.TraceID(uuid.New()),
+			SpanID:     oteltrace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+			TraceFlags: oteltrace.FlagsSampled,
+		})
+		callerCtx := oteltrace.ContextWithSpanContext(context.Background(), spanContext)
+		_, _, answersDone, cancel, err := callerQueue.SendOfferInit(callerCtx, host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			cancel()
+			<-answersDone
+		}()
+
+		var offer rpc.WebRTCCallOfferExchange
+		select {
+		case offer = <-offerCh:
+		case err := <-recvErrCh:
+			t.Fatal(err)
+		}
+		traced, ok := offer.(rpc.WebRTCTracedCallOffer)
+		if !ok {
+			t.Skip("queue does not implement rpc.WebRTCTracedCallOffer")
+		}
+		test.That(t, traced.TraceContext(), test.ShouldResemble, map[string]string{
+			"traceparent": fmt.Sprintf("00-%s-%s-01", spanContext.TraceID(), spanContext.SpanID()),
+		})
+		test.That(t, offer.AnswererDone(context.Background()), test.ShouldBeNil)
+	})
+
+	t.Run("offers should be received by priority class", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		offerCh := make(chan rpc.WebRTCCallOfferExchange, 1)
+		recvErrCh := make(chan error, 1)
+		go func() {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			if err != nil {
+				recvErrCh <- err
+				return
+			}
+			offerCh <- offer
+		}()
+		waitForAnswererOnline(host)
+
+		// keep the first offer in progress so the host stays online while the rest wait.
+		_, _, firstDone, firstCancel, err := callerQueue.SendOfferInit(context.Background(), host, "first", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			firstCancel()
+			<-firstDone
+		}()
+		var first rpc.WebRTCCallOfferExchange
+		select {
+		case first = <-offerCh:
+		case err := <-recvErrCh:
+			t.Fatal(err)
+		}
+		test.That(t, first.SDP(), test.ShouldEqual, "first")
+
+		priorities := []rpc.WebRTCCallPriority{
+			rpc.WebRTCCallPriorityLow,
+			rpc.WebRTCCallPriorityNormal,
+			rpc.WebRTCCallPriorityHigh,
+		}
+		for _, priority := range priorities {
+			ctx := rpc.ContextWithWebRTCCallPriority(context.Background(), priority)
+			_, _, answersDone, cancel, err := callerQueue.SendOfferInit(ctx, host, priority.String(), false)
+			test.That(t, err, test.ShouldBeNil)
+			defer func() {
+				cancel()
+				<-answersDone
+			}()
+		}
+
+		for i := len(priorities) - 1; i >= 0; i-- {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, offer.SDP(), test.ShouldEqual, priorities[i].String())
+			test.That(t, offer.AnswererDone(context.Background()), test.ShouldBeNil)
+		}
+		test.That(t, first.AnswererDone(context.Background()), test.ShouldBeNil)
+	})
+
+	t.Run("offers should stay in order within their priority class", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		offerCh := make(chan rpc.WebRTCCallOfferExchange, 1)
+		recvErrCh := make(chan error, 1)
+		go func() {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			if err != nil {
+				recvErrCh <- err
+				return
+			}
+			offerCh <- offer
+		}()
+		waitForAnswererOnline(host)
+
+		// keep the first offer in progress so the host stays online while the rest wait.
+		_, _, firstDone, firstCancel, err := callerQueue.SendOfferInit(context.Background(), host, "first", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			firstCancel()
+			<-firstDone
+		}()
+		var first rpc.WebRTCCallOfferExchange
+		select {
+		case first = <-offerCh:
+		case err := <-recvErrCh:
+			t.Fatal(err)
+		}
+
+		sent := []struct {
+			sdp      string
+			priority rpc.WebRTCCallPriority
+		}{
+			{"normal-1", rpc.WebRTCCallPriorityNormal},
+			{"normal-2", rpc.WebRTCCallPriorityNormal},
+			{"high", rpc.WebRTCCallPriorityHigh},
+		}
+		for _, offer := range sent {
+			ctx := rpc.ContextWithWebRTCCallPriority(context.Background(), offer.priority)
+			_, _, answersDone, cancel, err := callerQueue.SendOfferInit(ctx, host, offer.sdp, false)
+			test.That(t, err, test.ShouldBeNil)
+			defer func() {
+				cancel()
+				<-answersDone
+			}()
+		}
+
+		// taking the offer of a higher class does not pass over the oldest of a lower one.
+		for _, sdp := range []string{"high", "normal-1", "normal-2"} {
+			offer, err := answererQueue.RecvOffer(context.Background(), []string{host})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, offer.SDP(), test.ShouldEqual, sdp)
+			test.That(t, offer.AnswererDone(context.Background()), test.ShouldBeNil)
+		}
+		test.That(t, first.AnswererDone(context.Background()), test.ShouldBeNil)
+	})
+
+	t.Run("updating an inactive offer should fail", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setup(t)
+
+		host := uuid.NewString()
+		unknownUUID := uuid.NewString()
+		cand := newCandidate("c1")
+		err := callerQueue.SendOfferUpdate(context.Background(), host, unknownUUID, *cand)
+		test.That(t, errors.Is(err, rpc.ErrInactiveOffer), test.ShouldBeTrue)
+		err = callerQueue.SendOfferDone(context.Background(), host, unknownUUID)
+		test.That(t, errors.Is(err, rpc.ErrInactiveOffer), test.ShouldBeTrue)
+		err = callerQueue.SendOfferError(context.Background(), host, unknownUUID, errors.New("whoops"))
+		test.That(t, errors.Is(err, rpc.ErrInactiveOffer), test.ShouldBeTrue)
+
+		// an active offer is still inactive when addressed through the wrong host.
+		answerCtx, answerCancel := context.WithCancel(context.Background())
+		defer answerCancel()
+		offerCh := make(chan rpc.WebRTCCallOfferExchange, 1)
+		go func() {
+			offer, err := answererQueue.RecvOffer(answerCtx, []string{host})
+			if err == nil {
+				offerCh <- offer
+			}
+		}()
+		waitForAnswererOnline(host)
+
+		newUUID, _, answersDone, cancel, err := callerQueue.SendOfferInit(context.Background(), host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			cancel()
+			<-answersDone
+		}()
+		err = callerQueue.SendOfferUpdate(context.Background(), uuid.NewString(), newUUID, *cand)
+		test.That(t, errors.Is(err, rpc.ErrInactiveOffer), test.ShouldBeTrue)
+		err = callerQueue.SendOfferDone(context.Background(), uuid.NewString(), newUUID)
+		test.That(t, errors.Is(err, rpc.ErrInactiveOffer), test.ShouldBeTrue)
+	})
+
+	t.Run("receiving from a host not sent to should not work", func(t *testing.T) {
+		callerQueue, answererQueue, waitForAnswererOnline := setupWithOfferDeadline(t, 10*time.Second)
+		host := uuid.NewString()
+
+		recvErrCh := make(chan error)
+		done := make(chan struct{})
+		defer func() { <-done }()
+		go func() {
+			// should be ample time in tests
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := answererQueue.RecvOffer(ctx, []string{uuid.NewString()})
+			recvErrCh <- err
+			close(done)
+		}()
+
+		// We need to have an answerer online to handle this host before sending the offer so it doesn't immediately fail, but we don't care what
+		// happens to the offer.
+		answerCtx, answerCancel := context.WithCancel(context.Background())
+		defer answerCancel()
+		sdpCh := make(chan string, 1)
+		go func() {
+			offer, err := answererQueue.RecvOffer(answerCtx, []string{host})
+			if err == nil {
+				sdpCh <- offer.SDP()
+			}
+		}()
+		waitForAnswererOnline(host)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _, ansCtx, _, err := callerQueue.SendOfferInit(ctx, host, "hello", false)
+		test.That(t, err, test.ShouldBeNil)
+		<-ansCtx
+		recvErr := <-recvErrCh
+		test.That(t, recvErr, test.ShouldNotBeNil)
+		test.That(t, recvErr, test.ShouldWrap, context.DeadlineExceeded)
+		select {
+		case sdp := <-sdpCh:
+			test.That(t, sdp, test.ShouldEqual, "hello")
+		default:
+		}
+	})
+
+	t.Run("host statuses should report answerers waiting for calls", func(t *testing.T) {
+		_, answererQueue, waitForAnswererOnline := setup(t)
+		directory, ok := answererQueue.(rpc.WebRTCHostDirectory)
+		if !ok {
+			t.Skip("queue does not implement rpc.WebRTCHostDirectory")
+		}
+		host := uuid.NewString()
+		offlineHost := uuid.NewString()
+
+		presence := rpc.NewWebRTCAnswererPresence("answerer/1.0")
+		answerCtx, answerCancel := context.WithCancel(rpc.ContextWithWebRTCAnswererPresence(context.Background(), presence))
+		defer answerCancel()
+		recvDone := make(chan struct{})
+		go func() {
+			defer close(recvDone)
+			//nolint:errcheck
+			answererQueue.RecvOffer(answerCtx, []string{host})
+		}()
+		waitForAnswererOnline(host)
+
+		listed := func(tb testing.TB) (rpc.WebRTCHostStatus, bool) {
+			tb.Helper()
+			statuses, err := directory.HostStatuses(context.Background())
+			test.That(tb, err, test.ShouldBeNil)
+			for _, status := range statuses {
+				if status.Host == host {
+					return status, true
+				}
+			}
+			return rpc.WebRTCHostStatus{}, false
+		}
+		checkOnline := func() {
+			t.Helper()
+			testutils.WaitForAssertion(t, func(tb testing.TB) {
+				tb.Helper()
+				statuses, err := directory.HostStatuses(context.Background(), host, offlineHost)
+				test.That(tb, err, test.ShouldBeNil)
+				test.That(tb, statuses, test.ShouldHaveLength, 2)
+				test.That(tb, statuses[0].Host, test.ShouldEqual, host)
+				test.That(tb, statuses[0].Answerers, test.ShouldEqual, 1)
+				test.That(tb, statuses[0].AnswererVersion, test.ShouldEqual, "answerer/1.0")
+				test.That(tb, statuses[0].LastHeartbeat, test.ShouldHappenWithin, time.Millisecond, presence.LastHeartbeat())
+				test.That(tb, statuses[1], test.ShouldResemble, rpc.WebRTCHostStatus{Host: offlineHost})
+
+				status, ok := listed(tb)
+				test.That(tb, ok, test.ShouldBeTrue)
+				test.That(tb, status, test.ShouldResemble, statuses[0])
+			})
+		}
+		checkOnline()
+
+		presence.Heartbeat()
+		checkOnline()
+
+		answerCancel()
+		<-recvDone
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			statuses, err := directory.HostStatuses(context.Background(), host)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, statuses, test.ShouldHaveLength, 1)
+			test.That(tb, statuses[0].Answerers, test.ShouldEqual, 0)
+			test.That(tb, statuses[0].LastHeartbeat, test.ShouldHappenWithin, time.Millisecond, presence.LastHeartbeat())
+			test.That(tb, statuses[0].AnswererVersion, test.ShouldEqual, "answerer/1.0")
+			_, ok := listed(tb)
+			test.That(tb, ok, test.ShouldBeFalse)
+		})
+	})
+}
+
+func newCandidate(candidate string) *webrtc.ICECandidateInit {
+	sdpMid := "sdpmid"
+	sdpMLineIndex := uint16(1)
+	usernameFragment := "ufrag"
+	return &webrtc.ICECandidateInit{
+		Candidate:        candidate,
+		SDPMid:           &sdpMid,
+		SDPMLineIndex:    &sdpMLineIndex,
+		UsernameFragment: &usernameFragment,
+	}
+}
@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/viamrobotics/webrtc/v3"
 )
@@ -15,6 +16,10 @@ const (
 	ctxKeyPeerConnection
 	ctxKeyAuthEntity
 	ctxKeyAuthClaims // all jwt claims
+	ctxKeyRoundTripTimer
+	ctxKeyAuditCall
+	ctxKeyAnswererPresence
+	ctxKeyCallPriority
 )
 
 // contextWithHost attaches a host name to the given context.
@@ -59,6 +64,56 @@ func ContextPeerConnection(ctx context.Context) (*webrtc.PeerConnection, bool) {
 	return pc.(*webrtc.PeerConnection), true
 }
 
+// A roundTripTimer knows the smoothed round trip time to the peer of a connection.
+type roundTripTimer interface {
+	roundTripTime() (rtt, jitter time.Duration, ok bool)
+}
+
+// contextWithRoundTripTimer attaches what measures the round trip time of the connection a
+// request came in on to the given context.
+func contextWithRoundTripTimer(ctx context.Context, timer roundTripTimer) context.Context {
+	return context.WithValue(ctx, ctxKeyRoundTripTimer, timer)
+}
+
+// contextRoundTripTime returns the smoothed round trip time, and its variation, of the connection
+// a request came in on, if it is known.
+func contextRoundTripTime(ctx context.Context) (time.Duration, time.Duration, bool) {
+	timer, ok := ctx.Value(ctxKeyRoundTripTimer).(roundTripTimer)
+	if !ok {
+		return 0, 0, false
+	}
+	return timer.roundTripTime()
+}
+
+// ContextWithWebRTCAnswererPresence attaches the presence of the answerer waiting for calls to
+// the given context.
+func ContextWithWebRTCAnswererPresence(ctx context.Context, presence *WebRTCAnswererPresence) context.Context {
+	return context.WithValue(ctx, ctxKeyAnswererPresence, presence)
+}
+
+// ContextWebRTCAnswererPresence returns the presence of the answerer waiting for calls, if set.
+// The signaling server sets it on the context it receives offers with.
+func ContextWebRTCAnswererPresence(ctx context.Context) (*WebRTCAnswererPresence, bool) {
+	presence, ok := ctx.Value(ctxKeyAnswererPresence).(*WebRTCAnswererPresence)
+	return presence, ok
+}
+
+// ContextWithWebRTCCallPriority attaches the priority class of a call to the given context.
+func ContextWithWebRTCCallPriority(ctx context.Context, priority WebRTCCallPriority) context.Context {
+	return context.WithValue(ctx, ctxKeyCallPriority, priority)
+}
+
+// ContextWebRTCCallPriority returns the priority class of a call, which is
+// WebRTCCallPriorityNormal if not set. The signaling server sets it on the context it sends
+// offers with.
+func ContextWebRTCCallPriority(ctx context.Context) WebRTCCallPriority {
+	priority, ok := ctx.Value(ctxKeyCallPriority).(WebRTCCallPriority)
+	if !ok {
+		return WebRTCCallPriorityNormal
+	}
+	return priority
+}
+
 // ContextWithAuthEntity attaches an entity (e.g. a user) for an authenticated context to the given context.
 func ContextWithAuthEntity(ctx context.Context, authEntity EntityInfo) context.Context {
 	return context.WithValue(ctx, ctxKeyAuthEntity, authEntity)
@@ -86,3 +141,15 @@ func MustContextAuthEntity(ctx context.Context) EntityInfo {
 	}
 	return authEntity
 }
+
+// contextWithAuthClaims attaches the claims an entity authenticated with to the given context.
+func contextWithAuthClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, ctxKeyAuthClaims, claims)
+}
+
+// contextAuthClaims returns the claims the entity of this authentication context authenticated
+// with, if any.
+func contextAuthClaims(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(ctxKeyAuthClaims).(Claims)
+	return claims, ok
+}
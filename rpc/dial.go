@@ -20,7 +20,7 @@ import (
 
 // Dial attempts to make the most convenient connection to the given address. It attempts to connect
 // via WebRTC if a signaling server is detected or provided. Otherwise it attempts to connect directly.
-// TODO(GOUT-7): figure out decent way to handle reconnect on connection termination.
+// The returned connection is not redialed if it dies; see DialReconnecting for that.
 func Dial(ctx context.Context, address string, logger utils.ZapCompatibleLogger, opts ...DialOption) (ClientConn, error) {
 	var dOpts dialOptions
 	for _, opt := range opts {
@@ -114,7 +114,7 @@ func dial(
 
 	var isJustDomain bool
 	switch {
-	case strings.HasPrefix(address, "unix:"):
+	case isUnixSocketAddress(address):
 		dOpts.mdnsOptions.Disable = true
 		dOpts.webrtcOpts.Disable = true
 		dOpts.insecure = true
@@ -408,6 +408,7 @@ func dialMulticastDNS(
 
 	if dOpts.mdnsOptions.RemoveAuthCredentials {
 		dOpts.creds = Credentials{}
+		dOpts.oauth2 = nil
 		dOpts.authEntity = ""
 		dOpts.externalAuthToEntity = ""
 		dOpts.externalAuthMaterial = ""
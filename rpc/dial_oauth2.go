@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// An OAuth2DevicePrompt shows the user where to go and what code to enter to complete a device
+// authorization grant.
+type OAuth2DevicePrompt func(ctx context.Context, auth *oauth2.DeviceAuthResponse) error
+
+// DefaultOAuth2DevicePrompt writes the verification URI and user code to stderr.
+func DefaultOAuth2DevicePrompt(ctx context.Context, auth *oauth2.DeviceAuthResponse) error {
+	if auth.VerificationURIComplete != "" {
+		_, err := fmt.Fprintf(os.Stderr, "To authenticate, visit %s and confirm the code %s\n",
+			auth.VerificationURIComplete, auth.UserCode)
+		return err
+	}
+	_, err := fmt.Fprintf(os.Stderr, "To authenticate, visit %s and enter the code %s\n",
+		auth.VerificationURI, auth.UserCode)
+	return err
+}
+
+// An oauth2Grant gets access tokens from an OAuth2 provider for perRPCJWTCredentials. It caches
+// the token it gets and refreshes it before it expires, running the grant again if it cannot.
+type oauth2Grant struct {
+	// key identifies the provider and client in connection cache keys.
+	key string
+	// authorize runs the grant to get a new token.
+	authorize func(ctx context.Context) (*oauth2.Token, error)
+	// refresher returns a source of tokens replacing the given one once it expires.
+	refresher func(token *oauth2.Token) oauth2.TokenSource
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+func newOAuth2ClientCredentialsGrant(config clientcredentials.Config) *oauth2Grant {
+	return &oauth2Grant{
+		key: strings.Join(append([]string{
+			"client_credentials", config.TokenURL, config.ClientID, config.ClientSecret,
+		}, config.Scopes...), "\x00"),
+		authorize: config.Token,
+		refresher: func(token *oauth2.Token) oauth2.TokenSource {
+			// there are no refresh tokens in this grant; expired tokens are replaced with new ones.
+			return config.TokenSource(context.Background())
+		},
+	}
+}
+
+func newOAuth2DeviceAuthorizationGrant(
+	config oauth2.Config,
+	prompt OAuth2DevicePrompt,
+	opts ...oauth2.AuthCodeOption,
+) *oauth2Grant {
+	if prompt == nil {
+		prompt = DefaultOAuth2DevicePrompt
+	}
+	return &oauth2Grant{
+		key: strings.Join(append([]string{
+			"device_code", config.Endpoint.DeviceAuthURL, config.Endpoint.TokenURL, config.ClientID,
+		}, config.Scopes...), "\x00"),
+		authorize: func(ctx context.Context) (*oauth2.Token, error) {
+			auth, err := config.DeviceAuth(ctx, opts...)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to start device authorization")
+			}
+			if err := prompt(ctx, auth); err != nil {
+				return nil, err
+			}
+			token, err := config.DeviceAccessToken(ctx, auth, opts...)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to complete device authorization")
+			}
+			return token, nil
+		},
+		refresher: func(token *oauth2.Token) oauth2.TokenSource {
+			return config.TokenSource(context.Background(), token)
+		},
+	}
+}
+
+// accessToken returns a valid access token, running the grant if there is none yet or the last
+// one could not be refreshed.
+func (grant *oauth2Grant) accessToken(ctx context.Context) (string, error) {
+	grant.mu.Lock()
+	defer grant.mu.Unlock()
+	if grant.source != nil {
+		token, err := grant.source.Token()
+		if err == nil {
+			return token.AccessToken, nil
+		}
+		grant.source = nil
+	}
+	token, err := grant.authorize(ctx)
+	if err != nil {
+		return "", err
+	}
+	grant.source = oauth2.ReuseTokenSource(token, grant.refresher(token))
+	return token.AccessToken, nil
+}
@@ -0,0 +1,233 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang-jwt/jwt/v4"
+	"go.viam.com/test"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+)
+
+// fakeOAuth2Provider issues access tokens for the client credentials, device authorization and
+// refresh token grants.
+type fakeOAuth2Provider struct {
+	t        *testing.T
+	privKey  ed25519.PrivateKey
+	audience string
+
+	mu        sync.Mutex
+	grants    map[string]int
+	expiresIn int
+	approved  bool
+}
+
+func (p *fakeOAuth2Provider) grantCount(grantType string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.grants[grantType]
+}
+
+func (p *fakeOAuth2Provider) writeJSON(w http.ResponseWriter, code int, resp map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	test.That(p.t, json.NewEncoder(w).Encode(resp), test.ShouldBeNil)
+}
+
+func (p *fakeOAuth2Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	test.That(p.t, r.ParseForm(), test.ShouldBeNil)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if r.URL.Path == "/device" {
+		p.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": "https://example.com/activate",
+			"interval":         1,
+			"expires_in":       60,
+		})
+		return
+	}
+
+	grantType := r.PostForm.Get("grant_type")
+	var subject string
+	switch grantType {
+	case "client_credentials":
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "client-id" || clientSecret != "client-secret" {
+			p.writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"error": "invalid_client"})
+			return
+		}
+		subject = clientID
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		if !p.approved {
+			p.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "authorization_pending"})
+			return
+		}
+		subject = "device-user"
+	case "refresh_token":
+		if r.PostForm.Get("refresh_token") != "refresh-token" {
+			p.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid_grant"})
+			return
+		}
+		subject = "device-user"
+	default:
+		p.writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": "unsupported_grant_type"})
+		return
+	}
+	p.grants[grantType]++
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, JWTClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{p.audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		AuthCredentialsType: CredentialsTypeExternal,
+	}).SignedString(p.privKey)
+	test.That(p.t, err, test.ShouldBeNil)
+	resp := map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   p.expiresIn,
+	}
+	if grantType != "client_credentials" {
+		resp["refresh_token"] = "refresh-token"
+	}
+	p.writeJSON(w, http.StatusOK, resp)
+}
+
+func TestDialOAuth2(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+
+	audience := "oauth2-test"
+	provider := &fakeOAuth2Provider{t: t, privKey: privKey, audience: audience, grants: map[string]int{}}
+	providerServer := httptest.NewServer(provider)
+	defer providerServer.Close()
+
+	rpcServer, err := NewServer(
+		logger,
+		WithAuthAudience(audience),
+		WithExternalAuthEd25519PublicKeyTokenVerifier(pubKey),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	echoServer := &echoserver.Server{
+		MustContextAuthEntity: func(ctx context.Context) echoserver.RPCEntityInfo {
+			ent := MustContextAuthEntity(ctx)
+			return echoserver.RPCEntityInfo{
+				Entity: ent.Entity,
+				Data:   ent.Data,
+			}
+		},
+	}
+	echoServer.SetAuthorized(true)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		echoServer,
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	echoTwice := func(t *testing.T, opt DialOption) {
+		t.Helper()
+		conn, err := DialDirectGRPC(context.Background(), listener.Addr().String(), logger, WithInsecure(), opt)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		for i := 0; i < 2; i++ {
+			_, err = pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+			test.That(t, err, test.ShouldBeNil)
+		}
+	}
+
+	t.Run("client credentials", func(t *testing.T) {
+		echoServer.SetExpectedAuthEntity("client-id")
+		config := clientcredentials.Config{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     providerServer.URL + "/token",
+			AuthStyle:    oauth2.AuthStyleInHeader,
+		}
+
+		// tokens are cached until they are about to expire.
+		provider.mu.Lock()
+		provider.expiresIn = 3600
+		provider.mu.Unlock()
+		echoTwice(t, WithOAuth2ClientCredentials(config))
+		test.That(t, provider.grantCount("client_credentials"), test.ShouldEqual, 1)
+
+		provider.mu.Lock()
+		provider.expiresIn = 1
+		provider.mu.Unlock()
+		echoTwice(t, WithOAuth2ClientCredentials(config))
+		test.That(t, provider.grantCount("client_credentials"), test.ShouldEqual, 3)
+
+		config.ClientSecret = "wrong"
+		conn, err := DialDirectGRPC(context.Background(), listener.Addr().String(), logger,
+			WithInsecure(), WithOAuth2ClientCredentials(config))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		_, err = pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid_client")
+	})
+
+	t.Run("device authorization", func(t *testing.T) {
+		echoServer.SetExpectedAuthEntity("device-user")
+		provider.mu.Lock()
+		provider.expiresIn = 1
+		provider.mu.Unlock()
+
+		var prompts []string
+		echoTwice(t, WithOAuth2DeviceAuthorization(
+			oauth2.Config{
+				ClientID: "cli",
+				Endpoint: oauth2.Endpoint{
+					DeviceAuthURL: providerServer.URL + "/device",
+					TokenURL:      providerServer.URL + "/token",
+				},
+			},
+			func(ctx context.Context, auth *oauth2.DeviceAuthResponse) error {
+				prompts = append(prompts, auth.UserCode)
+				provider.mu.Lock()
+				provider.approved = true
+				provider.mu.Unlock()
+				return nil
+			},
+		))
+		// the user is only prompted once and the expired token is refreshed afterwards.
+		test.That(t, prompts, test.ShouldResemble, []string{"ABCD-EFGH"})
+		test.That(t, provider.grantCount("urn:ietf:params:oauth:grant-type:device_code"), test.ShouldEqual, 1)
+		test.That(t, provider.grantCount("refresh_token"), test.ShouldEqual, 1)
+	})
+}
@@ -2,8 +2,12 @@ package rpc
 
 import (
 	"crypto/tls"
+	"os"
+	"strconv"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/stats"
 )
@@ -17,6 +21,9 @@ type dialOptions struct {
 	// tlsConfig is the TLS config to use for any secured connections.
 	tlsConfig *tls.Config
 
+	// clientCert is presented to servers that authenticate clients by certificate.
+	clientCert *tls.Certificate
+
 	// allowInsecureDowngrade determines if it is acceptable to downgrade
 	// an insecure connection if detected. This is only used when credentials
 	// are not present.
@@ -34,6 +41,9 @@ type dialOptions struct {
 	// credentials.
 	creds Credentials
 
+	// oauth2 gets access tokens from an OAuth2 provider instead of authenticating with creds.
+	oauth2 *oauth2Grant
+
 	// webrtcOpts control how WebRTC is utilized in a dial attempt.
 	webrtcOpts    DialWebRTCOptions
 	webrtcOptsSet bool
@@ -61,6 +71,9 @@ type dialOptions struct {
 	// stats monitoring on the connections.
 	statsHandler stats.Handler
 
+	// compressor names the compressor calls use by default.
+	compressor string
+
 	// interceptors
 	unaryInterceptor  grpc.UnaryClientInterceptor
 	streamInterceptor grpc.StreamClientInterceptor
@@ -115,21 +128,70 @@ func WithInsecure() DialOption {
 // WithCredentials returns a DialOption which sets the credentials to use for
 // authenticating the request. The associated entity is assumed to be the
 // address of the server. This is mutually exclusive with
-// WithEntityCredentials.
+// WithEntityCredentials and the OAuth2 options.
 func WithCredentials(creds Credentials) DialOption {
 	return newFuncDialOption(func(o *dialOptions) {
 		o.authEntity = ""
 		o.creds = creds
+		o.oauth2 = nil
 	})
 }
 
 // WithEntityCredentials returns a DialOption which sets the entity credentials
 // to use for authenticating the request. This is mutually exclusive with
-// WithCredentials.
+// WithCredentials and the OAuth2 options.
 func WithEntityCredentials(entity string, creds Credentials) DialOption {
 	return newFuncDialOption(func(o *dialOptions) {
 		o.authEntity = entity
 		o.creds = creds
+		o.oauth2 = nil
+	})
+}
+
+// WithUnixPeerCredentials returns a DialOption which authenticates as the user this process
+// runs as by way of its unix socket peer credentials. It is for "unix:" addresses served by
+// a server using MakeUnixPeerAuthHandler.
+func WithUnixPeerCredentials() DialOption {
+	return WithEntityCredentials(strconv.Itoa(os.Getuid()), Credentials{Type: CredentialsTypeUnixPeer})
+}
+
+// WithOAuth2ClientCredentials returns a DialOption which gets access tokens from an
+// OAuth2 provider using the client credentials grant and sends them with every request
+// instead of authenticating to the server. Tokens are cached and replaced before they
+// expire. The server is expected to verify them as external tokens (e.g. with
+// WithExternalAuthOIDCTokenVerifier); an audience the provider should issue tokens for
+// can be requested with EndpointParams. When used with WithExternalAuth, the tokens are
+// used to AuthenticateTo the external auth service instead. This is mutually exclusive
+// with WithCredentials, WithEntityCredentials and WithOAuth2DeviceAuthorization.
+func WithOAuth2ClientCredentials(config clientcredentials.Config) DialOption {
+	// the grant is shared by every dial made with the option so that they share its tokens.
+	grant := newOAuth2ClientCredentialsGrant(config)
+	return newFuncDialOption(func(o *dialOptions) {
+		o.authEntity = ""
+		o.creds = Credentials{}
+		o.oauth2 = grant
+	})
+}
+
+// WithOAuth2DeviceAuthorization returns a DialOption which gets access tokens from an
+// OAuth2 provider using the device authorization grant, meant for clients without a
+// browser. The first request made prompts the user, with DefaultOAuth2DevicePrompt if
+// prompt is nil, to authorize the device elsewhere and waits until they do or the
+// request's context is done. Tokens are then refreshed with the refresh token issued, if
+// any, and the user is prompted again otherwise. opts are passed to the provider with
+// both the authorization and token requests (e.g. oauth2.SetAuthURLParam("audience", ...)).
+// Tokens are used like in WithOAuth2ClientCredentials, with which it is mutually exclusive.
+func WithOAuth2DeviceAuthorization(
+	config oauth2.Config,
+	prompt OAuth2DevicePrompt,
+	opts ...oauth2.AuthCodeOption,
+) DialOption {
+	// the grant is shared by every dial made with the option so that they share its tokens.
+	grant := newOAuth2DeviceAuthorizationGrant(config, prompt, opts...)
+	return newFuncDialOption(func(o *dialOptions) {
+		o.authEntity = ""
+		o.creds = Credentials{}
+		o.oauth2 = grant
 	})
 }
 
@@ -189,6 +251,15 @@ func WithTLSConfig(config *tls.Config) DialOption {
 	})
 }
 
+// WithClientCertificate returns a DialOption which presents the given certificate to servers
+// that authenticate clients by certificate (see WithMTLSAuthHandler). It is used during the TLS
+// handshake of direct connections and as the DTLS certificate of WebRTC connections.
+func WithClientCertificate(cert tls.Certificate) DialOption {
+	return newFuncDialOption(func(o *dialOptions) {
+		o.clientCert = &cert
+	})
+}
+
 // WithWebRTCOptions returns a DialOption which sets the WebRTC options
 // to use if the dialer tries to establish a WebRTC connection.
 func WithWebRTCOptions(webrtcOpts DialWebRTCOptions) DialOption {
@@ -251,6 +322,18 @@ func WithDialStatsHandler(handler stats.Handler) DialOption {
 	})
 }
 
+// WithCompressor returns a DialOption that compresses messages of all calls with the named
+// compressor, like grpc.UseCompressor does per call. The compressor must be registered with
+// google.golang.org/grpc/encoding; "gzip", "zstd", and "snappy" always are. Over WebRTC,
+// responses are compressed once the server sees the compressor is accepted and requests are
+// compressed once the server has said it supports the compressor, so servers without
+// compression support continue to work.
+func WithCompressor(name string) DialOption {
+	return newFuncDialOption(func(o *dialOptions) {
+		o.compressor = name
+	})
+}
+
 // WithUnaryClientInterceptor returns a DialOption that specifies the interceptor for
 // unary RPCs.
 func WithUnaryClientInterceptor(interceptor grpc.UnaryClientInterceptor) DialOption {
@@ -0,0 +1,459 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/viamrobotics/webrtc/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils"
+)
+
+// ErrClientConnClosed is returned by calls made on a ReconnectingClientConn after it has been closed.
+var ErrClientConnClosed = errors.New("client connection closed")
+
+// ConnectionState is the state of a ReconnectingClientConn.
+type ConnectionState int
+
+const (
+	// ConnectionStateConnected indicates there is an established connection for calls to use.
+	ConnectionStateConnected ConnectionState = iota
+	// ConnectionStateReconnecting indicates the previous connection died and is being redialed.
+	// Calls made in this state wait for the new connection.
+	ConnectionStateReconnecting
+	// ConnectionStateClosed indicates the connection was closed by the caller. It is terminal.
+	ConnectionStateClosed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateConnected:
+		return "connected"
+	case ConnectionStateReconnecting:
+		return "reconnecting"
+	case ConnectionStateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryPolicy controls whether a failed unary RPC is retried on a ReconnectingClientConn. Only
+// idempotent methods should be given a policy that retries.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts made, including the first. Values below 2
+	// disable retries.
+	MaxAttempts int
+
+	// RetryableCodes are the status codes that are retried. If empty, only codes.Unavailable is
+	// retried. Calls that fail because the connection died are always treated as
+	// codes.Unavailable.
+	RetryableCodes []codes.Code
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	code := codes.Unavailable
+	if !isDisconnectedError(err) {
+		code = status.Code(err)
+	}
+	if len(p.RetryableCodes) == 0 {
+		return code == codes.Unavailable
+	}
+	return slices.Contains(p.RetryableCodes, code)
+}
+
+// ReconnectOptions control how a ReconnectingClientConn redials and retries.
+type ReconnectOptions struct {
+	// InitialBackoff is the delay before the first redial attempt and between retries of a unary
+	// RPC. It doubles after each failed attempt. Defaults to 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between redial attempts and between retries. Defaults to 5s.
+	MaxBackoff time.Duration
+
+	// DefaultRetryPolicy applies to unary RPCs without an entry in MethodRetryPolicies. The zero
+	// value does not retry.
+	DefaultRetryPolicy RetryPolicy
+
+	// MethodRetryPolicies maps full method names (e.g. "/proto.rpc.examples.echo.v1.EchoService/Echo")
+	// to the policy used for that method.
+	MethodRetryPolicies map[string]RetryPolicy
+
+	// OnStateChange, if set, is called every time the connection state changes. Calls are made
+	// one at a time, in order, from a goroutine of their own and without any locks held, so they
+	// may call back into the connection, including Close. They should not block for long;
+	// changes made while a call is still running are dropped once too many queue up. The last
+	// call is always for ConnectionStateClosed, which may be made after Close returns.
+	OnStateChange func(state ConnectionState)
+}
+
+const (
+	defaultReconnectInitialBackoff = 100 * time.Millisecond
+	defaultReconnectMaxBackoff     = 5 * time.Second
+)
+
+// reconnectHealthCheckInterval is how often the current connection's PeerConnection or gRPC
+// connection is inspected for failure.
+var reconnectHealthCheckInterval = time.Second
+
+// A ReconnectingClientConn is a ClientConn that redials whenever its underlying connection dies.
+// Callers keep using the same ReconnectingClientConn across reconnects. Unary RPCs are retried
+// according to the configured RetryPolicy; streams are never retried.
+type ReconnectingClientConn struct {
+	dial    func(ctx context.Context) (ClientConn, error)
+	opts    ReconnectOptions
+	logger  utils.ZapCompatibleLogger
+	workers *utils.StoppableWorkers
+
+	mu    sync.Mutex
+	conn  ClientConn
+	state ConnectionState
+	// connected is closed when the connection moves out of the reconnecting state.
+	connected chan struct{}
+
+	redialNeeded chan struct{}
+	stateChanges chan ConnectionState
+	// closed is closed by Close to tell the notify loop to deliver the last state changes.
+	closed chan struct{}
+}
+
+var _ ClientConn = (*ReconnectingClientConn)(nil)
+
+// DialReconnecting dials the given address exactly like Dial and returns a ClientConn that
+// transparently redials with the same options (mDNS, WebRTC, direct gRPC) whenever the
+// connection dies. The initial dial is not retried.
+func DialReconnecting(
+	ctx context.Context,
+	address string,
+	logger utils.ZapCompatibleLogger,
+	reconnectOpts ReconnectOptions,
+	opts ...DialOption,
+) (*ReconnectingClientConn, error) {
+	return newReconnectingClientConn(ctx, func(ctx context.Context) (ClientConn, error) {
+		return Dial(ctx, address, logger, opts...)
+	}, reconnectOpts, logger)
+}
+
+func newReconnectingClientConn(
+	ctx context.Context,
+	dial func(ctx context.Context) (ClientConn, error),
+	opts ReconnectOptions,
+	logger utils.ZapCompatibleLogger,
+) (*ReconnectingClientConn, error) {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaultReconnectInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaultReconnectMaxBackoff
+	}
+
+	conn, err := dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Redials happen long after the caller's context has ended, but should still observe any of its
+	// values (e.g. a Dialer).
+	redialCtx := context.WithoutCancel(ctx)
+	connected := make(chan struct{})
+	close(connected)
+	rc := &ReconnectingClientConn{
+		dial: func(ctx context.Context) (ClientConn, error) {
+			ctx, cancel := utils.MergeContext(redialCtx, ctx)
+			defer cancel()
+			return dial(ctx)
+		},
+		opts:         opts,
+		logger:       logger,
+		conn:         conn,
+		state:        ConnectionStateConnected,
+		connected:    connected,
+		redialNeeded: make(chan struct{}, 1),
+		stateChanges: make(chan ConnectionState, 16),
+		closed:       make(chan struct{}),
+	}
+	rc.workers = utils.NewBackgroundStoppableWorkers(rc.redialLoop)
+	// The notify loop is not one of the workers so that Close, which stops them, can be called
+	// from OnStateChange.
+	if opts.OnStateChange != nil {
+		utils.PanicCapturingGo(rc.notifyLoop)
+	}
+	return rc, nil
+}
+
+// State returns the current state of the connection.
+func (rc *ReconnectingClientConn) State() ConnectionState {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.state
+}
+
+// setStateLocked must be called with `rc.mu` held.
+func (rc *ReconnectingClientConn) setStateLocked(state ConnectionState) {
+	if rc.state == state {
+		return
+	}
+	rc.state = state
+	// the notify loop delivers the close itself once every other change is delivered.
+	if rc.opts.OnStateChange == nil || state == ConnectionStateClosed {
+		return
+	}
+	select {
+	case rc.stateChanges <- state:
+	default:
+		rc.logger.Warnw("dropping connection state change notification; OnStateChange is too slow", "state", state)
+	}
+}
+
+func (rc *ReconnectingClientConn) notifyLoop() {
+	for {
+		select {
+		case state := <-rc.stateChanges:
+			rc.opts.OnStateChange(state)
+		case <-rc.closed:
+			// Nothing changes the state once closed, so what is queued is all that is left.
+			for {
+				select {
+				case state := <-rc.stateChanges:
+					rc.opts.OnStateChange(state)
+				default:
+					rc.opts.OnStateChange(ConnectionStateClosed)
+					return
+				}
+			}
+		}
+	}
+}
+
+// currentConn returns the established connection, waiting for a reconnect if one is in progress.
+func (rc *ReconnectingClientConn) currentConn(ctx context.Context) (ClientConn, error) {
+	for {
+		rc.mu.Lock()
+		conn, state, connected := rc.conn, rc.state, rc.connected
+		rc.mu.Unlock()
+		switch state {
+		case ConnectionStateConnected:
+			return conn, nil
+		case ConnectionStateClosed:
+			return nil, ErrClientConnClosed
+		case ConnectionStateReconnecting:
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-connected:
+		}
+	}
+}
+
+// connDied moves to the reconnecting state and wakes up the redial loop, unless `conn` has already
+// been replaced.
+func (rc *ReconnectingClientConn) connDied(conn ClientConn, reason error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.state != ConnectionStateConnected || rc.conn != conn {
+		return
+	}
+	rc.logger.Infow("connection died; reconnecting", "reason", reason)
+	rc.connected = make(chan struct{})
+	rc.setStateLocked(ConnectionStateReconnecting)
+	select {
+	case rc.redialNeeded <- struct{}{}:
+	default:
+	}
+}
+
+// connDead reports whether the connection is backed by a PeerConnection that will not recover or
+// by a gRPC connection that has failed or shut down.
+func connDead(conn ClientConn) bool {
+	if pc := conn.PeerConn(); pc != nil {
+		state := pc.ConnectionState()
+		return state == webrtc.PeerConnectionStateFailed || state == webrtc.PeerConnectionStateClosed
+	}
+	state, ok := grpcConnState(conn)
+	return ok && (state == connectivity.TransientFailure || state == connectivity.Shutdown)
+}
+
+// grpcConnState returns the state of the gRPC connection backing a direct gRPC connection,
+// looking through the wrappers dialing puts around it.
+func grpcConnState(conn ClientConn) (connectivity.State, bool) {
+	for {
+		switch c := conn.(type) {
+		case GrpcOverHTTPClientConn:
+			return c.GetState(), true
+		case clientConnRPCAuthenticator:
+			conn = c.ClientConn
+		case *clientConnWithCloseFunc:
+			conn = c.ClientConn
+		case *reffedConn:
+			conn = c.ClientConn
+		default:
+			return connectivity.Idle, false
+		}
+	}
+}
+
+// isDisconnectedError reports whether a call failed because the connection it was made on died
+// or is going away. gRPC fails calls with codes.Unavailable when it cannot reach the server.
+func isDisconnectedError(err error) bool {
+	return errors.Is(err, ErrDisconnected) || errors.Is(err, ErrGoingAway) || errors.Is(err, io.ErrClosedPipe) ||
+		status.Code(err) == codes.Unavailable
+}
+
+func (rc *ReconnectingClientConn) redialLoop(ctx context.Context) {
+	ticker := time.NewTicker(reconnectHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rc.mu.Lock()
+			conn := rc.conn
+			rc.mu.Unlock()
+			if conn != nil && connDead(conn) {
+				rc.connDied(conn, errors.New("connection failed"))
+			}
+			continue
+		case <-rc.redialNeeded:
+		}
+
+		// Close the dead connection first so that a caching Dialer does not hand it back to us.
+		rc.mu.Lock()
+		deadConn := rc.conn
+		rc.conn = nil
+		rc.mu.Unlock()
+		if deadConn != nil {
+			utils.UncheckedError(deadConn.Close())
+		}
+
+		backoff := rc.opts.InitialBackoff
+		for {
+			if !utils.SelectContextOrWait(ctx, backoff) {
+				return
+			}
+			conn, err := rc.dial(ctx)
+			if err == nil {
+				rc.mu.Lock()
+				rc.conn = conn
+				rc.setStateLocked(ConnectionStateConnected)
+				close(rc.connected)
+				rc.mu.Unlock()
+				rc.logger.Info("reconnected")
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			rc.logger.Debugw("error reconnecting", "error", err, "backoff", backoff)
+			backoff = min(2*backoff, rc.opts.MaxBackoff)
+		}
+	}
+}
+
+func (rc *ReconnectingClientConn) retryPolicy(method string) RetryPolicy {
+	if policy, ok := rc.opts.MethodRetryPolicies[method]; ok {
+		return policy
+	}
+	return rc.opts.DefaultRetryPolicy
+}
+
+// Invoke performs a unary RPC on the current connection, retrying according to the method's
+// RetryPolicy.
+func (rc *ReconnectingClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpc.CallOption,
+) error {
+	policy := rc.retryPolicy(method)
+	backoff := rc.opts.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := rc.currentConn(ctx)
+		if err != nil {
+			return err
+		}
+		err = conn.Invoke(ctx, method, args, reply, opts...)
+		if err == nil {
+			return nil
+		}
+		if isDisconnectedError(err) {
+			rc.connDied(conn, err)
+		}
+		if attempt >= policy.MaxAttempts || !policy.retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if !utils.SelectContextOrWait(ctx, backoff) {
+			return err
+		}
+		backoff = min(2*backoff, rc.opts.MaxBackoff)
+	}
+}
+
+// NewStream creates a stream on the current connection. Streams are not retried; a stream that
+// fails because the connection died causes a reconnect so that the next call may succeed.
+func (rc *ReconnectingClientConn) NewStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	conn, err := rc.currentConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		if isDisconnectedError(err) {
+			rc.connDied(conn, err)
+		}
+		return nil, err
+	}
+	return stream, nil
+}
+
+// PeerConn returns the PeerConnection backing the current connection, if any. It returns nil
+// while reconnecting.
+func (rc *ReconnectingClientConn) PeerConn() *webrtc.PeerConnection {
+	rc.mu.Lock()
+	conn := rc.conn
+	rc.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.PeerConn()
+}
+
+// Close stops reconnecting and closes the current connection. It does not wait for OnStateChange
+// to be told about the close.
+func (rc *ReconnectingClientConn) Close() error {
+	// Stopping the workers first guarantees the redial loop can no longer change the state.
+	rc.workers.Stop()
+	rc.mu.Lock()
+	if rc.state == ConnectionStateClosed {
+		rc.mu.Unlock()
+		return nil
+	}
+	conn := rc.conn
+	rc.conn = nil
+	if rc.state == ConnectionStateReconnecting {
+		close(rc.connected)
+	}
+	rc.setStateLocked(ConnectionStateClosed)
+	close(rc.closed)
+	rc.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
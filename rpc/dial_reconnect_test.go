@@ -0,0 +1,445 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"github.com/viamrobotics/webrtc/v3"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+type fakeReconnectClientConn struct {
+	mu        sync.Mutex
+	id        int
+	invokeErr []error
+	invokes   int
+	closed    bool
+}
+
+func (cc *fakeReconnectClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpc.CallOption,
+) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.invokes++
+	if len(cc.invokeErr) == 0 {
+		return nil
+	}
+	err := cc.invokeErr[0]
+	cc.invokeErr = cc.invokeErr[1:]
+	return err
+}
+
+func (cc *fakeReconnectClientConn) NewStream(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, ErrDisconnected
+}
+
+func (cc *fakeReconnectClientConn) PeerConn() *webrtc.PeerConnection {
+	return nil
+}
+
+func (cc *fakeReconnectClientConn) Close() error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.closed = true
+	return nil
+}
+
+type fakeReconnectDialer struct {
+	mu       sync.Mutex
+	conns    []*fakeReconnectClientConn
+	dialErrs []error
+	// nextInvokeErrs are the invoke errors given to the next connection dialed.
+	nextInvokeErrs []error
+}
+
+func (d *fakeReconnectDialer) dial(ctx context.Context) (ClientConn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.dialErrs) > 0 {
+		err := d.dialErrs[0]
+		d.dialErrs = d.dialErrs[1:]
+		return nil, err
+	}
+	conn := &fakeReconnectClientConn{id: len(d.conns), invokeErr: d.nextInvokeErrs}
+	d.nextInvokeErrs = nil
+	d.conns = append(d.conns, conn)
+	return conn, nil
+}
+
+func (d *fakeReconnectDialer) numConns() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.conns)
+}
+
+type stateRecorder struct {
+	mu     sync.Mutex
+	states []ConnectionState
+}
+
+func (r *stateRecorder) record(state ConnectionState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+}
+
+func (r *stateRecorder) get() []ConnectionState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ConnectionState(nil), r.states...)
+}
+
+func TestReconnectingClientConn(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	opts := ReconnectOptions{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+
+	t.Run("initial dial error", func(t *testing.T) {
+		dialer := &fakeReconnectDialer{dialErrs: []error{errors.New("whoops")}}
+		_, err := newReconnectingClientConn(context.Background(), dialer.dial, opts, logger)
+		test.That(t, err, test.ShouldBeError, errors.New("whoops"))
+	})
+
+	t.Run("redial after disconnect", func(t *testing.T) {
+		dialer := &fakeReconnectDialer{}
+		var states stateRecorder
+		opts := opts
+		opts.OnStateChange = states.record
+		rc, err := newReconnectingClientConn(context.Background(), dialer.dial, opts, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rc.State(), test.ShouldEqual, ConnectionStateConnected)
+
+		first := dialer.conns[0]
+		first.invokeErr = []error{ErrDisconnected}
+		dialer.dialErrs = []error{errors.New("not yet"), errors.New("still not yet")}
+
+		// Without a retry policy the disconnect is returned to the caller.
+		err = rc.Invoke(context.Background(), "/svc/Method", nil, nil)
+		test.That(t, err, test.ShouldEqual, ErrDisconnected)
+
+		// The next call waits for the redial and succeeds on the new connection.
+		test.That(t, rc.Invoke(context.Background(), "/svc/Method", nil, nil), test.ShouldBeNil)
+		test.That(t, rc.State(), test.ShouldEqual, ConnectionStateConnected)
+		test.That(t, dialer.numConns(), test.ShouldEqual, 2)
+		test.That(t, first.closed, test.ShouldBeTrue)
+		test.That(t, dialer.conns[1].invokes, test.ShouldEqual, 1)
+
+		test.That(t, rc.Close(), test.ShouldBeNil)
+		test.That(t, dialer.conns[1].closed, test.ShouldBeTrue)
+		test.That(t, rc.State(), test.ShouldEqual, ConnectionStateClosed)
+		test.That(t, rc.Close(), test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, states.get(), test.ShouldResemble, []ConnectionState{
+				ConnectionStateReconnecting,
+				ConnectionStateConnected,
+				ConnectionStateClosed,
+			})
+		})
+
+		err = rc.Invoke(context.Background(), "/svc/Method", nil, nil)
+		test.That(t, err, test.ShouldEqual, ErrClientConnClosed)
+		_, err = rc.NewStream(context.Background(), &grpc.StreamDesc{}, "/svc/Stream")
+		test.That(t, err, test.ShouldEqual, ErrClientConnClosed)
+	})
+
+	t.Run("retry policy", func(t *testing.T) {
+		dialer := &fakeReconnectDialer{}
+		opts := opts
+		opts.DefaultRetryPolicy = RetryPolicy{
+			MaxAttempts:    3,
+			RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+		}
+		opts.MethodRetryPolicies = map[string]RetryPolicy{
+			"/svc/NotIdempotent": {},
+			"/svc/Custom":        {MaxAttempts: 2, RetryableCodes: []codes.Code{codes.Aborted}},
+		}
+		rc, err := newReconnectingClientConn(context.Background(), dialer.dial, opts, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rc.Close(), test.ShouldBeNil)
+		}()
+
+		unavailable := status.Error(codes.Unavailable, "try again")
+		exhausted := status.Error(codes.ResourceExhausted, "try again")
+		aborted := status.Error(codes.Aborted, "try again")
+
+		// Disconnects, and servers gRPC cannot reach, are retried on a new connection.
+		dialer.conns[0].invokeErr = []error{ErrDisconnected}
+		dialer.nextInvokeErrs = []error{unavailable}
+		test.That(t, rc.Invoke(context.Background(), "/svc/Method", nil, nil), test.ShouldBeNil)
+		test.That(t, dialer.numConns(), test.ShouldEqual, 3)
+		test.That(t, dialer.conns[1].invokes, test.ShouldEqual, 1)
+		test.That(t, dialer.conns[2].invokes, test.ShouldEqual, 1)
+
+		// Other retryable codes are retried on the same connection, but attempts are capped.
+		conn := dialer.conns[2]
+		conn.invokeErr = []error{exhausted, exhausted, exhausted}
+		test.That(t, rc.Invoke(context.Background(), "/svc/Method", nil, nil), test.ShouldEqual, exhausted)
+		test.That(t, conn.invokes, test.ShouldEqual, 4)
+
+		// Non-retryable codes fail immediately.
+		conn.invokeErr = []error{aborted}
+		test.That(t, rc.Invoke(context.Background(), "/svc/Method", nil, nil), test.ShouldEqual, aborted)
+		test.That(t, conn.invokes, test.ShouldEqual, 5)
+
+		// Per-method policies override the default.
+		conn.invokeErr = []error{exhausted}
+		test.That(t, rc.Invoke(context.Background(), "/svc/NotIdempotent", nil, nil), test.ShouldEqual, exhausted)
+		test.That(t, conn.invokes, test.ShouldEqual, 6)
+
+		conn.invokeErr = []error{aborted}
+		test.That(t, rc.Invoke(context.Background(), "/svc/Custom", nil, nil), test.ShouldBeNil)
+		test.That(t, conn.invokes, test.ShouldEqual, 8)
+		test.That(t, dialer.numConns(), test.ShouldEqual, 3)
+	})
+
+	t.Run("streams trigger redial", func(t *testing.T) {
+		dialer := &fakeReconnectDialer{}
+		rc, err := newReconnectingClientConn(context.Background(), dialer.dial, opts, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rc.Close(), test.ShouldBeNil)
+		}()
+
+		_, err = rc.NewStream(context.Background(), &grpc.StreamDesc{}, "/svc/Stream")
+		test.That(t, err, test.ShouldEqual, ErrDisconnected)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, dialer.numConns(), test.ShouldEqual, 2)
+			test.That(tb, rc.State(), test.ShouldEqual, ConnectionStateConnected)
+		})
+	})
+
+	t.Run("close while reconnecting", func(t *testing.T) {
+		dialer := &fakeReconnectDialer{}
+		rc, err := newReconnectingClientConn(context.Background(), dialer.dial, opts, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		dialer.mu.Lock()
+		for range 1000 {
+			dialer.dialErrs = append(dialer.dialErrs, errors.New("down"))
+		}
+		dialer.mu.Unlock()
+		rc.connDied(dialer.conns[0], ErrDisconnected)
+		test.That(t, rc.State(), test.ShouldEqual, ConnectionStateReconnecting)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err = rc.Invoke(ctx, "/svc/Method", nil, nil)
+		test.That(t, err, test.ShouldResemble, context.DeadlineExceeded)
+
+		invokeErr := make(chan error, 1)
+		go func() {
+			invokeErr <- rc.Invoke(context.Background(), "/svc/Method", nil, nil)
+		}()
+		test.That(t, rc.Close(), test.ShouldBeNil)
+		test.That(t, <-invokeErr, test.ShouldEqual, ErrClientConnClosed)
+		test.That(t, rc.PeerConn(), test.ShouldBeNil)
+	})
+
+	t.Run("close from OnStateChange", func(t *testing.T) {
+		dialer := &fakeReconnectDialer{}
+		var states stateRecorder
+		var rc *ReconnectingClientConn
+		closeErr := make(chan error, 1)
+		opts := opts
+		opts.OnStateChange = func(state ConnectionState) {
+			states.record(state)
+			if state == ConnectionStateReconnecting {
+				closeErr <- rc.Close()
+			}
+		}
+		var err error
+		rc, err = newReconnectingClientConn(context.Background(), dialer.dial, opts, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		dialer.mu.Lock()
+		dialer.dialErrs = []error{errors.New("down")}
+		dialer.mu.Unlock()
+		rc.connDied(dialer.conns[0], ErrDisconnected)
+		select {
+		case err := <-closeErr:
+			test.That(t, err, test.ShouldBeNil)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Close from OnStateChange deadlocked")
+		}
+		test.That(t, rc.State(), test.ShouldEqual, ConnectionStateClosed)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, states.get(), test.ShouldResemble, []ConnectionState{
+				ConnectionStateReconnecting,
+				ConnectionStateClosed,
+			})
+		})
+	})
+}
+
+func TestDialReconnecting(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	rpcServer, err := NewServer(
+		logger,
+		WithUnauthenticated(),
+		WithDisableMulticastDNS(),
+		WithWebRTCServerOptions(WebRTCServerOptions{Enable: true}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	httpListener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(httpListener)
+	}()
+
+	var states stateRecorder
+	conn, err := DialReconnecting(context.Background(), rpcServer.InstanceNames()[0], logger, ReconnectOptions{
+		InitialBackoff:     10 * time.Millisecond,
+		DefaultRetryPolicy: RetryPolicy{MaxAttempts: 5},
+		OnStateChange:      states.record,
+	},
+		WithInsecure(),
+		WithWebRTCOptions(DialWebRTCOptions{
+			SignalingInsecure:      true,
+			SignalingServerAddress: httpListener.Addr().String(),
+		}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	firstPC := conn.PeerConn()
+	test.That(t, firstPC, test.ShouldNotBeNil)
+
+	client := pb.NewEchoServiceClient(conn)
+	resp, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+
+	// Kill the PeerConnection out from under the connection. The same client keeps working.
+	test.That(t, firstPC.GracefulClose(), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, conn.PeerConn(), test.ShouldNotBeNil)
+		test.That(tb, conn.PeerConn(), test.ShouldNotEqual, firstPC)
+	})
+	resp, err = client.Echo(context.Background(), &pb.EchoRequest{Message: "again"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetMessage(), test.ShouldEqual, "again")
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, states.get(), test.ShouldResemble, []ConnectionState{
+			ConnectionStateReconnecting,
+			ConnectionStateConnected,
+			ConnectionStateClosed,
+		})
+	})
+
+	test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+	test.That(t, <-errChan, test.ShouldBeNil)
+}
+
+func TestDialReconnectingDirectGRPC(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	// serve serves an echo server on the given address until it is stopped.
+	serve := func(address string) (Server, string, chan error) {
+		rpcServer, err := NewServer(logger, WithUnauthenticated(), WithDisableMulticastDNS())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rpcServer.RegisterServiceServer(
+			context.Background(),
+			&pb.EchoService_ServiceDesc,
+			&echoserver.Server{},
+			pb.RegisterEchoServiceHandlerFromEndpoint,
+		), test.ShouldBeNil)
+		listener, err := net.Listen("tcp", address)
+		test.That(t, err, test.ShouldBeNil)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- rpcServer.Serve(listener)
+		}()
+		return rpcServer, listener.Addr().String(), errChan
+	}
+
+	rpcServer, address, errChan := serve("localhost:0")
+	var states stateRecorder
+	conn, err := DialReconnecting(context.Background(), address, logger, ReconnectOptions{
+		InitialBackoff:     10 * time.Millisecond,
+		MaxBackoff:         100 * time.Millisecond,
+		DefaultRetryPolicy: RetryPolicy{MaxAttempts: 50},
+		OnStateChange:      states.record,
+	},
+		WithInsecure(),
+		WithWebRTCOptions(DialWebRTCOptions{Disable: true}),
+		WithDialMulticastDNSOptions(DialMulticastDNSOptions{Disable: true}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conn.PeerConn(), test.ShouldBeNil)
+
+	client := pb.NewEchoServiceClient(conn)
+	resp, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+
+	// Stop the server. The next call fails as unavailable and the connection waits for the
+	// server to come back.
+	test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+	test.That(t, <-errChan, test.ShouldBeNil)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	_, err = client.Echo(ctx, &pb.EchoRequest{Message: "gone"})
+	cancel()
+	test.That(t, err, test.ShouldNotBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, conn.State(), test.ShouldEqual, ConnectionStateReconnecting)
+	})
+
+	rpcServer, _, errChan = serve(address)
+	resp, err = client.Echo(context.Background(), &pb.EchoRequest{Message: "again"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetMessage(), test.ShouldEqual, "again")
+	test.That(t, conn.State(), test.ShouldEqual, ConnectionStateConnected)
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, states.get(), test.ShouldResemble, []ConnectionState{
+			ConnectionStateReconnecting,
+			ConnectionStateConnected,
+			ConnectionStateClosed,
+		})
+	})
+
+	test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+	test.That(t, <-errChan, test.ShouldBeNil)
+}
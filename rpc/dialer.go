@@ -402,6 +402,10 @@ func dialDirectGRPC(ctx context.Context, address string, dOpts dialOptions, logg
 		if tlsConfig == nil {
 			tlsConfig = newDefaultTLSConfig()
 		}
+		if dOpts.clientCert != nil {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.Certificates = []tls.Certificate{*dOpts.clientCert}
+		}
 
 		var downgrade bool
 		if dOpts.allowInsecureDowngrade || dOpts.allowInsecureWithCredsDowngrade {
@@ -413,8 +417,8 @@ func dialDirectGRPC(ctx context.Context, address string, dOpts dialOptions, logg
 				utils.UncheckedError(conn.Close())
 			} else if strings.Contains(err.Error(), "tls: first record does not look like a TLS handshake") {
 				// unfortunately there's no explicit error value for this, so we do a string check
-				hasLocalCreds := dOpts.creds.Type != "" && dOpts.externalAuthAddr == ""
-				if dOpts.creds.Type == "" || !hasLocalCreds || dOpts.allowInsecureWithCredsDowngrade {
+				hasLocalCreds := (dOpts.creds.Type != "" || dOpts.oauth2 != nil) && dOpts.externalAuthAddr == ""
+				if !hasLocalCreds || dOpts.allowInsecureWithCredsDowngrade {
 					logger.Warnw("downgrading from TLS to plaintext", "address", address, "with_credentials", hasLocalCreds)
 					downgrade = true
 				} else if hasLocalCreds {
@@ -432,6 +436,9 @@ func dialDirectGRPC(ctx context.Context, address string, dOpts dialOptions, logg
 	if dOpts.statsHandler != nil {
 		dialOpts = append(dialOpts, grpc.WithStatsHandler(dOpts.statsHandler))
 	}
+	if dOpts.compressor != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(dOpts.compressor)))
+	}
 
 	grpcLogger := logger.Desugar()
 	if !(dOpts.debug || utils.Debug) {
@@ -457,10 +464,11 @@ func dialDirectGRPC(ctx context.Context, address string, dOpts dialOptions, logg
 
 	if dOpts.authMaterial != "" {
 		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(&staticPerRPCJWTCredentials{dOpts.authMaterial}))
-	} else if dOpts.creds.Type != "" || dOpts.externalAuthMaterial != "" {
+	} else if dOpts.creds.Type != "" || dOpts.externalAuthMaterial != "" || dOpts.oauth2 != nil {
 		rpcCreds = &perRPCJWTCredentials{
 			entity: dOpts.authEntity,
 			creds:  dOpts.creds,
+			oauth2: dOpts.oauth2,
 			debug:  dOpts.debug,
 			logger: logger,
 			// Note: don't set dialOptsCopy.authMaterial below as perRPCJWTCredentials will know to use
@@ -540,6 +548,7 @@ func dialExternalAuthEntity(ctx context.Context, logger utils.ZapCompatibleLogge
 	dOpts.insecure = dOpts.externalAuthInsecure
 	dOpts.externalAuthMaterial = ""
 	dOpts.creds = Credentials{}
+	dOpts.oauth2 = nil
 	dOpts.authEntity = ""
 
 	// reset the tls config that is used for the external Auth Service.
@@ -567,6 +576,9 @@ func (dOpts dialOptions) cacheKey() string {
 	if dOpts.creds.Payload != "" {
 		hasher.Write([]byte(dOpts.creds.Payload))
 	}
+	if dOpts.oauth2 != nil {
+		hasher.Write([]byte(dOpts.oauth2.key))
+	}
 	if dOpts.externalAuthAddr != "" {
 		hasher.Write([]byte(dOpts.externalAuthAddr))
 	}
@@ -579,6 +591,12 @@ func (dOpts dialOptions) cacheKey() string {
 	if dOpts.webrtcOpts.SignalingServerAddress != "" {
 		hasher.Write([]byte(dOpts.webrtcOpts.SignalingServerAddress))
 	}
+	for _, addr := range dOpts.webrtcOpts.SignalingServerAddresses {
+		hasher.Write([]byte(addr))
+	}
+	if dOpts.webrtcOpts.SignalingServerSRV != "" {
+		hasher.Write([]byte(dOpts.webrtcOpts.SignalingServerSRV))
+	}
 	if dOpts.webrtcOpts.SignalingExternalAuthAddress != "" {
 		hasher.Write([]byte(dOpts.webrtcOpts.SignalingExternalAuthAddress))
 	}
@@ -594,6 +612,11 @@ func (dOpts dialOptions) cacheKey() string {
 	if dOpts.webrtcOpts.SignalingCreds.Payload != "" {
 		hasher.Write([]byte(dOpts.webrtcOpts.SignalingCreds.Payload))
 	}
+	if dOpts.clientCert != nil {
+		for _, certDER := range dOpts.clientCert.Certificate {
+			hasher.Write(certDER)
+		}
+	}
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
@@ -682,6 +705,7 @@ type perRPCJWTCredentials struct {
 	entity               string
 	externalAuthToEntity string
 	creds                Credentials
+	oauth2               *oauth2Grant
 	accessToken          string
 	// The static external auth material used against the AuthenticateTo request to obtain final accessToken
 	externalAuthMaterial string
@@ -707,6 +731,12 @@ func (creds *perRPCJWTCredentials) GetRequestMetadata(ctx context.Context, uri .
 }
 
 func (creds *perRPCJWTCredentials) authenticate(ctx context.Context) (string, error) {
+	useOAuth2 := creds.oauth2 != nil && creds.creds.Type == "" && creds.externalAuthMaterial == ""
+	if useOAuth2 && creds.externalAuthToEntity == "" {
+		// the grant caches the token and replaces it before it expires.
+		return creds.oauth2.accessToken(ctx)
+	}
+
 	creds.mu.RLock()
 	accessToken := creds.accessToken
 	creds.mu.RUnlock()
@@ -716,7 +746,16 @@ func (creds *perRPCJWTCredentials) authenticate(ctx context.Context) (string, er
 		accessToken = creds.accessToken
 		if accessToken == "" {
 			// skip authenticate call when a static access token for the external auth is used.
-			if creds.externalAuthMaterial == "" {
+			if useOAuth2 {
+				if creds.debug {
+					creds.logger.Debug("getting access token from OAuth2 provider")
+				}
+				var err error
+				accessToken, err = creds.oauth2.accessToken(ctx)
+				if err != nil {
+					return "", err
+				}
+			} else if creds.externalAuthMaterial == "" {
 				if creds.debug {
 					creds.logger.Debugw("authenticating as entity", "entity", creds.entity)
 				}
@@ -20,6 +20,8 @@ following mechanisms to connect to a target server:
 By default it will try to connect with mDNS (1) and WebRTC (2) in parallel and use the
 first established connection. If both fail then it will try to connection with Direct
 gRPC. This ordering can be modified by disabling some of these methods with DialOptions.
+Addresses starting with "unix:" or "unix-abstract:" are only dialed with Direct gRPC, over
+a unix socket.
 
 # Direct gRPC
 
@@ -36,6 +38,38 @@ a DataChannel is established that we use to tunnel over gRPC method calls
 Using this is a powerful means of connection because it exposes ContextPeerConnection which makes it possible
 to use gRPC methods to modify the Video/Audio part of the connection.
 
+More signaling servers can be listed in DialWebRTCOptions, directly or by way of a DNS SRV name. They are
+tried in order, starting with the ones that could be reached last time, and the next one is tried in parallel
+whenever the last one fails or is slow to answer. The one that answered is logged with WithDialDebug.
+
+Signaling servers hand out extra ICE servers from a WebRTCConfigProvider. TURNRESTConfigProvider hands out
+TURN servers along with short-lived credentials minted from a secret shared with them (the TURN REST API).
+
+Instead of a separately deployed TURN server, a WebRTCRelay built on pion/turn can run next to a signaling
+server (see WebRTCServerOptions.Relay and WebRTCSignalingServer.SetRelay). The signaling server hands it out
+with credentials for the entities it authenticated, and each entity may only hold so many relayed addresses.
+
+Signaling servers also tell which hosts are online without placing a call (ListOnlineHosts and GetHostStatus),
+along with when their answerers were last heard from, when their call queue is a WebRTCHostDirectory. The
+memory, MongoDB and Redis call queues are. ListOnlineHosts is refused unless a WebRTCOnlineHostsFilter decides which
+hosts each caller may see (see WebRTCServerOptions.OnlineHostsFilter).
+
+Calls are traced with the provider of the go.viam.com/utils/trace package. The caller sends its W3C trace
+context along with its offer and ICE candidates, call queues keep it with the offer (see WebRTCTracedCallOffer),
+and the signaling server passes it on to the answerer. This way a single trace, with an event for each stage,
+covers the offer, ICE candidates and answer on all sides up to the first RPC over the connection.
+
+Calls have a priority class (see WebRTCCallPriority) and call queues hand answerers the waiting offers of the
+highest class first. Signaling servers decide the class of a call from its auth entity or metadata with
+WebRTCCallAdmissionOptions, which also cap how many calls of each class a host may have in progress at once.
+Queue depths and refused calls are reported as metrics and in WebRTCSignalingServer.CallStats.
+
+# Stopping
+
+Servers stopped with GracefulStopper.StopGracefully refuse new calls and WebRTC connections and send a GoAway
+to connected WebRTC clients, which then connect again for any new calls. The calls already being handled are given until
+the passed context is done to finish before the server stops. Progress is reported in the DrainStats of Stats.
+
 Multicast DNS (mDNS)
 
 By default, a server will broadcast its ability to be connected to over gRPC/WebRTC over mDNS. When a dial
@@ -66,6 +100,10 @@ a key used to verify the JWT if it was not signed by the RPC service provider. O
 EntityDataLoader associated with the credential type can use the JWT metadata to produce application to produce
 data for the entity to be accessible via rpc.MustContextAuthEntity.
 
+Instead of authenticating to the server, clients can get access tokens from an OAuth2 provider with the
+WithOAuth2ClientCredentials and WithOAuth2DeviceAuthorization DialOptions. The tokens are cached and refreshed
+by the client and verified by the server as external tokens (e.g. with WithExternalAuthOIDCTokenVerifier).
+
 Additionally, authentication via mutual TLS is supported by way of the WithTLSAuthHandler and
 WithInternalTLSConfig ServerOptions. Using these two options in tandem will ask clients connecting
 to present a client certificate, which will be verified. This verified certificate is then caught by
@@ -73,6 +111,25 @@ the authentication middleware before JWT presence is checked. If any of the DNS
 client certificate match that of the entities checked in WithTLSAuthHandler, then the request will be
 allowed to proceed.
 
+WithMTLSAuthHandler instead verifies client certificates against a pool of CAs and maps them to entities,
+by SPIFFE ID by default, without the client having to authenticate first. It requests certificates on both
+the internal listener and ServeTLS. Over WebRTC, the DTLS certificate of the peer connection, whose fingerprint
+is exchanged through signaling, is verified the same way. Clients present a certificate with the
+WithClientCertificate DialOption.
+
+Servers whose internal listener is bound to a unix socket (see WithInternalBindAddress) can authenticate
+local processes by the user they run as, which the kernel reports (SO_PEERCRED on Linux), with
+MakeUnixPeerAuthHandler. Clients authenticate this way with the WithUnixPeerCredentials DialOption.
+
+Once authenticated, an entity may call any method unless the WithAuthorizationPolicy ServerOption is used.
+Its rules match on full method names, credentials types, entities and auth metadata (e.g. roles or scopes)
+and are checked in order, over both gRPC and WebRTC. Calls that are not allowed fail with PermissionDenied.
+
+Authentication attempts, token verification failures and every call, along with the entity that made it,
+can be recorded as structured AuditEvents by way of the WithAuditSink ServerOption. JSON lines and MongoDB
+sinks are provided. The MongoDB sink keeps events in their own database and drops them, rather than holding
+up requests, when it falls behind.
+
 For WebRTC, we assume that signaling is implemented as an authenticated/authorized service and for now,
 do not pass any JWTs over the WebRTC data channels that are established.
 
@@ -0,0 +1,21 @@
+package rpc
+
+import "context"
+
+// WaitForAnswererOnlineFunc returns the answerer liveness check of queues that only eventually
+// learn of answerers coming online, or nil if the queue does not need one.
+func WaitForAnswererOnlineFunc(queue WebRTCCallQueue) func(ctx context.Context, hosts []string) error {
+	if waiter, ok := queue.(interface {
+		waitForAnswererOnline(ctx context.Context, hosts []string) error
+	}); ok {
+		return waiter.waitForAnswererOnline
+	}
+	return nil
+}
+
+// SetDefaultOfferDeadline changes how long call offers have to live and returns a function to
+// restore the previous deadline.
+var SetDefaultOfferDeadline = setDefaultOfferDeadline
+
+// MongoDBWebRTCCallQueueDBName is the database the MongoDB call queue uses.
+var MongoDBWebRTCCallQueueDBName = &mongodbWebRTCCallQueueDBName
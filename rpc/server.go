@@ -30,10 +30,13 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	"go.viam.com/utils"
@@ -69,7 +72,8 @@ type Server interface {
 	// ServeTLS will externally serve, using the given cert/key, the
 	// all in one handler described by http.Handler. The provided tlsConfig
 	// will be used for any extra TLS settings. Client authentication is handled
-	// at the application level; the internal listener uses tls.NoClientCert.
+	// at the application level; the internal listener uses tls.NoClientCert unless
+	// WithMTLSAuthHandler is used, in which case client certificates are requested.
 	ServeTLS(listener net.Listener, certFile, keyFile string, tlsConfig *tls.Config) error
 
 	// Stop stops the internal gRPC and the HTTP server if it
@@ -111,6 +115,7 @@ type simpleServer struct {
 	mu                      sync.RWMutex
 	activeBackgroundWorkers sync.WaitGroup
 	grpcListener            net.Listener
+	gatewayListener         *bufconn.Listener
 	grpcServer              *grpc.Server
 	grpcWebServer           *grpcweb.WrappedGrpcServer
 	grpcGatewayHandler      *runtime.ServeMux
@@ -121,7 +126,9 @@ type simpleServer struct {
 	serviceServerCancels    []func()
 	signalingCallQueue      WebRTCCallQueue
 	signalingServer         *WebRTCSignalingServer
+	relay                   *WebRTCRelay
 	mdnsServers             []*zeroconf.Server
+	healthServer            *health.Server
 	// exempt methods do not perform any auth
 	exemptMethods map[string]bool
 	// public methods attempt, but do not require, authentication
@@ -129,17 +136,24 @@ type simpleServer struct {
 	tlsConfig            *tls.Config
 	firstSeenTLSCertLeaf *x509.Certificate
 	stopped              bool
+	rpcs                 rpcTracker
 	logger               utils.ZapCompatibleLogger
 
 	// auth
-	authKeys             map[string]authKeyData
-	authKeyForJWTSigning authKeyData
+	authKeys             *authKeyRing
 	internalUUID         string
 	internalCreds        Credentials
 	tlsAuthHandler       func(ctx context.Context, entities ...string) error
+	mtlsAuth             *mtlsAuthenticator
+	authorizationPolicy  *AuthorizationPolicy
+	auditSink            AuditSink
 	authHandlersForCreds map[CredentialsType]credAuthHandlers
 	authToHandler        AuthenticateToHandler
 	ensureAuthedHandler  func(ctx context.Context) (context.Context, error)
+	tokenRevocationStore TokenRevocationStore
+	tokenRevocationCache *tokenRevocationCache
+	accessTokenTTL       time.Duration
+	refreshTokenTTL      time.Duration
 
 	// authAudience is the JWT audience (aud) that will be used/expected
 	// for our service.
@@ -161,7 +175,11 @@ type simpleServer struct {
 	}
 }
 
-var errMixedUnauthAndAuth = errors.New("cannot use unauthenticated and auth handlers at same time")
+var (
+	errMixedUnauthAndAuth = errors.New("cannot use unauthenticated and auth handlers at same time")
+	// a custom ensure authed handler leaves no claims for an authorization policy to decide on.
+	errMixedPolicyAndEnsureAuthed = errors.New("cannot use an authorization policy and an ensure authed handler at same time")
+)
 
 func addrsForInterface(iface *net.Interface) ([]string, []string) {
 	var v4, v6, v6local []string
@@ -200,9 +218,13 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 			return nil, err
 		}
 	}
-	if sOpts.unauthenticated && (len(sOpts.authHandlersForCreds) != 0 || sOpts.tlsAuthHandler != nil) {
+	if sOpts.unauthenticated && (len(sOpts.authHandlersForCreds) != 0 || sOpts.tlsAuthHandler != nil ||
+		sOpts.mtlsAuth != nil || sOpts.authorizationPolicy != nil) {
 		return nil, errMixedUnauthAndAuth
 	}
+	if sOpts.authorizationPolicy != nil && sOpts.ensureAuthedHandler != nil {
+		return nil, errMixedPolicyAndEnsureAuthed
+	}
 
 	grpcBindAddr := sOpts.bindAddress
 	if grpcBindAddr == "" {
@@ -213,8 +235,13 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		}
 	}
 
+	grpcNetwork := "tcp"
+	if path, ok := unixSocketPath(grpcBindAddr); ok {
+		grpcNetwork, grpcBindAddr = "unix", path
+	}
+
 	var lc net.ListenConfig
-	grpcListener, err := lc.Listen(context.Background(), "tcp", grpcBindAddr)
+	grpcListener, err := lc.Listen(context.Background(), grpcNetwork, grpcBindAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -238,8 +265,17 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		} else {
 			firstSeenTLSCert = &sOpts.tlsConfig.Certificates[0]
 		}
+		if sOpts.mtlsAuth != nil && sOpts.tlsConfig.ClientAuth == tls.NoClientCert {
+			// certificates are verified by the mTLS auth handler.
+			sOpts.tlsConfig.ClientAuth = tls.RequestClientCert
+		}
 		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(sOpts.tlsConfig)))
 	}
+	var gatewayListener *bufconn.Listener
+	if sOpts.tlsConfig == nil && grpcNetwork == "unix" {
+		serverOpts = append(serverOpts, grpc.Creds(newUnixPeerTransportCredentials()))
+		gatewayListener = newGatewayListener()
+	}
 
 	var firstSeenTLSCertLeaf *x509.Certificate
 	if firstSeenTLSCert != nil {
@@ -255,6 +291,19 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		MaxHeaderBytes:    MaxMessageSize,
 	}
 
+	if sOpts.authKeySource != nil {
+		if len(sOpts.authKeys) != 0 || sOpts.jwtSignerKeyID != "" || sOpts.authKeyRotationInterval > 0 {
+			return nil, errors.New("cannot use WithAuthKeySource with other auth key options")
+		}
+		if sOpts.unauthenticated {
+			return nil, errors.New("cannot use WithAuthKeySource with WithUnauthenticated")
+		}
+		sOpts.authKeys, sOpts.jwtSignerKeyID, err = loadAuthKeys(context.Background(), sOpts.authKeySource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(sOpts.authKeys) == 0 {
 		if sOpts.jwtSignerKeyID != "" {
 			return nil, errors.New("cannot use WithJWTSignerKeyID if no auth keys are set")
@@ -297,13 +346,12 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		}
 	}
 
-	var authKeyForJWTSigning authKeyData
+	var authKeys *authKeyRing
 	if !sOpts.unauthenticated {
-		var ok bool
-		authKeyForJWTSigning, ok = sOpts.authKeys[sOpts.jwtSignerKeyID]
-		if !ok {
+		if _, ok := sOpts.authKeys[sOpts.jwtSignerKeyID]; !ok {
 			return nil, fmt.Errorf("no auth key data set for key id %q", sOpts.jwtSignerKeyID)
 		}
+		authKeys = newAuthKeyRing(sOpts.authKeys, sOpts.jwtSignerKeyID)
 	}
 
 	internalCredsKey := make([]byte, 64)
@@ -328,28 +376,48 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 	)
 
 	server := &simpleServer{
-		grpcListener:         grpcListener,
-		httpServer:           httpServer,
-		grpcGatewayHandler:   grpcGatewayHandler,
-		authKeys:             sOpts.authKeys,
-		authKeyForJWTSigning: authKeyForJWTSigning,
-		internalUUID:         uuid.NewString(),
+		grpcListener:       grpcListener,
+		gatewayListener:    gatewayListener,
+		httpServer:         httpServer,
+		grpcGatewayHandler: grpcGatewayHandler,
+		authKeys:           authKeys,
+		internalUUID:       uuid.NewString(),
 		internalCreds: Credentials{
 			Type:    credentialsTypeInternal,
 			Payload: base64.StdEncoding.EncodeToString(internalCredsKey),
 		},
 		tlsAuthHandler:       sOpts.tlsAuthHandler,
+		mtlsAuth:             sOpts.mtlsAuth,
+		authorizationPolicy:  sOpts.authorizationPolicy,
+		auditSink:            sOpts.auditSink,
 		authHandlersForCreds: sOpts.authHandlersForCreds,
 		authToHandler:        sOpts.authToHandler,
 		authAudience:         sOpts.authAudience,
 		authIssuer:           sOpts.authIssuer,
 		ensureAuthedHandler:  sOpts.ensureAuthedHandler,
+		tokenRevocationStore: sOpts.tokenRevocationStore,
+		accessTokenTTL:       sOpts.accessTokenTTL,
+		refreshTokenTTL:      sOpts.refreshTokenTTL,
 		exemptMethods:        make(map[string]bool),
 		publicMethods:        make(map[string]bool),
 		tlsConfig:            sOpts.tlsConfig,
 		firstSeenTLSCertLeaf: firstSeenTLSCertLeaf,
 		logger:               logger,
 	}
+	if server.refreshTokenTTL != 0 && server.tokenRevocationStore == nil {
+		server.tokenRevocationStore = NewMemoryTokenRevocationStore()
+	}
+	// an in-memory store is as quick to consult as a cache.
+	_, isMemoryStore := server.tokenRevocationStore.(*MemoryTokenRevocationStore)
+	if server.tokenRevocationStore != nil && !isMemoryStore {
+		cacheTTL := defaultTokenRevocationCacheTTL
+		if sOpts.tokenRevocationCacheTTLSet {
+			cacheTTL = sOpts.tokenRevocationCacheTTL
+		}
+		if cacheTTL > 0 {
+			server.tokenRevocationCache = newTokenRevocationCache(cacheTTL)
+		}
+	}
 
 	if sOpts.unknownStreamDesc != nil {
 		serverOpts = append(serverOpts, grpc.UnknownServiceHandler(sOpts.unknownStreamDesc.Handler))
@@ -367,13 +435,20 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 			}))),
 		grpcUnaryServerInterceptor(grpcLogger),
 		unaryServerCodeInterceptor(),
+		server.drainUnaryInterceptor,
 	)
 	unaryInterceptors = append(unaryInterceptors, UnaryServerTracingInterceptor())
+	if sOpts.auditSink != nil {
+		unaryInterceptors = append(unaryInterceptors, server.auditUnaryInterceptor)
+	}
 	unaryAuthIntPos := -1
 	if !sOpts.unauthenticated {
 		unaryInterceptors = append(unaryInterceptors, server.authUnaryInterceptor)
 		unaryAuthIntPos = len(unaryInterceptors) - 1
 	}
+	if sOpts.rateLimiter != nil {
+		unaryInterceptors = append(unaryInterceptors, rateLimitUnaryInterceptor(sOpts.rateLimiter))
+	}
 	if sOpts.unaryInterceptor != nil {
 		unaryInterceptors = append(unaryInterceptors, func(
 			ctx context.Context,
@@ -400,13 +475,20 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 			}))),
 		grpcStreamServerInterceptor(grpcLogger),
 		streamServerCodeInterceptor(),
+		server.drainStreamInterceptor,
 	)
 	streamInterceptors = append(streamInterceptors, StreamServerTracingInterceptor())
+	if sOpts.auditSink != nil {
+		streamInterceptors = append(streamInterceptors, server.auditStreamInterceptor)
+	}
 	streamAuthIntPos := -1
 	if !sOpts.unauthenticated {
 		streamInterceptors = append(streamInterceptors, server.authStreamInterceptor)
 		streamAuthIntPos = len(streamInterceptors) - 1
 	}
+	if sOpts.rateLimiter != nil {
+		streamInterceptors = append(streamInterceptors, rateLimitStreamInterceptor(sOpts.rateLimiter))
+	}
 	if sOpts.streamInterceptor != nil {
 		streamInterceptors = append(streamInterceptors, func(
 			srv interface{},
@@ -455,6 +537,7 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		}
 		// Update this if the proto method or path changes
 		server.exemptMethods["/proto.rpc.v1.AuthService/Authenticate"] = true
+		server.exemptMethods["/proto.rpc.v1.AuthService/RefreshToken"] = true
 	}
 
 	if sOpts.allowUnauthenticatedHealthCheck {
@@ -480,6 +563,9 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 	var mDNSAddress *net.TCPAddr
 	if sOpts.listenerAddress != nil {
 		mDNSAddress = sOpts.listenerAddress
+	} else if grpcNetwork == "unix" {
+		// there is no address to broadcast.
+		sOpts.disableMDNS = true
 	} else {
 		var ok bool
 		mDNSAddress, ok = grpcListener.Addr().(*net.TCPAddr)
@@ -494,9 +580,13 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 	}
 	instanceNames := sOpts.instanceNames
 	if len(instanceNames) == 0 {
-		instanceName, err := InstanceNameFromAddress(mDNSAddress.String())
-		if err != nil {
-			return nil, err
+		// like an IP address, a unix socket says nothing about the host.
+		instanceName := uuid.NewString()
+		if mDNSAddress != nil {
+			instanceName, err = InstanceNameFromAddress(mDNSAddress.String())
+			if err != nil {
+				return nil, err
+			}
 		}
 		instanceNames = []string{instanceName}
 	}
@@ -652,12 +742,19 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		webrtcStreamInterceptors := make([]grpc.StreamServerInterceptor, 0, len(streamInterceptors))
 		for idx, interceptor := range unaryInterceptors {
 			if idx == unaryAuthIntPos {
+				// authentication happens through signaling but authorization still applies.
+				if sOpts.authorizationPolicy != nil {
+					webrtcUnaryInterceptors = append(webrtcUnaryInterceptors, server.authorizeUnaryInterceptor)
+				}
 				continue
 			}
 			webrtcUnaryInterceptors = append(webrtcUnaryInterceptors, interceptor)
 		}
 		for idx, interceptor := range streamInterceptors {
 			if idx == streamAuthIntPos {
+				if sOpts.authorizationPolicy != nil {
+					webrtcStreamInterceptors = append(webrtcStreamInterceptors, server.authorizeStreamInterceptor)
+				}
 				continue
 			}
 			webrtcStreamInterceptors = append(webrtcStreamInterceptors, interceptor)
@@ -671,7 +768,11 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 			streamInterceptor,
 			sOpts.unknownStreamDesc,
 			sOpts.statsHandler,
+			sOpts.webrtcOpts,
 		)
+		if sOpts.mtlsAuth != nil {
+			server.webrtcServer.authenticatePeer = sOpts.mtlsAuth.authenticate
+		}
 		reflection.Register(server.webrtcServer)
 
 		config := DefaultWebRTCConfiguration
@@ -711,6 +812,8 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 			server.signalingCallQueue = signalingCallQueue
 			server.signalingServer = NewWebRTCSignalingServer(signalingCallQueue, nil, logger,
 				defaultHeartbeatInterval, internalSignalingHosts...)
+			server.signalingServer.SetCallAdmission(sOpts.webrtcOpts.CallAdmission)
+			server.signalingServer.SetOnlineHostsFilter(sOpts.webrtcOpts.OnlineHostsFilter)
 			if err := server.RegisterServiceServer(
 				context.Background(),
 				&webrtcpb.SignalingService_ServiceDesc,
@@ -719,8 +822,16 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 			); err != nil {
 				return nil, err
 			}
+			if sOpts.webrtcOpts.Relay != nil {
+				relay, err := NewWebRTCRelay(*sOpts.webrtcOpts.Relay, utils.Sublogger(logger, "relay"))
+				if err != nil {
+					return nil, err
+				}
+				server.relay = relay
+				server.signalingServer.SetRelay(relay)
+			}
 
-			address := grpcListener.Addr().String()
+			address := listenerTarget(grpcListener.Addr())
 			logger.Infow(
 				"Running internal signaling",
 				"signaling_address", address,
@@ -750,6 +861,37 @@ func NewServer(logger utils.ZapCompatibleLogger, opts ...ServerOption) (Server,
 		}
 	}
 
+	if sOpts.healthService {
+		// services registered so far, like signaling, are serving along with the ones registered
+		// from now on.
+		healthServer := health.NewServer()
+		for service := range grpcServer.GetServiceInfo() {
+			healthServer.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+		}
+		if err := server.RegisterServiceServer(
+			context.Background(),
+			&healthpb.Health_ServiceDesc,
+			healthServer,
+		); err != nil {
+			return nil, err
+		}
+		server.healthServer = healthServer
+	}
+
+	if authKeys != nil && sOpts.authKeyRotationInterval > 0 {
+		if err := authKeys.prepareRotation(); err != nil {
+			return nil, err
+		}
+		rotationCtx, rotationCancel := context.WithCancel(context.Background())
+		server.serviceServerCancels = append(server.serviceServerCancels, rotationCancel)
+		server.rotateAuthKeysPeriodically(rotationCtx, sOpts.authKeyRotationInterval, sOpts.authKeyRetireAfter)
+	}
+	if authKeys != nil && sOpts.authKeySource != nil {
+		refreshCtx, refreshCancel := context.WithCancel(context.Background())
+		server.serviceServerCancels = append(server.serviceServerCancels, refreshCancel)
+		server.refreshAuthKeysPeriodically(refreshCtx, sOpts.authKeySource, sOpts.authKeySourceRefreshInterval)
+	}
+
 	return server, nil
 }
 
@@ -826,7 +968,12 @@ func (ss *simpleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		fallthrough
 	default:
 		ss.counters.TCPOtherRequestsStarted.Add(1)
-		ss.grpcGatewayHandler.ServeHTTP(w, r)
+		switch r.URL.Path {
+		case JWKSPath, OIDCDiscoveryPath:
+			ss.JWKSHandler().ServeHTTP(w, r)
+		default:
+			ss.grpcGatewayHandler.ServeHTTP(w, r)
+		}
 		ss.counters.TCPOtherRequestsCompleted.Add(1)
 	}
 }
@@ -852,6 +999,15 @@ func (ss *simpleServer) Start() error {
 			errMu.Unlock()
 		}
 	})
+	if ss.gatewayListener != nil {
+		utils.PanicCapturingGo(func() {
+			if serveErr := ss.grpcServer.Serve(ss.gatewayListener); serveErr != nil {
+				errMu.Lock()
+				err = multierr.Combine(err, serveErr)
+				errMu.Unlock()
+			}
+		})
+	}
 
 	for _, answerer := range ss.webrtcAnswerers {
 		answerer.Start()
@@ -901,6 +1057,14 @@ func (ss *simpleServer) serveTLS(listener net.Listener, certFile, keyFile string
 			if tlsConfig != nil {
 				ss.httpServer.TLSConfig = tlsConfig.Clone()
 			}
+			if ss.mtlsAuth != nil {
+				if ss.httpServer.TLSConfig == nil {
+					ss.httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+				}
+				if ss.httpServer.TLSConfig.ClientAuth == tls.NoClientCert {
+					ss.httpServer.TLSConfig.ClientAuth = tls.RequestClientCert
+				}
+			}
 			serveErr = ss.httpServer.ServeTLS(listener, certFile, keyFile)
 		} else {
 			serveErr = ss.httpServer.Serve(listener)
@@ -927,6 +1091,10 @@ func (ss *simpleServer) Stop() error {
 	ss.stopped = true
 	var err error
 	ss.logger.Info("stopping")
+	if ss.healthServer != nil {
+		// let health watchers know before connections go away.
+		ss.healthServer.Shutdown()
+	}
 	for idx, answerer := range ss.webrtcAnswerers {
 		ss.logger.Debugw("stopping WebRTC answerer", "num", idx)
 		answerer.Stop()
@@ -935,6 +1103,9 @@ func (ss *simpleServer) Stop() error {
 	if ss.signalingServer != nil {
 		ss.signalingServer.Close()
 	}
+	if ss.relay != nil {
+		err = multierr.Combine(err, ss.relay.Close())
+	}
 	if ss.signalingCallQueue != nil {
 		err = multierr.Combine(err, ss.signalingCallQueue.Close())
 	}
@@ -966,6 +1137,7 @@ func (ss *simpleServer) Stop() error {
 type SimpleServerStats struct {
 	TCPGrpcStats    TCPGrpcStats
 	WebRTCGrpcStats WebRTCGrpcStats
+	DrainStats      DrainStats
 }
 
 // TCPGrpcStats are stats for the classic tcp/http2 webserver.
@@ -990,6 +1162,7 @@ func (ss *simpleServer) Stats() any {
 			OtherRequestsCompleted: ss.counters.TCPOtherRequestsCompleted.Load(),
 		},
 		WebRTCGrpcStats: ss.webrtcServer.Stats(),
+		DrainStats:      ss.rpcs.stats(),
 	}
 }
 
@@ -1016,10 +1189,20 @@ func (ss *simpleServer) RegisterServiceServer(
 		//nolint:contextcheck
 		ss.webrtcServer.RegisterService(svcDesc, svcServer)
 	}
+	if ss.healthServer != nil {
+		ss.healthServer.SetServingStatus(svcDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	}
 	if len(svcHandlers) != 0 {
-		addr := ss.grpcListener.Addr().String()
+		addr := listenerTarget(ss.grpcListener.Addr())
 		opts := []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxMessageSize))}
-		if ss.tlsConfig == nil {
+		if ss.gatewayListener != nil {
+			addr = gatewayTarget
+			opts = append(opts,
+				grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+					return ss.gatewayListener.DialContext(ctx)
+				}),
+				grpc.WithTransportCredentials(insecure.NewCredentials()))
+		} else if ss.tlsConfig == nil {
 			opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		} else {
 			tlsConfig := ss.tlsConfig.Clone()
@@ -1035,6 +1218,25 @@ func (ss *simpleServer) RegisterServiceServer(
 	return nil
 }
 
+// A HealthStatusSetter changes what the health service of a server reports. Servers returned by
+// NewServer implement it; callers type-assert a Server to it.
+type HealthStatusSetter interface {
+	// SetServingStatus sets the status the health service reports for the given service, or for
+	// the server as a whole when service is empty. Services are reported as serving once they are
+	// registered and as not serving once the server is stopping. It does nothing unless the
+	// WithHealthService option is used.
+	SetServingStatus(service string, servingStatus healthpb.HealthCheckResponse_ServingStatus)
+}
+
+var _ HealthStatusSetter = (*simpleServer)(nil)
+
+func (ss *simpleServer) SetServingStatus(service string, servingStatus healthpb.HealthCheckResponse_ServingStatus) {
+	if ss.healthServer == nil {
+		return
+	}
+	ss.healthServer.SetServingStatus(service, servingStatus)
+}
+
 func unaryServerCodeInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		resp, err := handler(ctx, req)
@@ -1094,11 +1296,31 @@ const (
 	PeerConnectionTypeWebRTC
 )
 
+// String returns the name of the connection type.
+func (t PeerConnectionType) String() string {
+	switch t {
+	case PeerConnectionTypeGRPC:
+		return "grpc"
+	case PeerConnectionTypeWebRTC:
+		return "webrtc"
+	case PeerConnectionTypeUnknown:
+		fallthrough
+	default:
+		return "unknown"
+	}
+}
+
 // PeerConnectionInfo details information about a connection.
 type PeerConnectionInfo struct {
 	ConnectionType PeerConnectionType
 	LocalAddress   string
 	RemoteAddress  string
+
+	// RoundTripTime and RoundTripTimeJitter are the smoothed round trip time of a WebRTC
+	// connection, and its variation, as measured by keepalive pings. They are zero until the
+	// peer answers a ping.
+	RoundTripTime       time.Duration
+	RoundTripTimeJitter time.Duration
 }
 
 // PeerConnectionInfoFromContext returns as much information about the connection as can be found
@@ -1113,10 +1335,13 @@ func PeerConnectionInfoFromContext(ctx context.Context) PeerConnectionInfo {
 	if pc, ok := ContextPeerConnection(ctx); ok {
 		candPair, hasCandPair := webrtcPeerConnCandPair(pc)
 		if hasCandPair {
+			rtt, jitter, _ := contextRoundTripTime(ctx)
 			return PeerConnectionInfo{
-				ConnectionType: PeerConnectionTypeWebRTC,
-				LocalAddress:   candPair.Local.String(),
-				RemoteAddress:  candPair.Remote.String(),
+				ConnectionType:      PeerConnectionTypeWebRTC,
+				LocalAddress:        candPair.Local.String(),
+				RemoteAddress:       candPair.Remote.String(),
+				RoundTripTime:       rtt,
+				RoundTripTimeJitter: jitter,
 			}
 		}
 	}
@@ -0,0 +1,313 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils"
+	mongoutils "go.viam.com/utils/mongo"
+	"go.viam.com/utils/perf/statz"
+	"go.viam.com/utils/perf/statz/units"
+)
+
+func init() {
+	mongoutils.MustRegisterNamespace(&mongodbAuditDBName, &mongodbAuditEventsCollName)
+}
+
+var auditEventsDropped = statz.NewCounter0("rpc/audit_events_dropped", statz.MetricConfig{
+	Description: "The number of audit events dropped because the MongoDB audit sink's buffer was full.",
+	Unit:        units.Dimensionless,
+})
+
+// AuditEventType is the kind of thing an AuditEvent records.
+type AuditEventType string
+
+// The types of audit events.
+const (
+	// AuditEventAuthenticate records an Authenticate call, successful or not.
+	AuditEventAuthenticate = AuditEventType("authenticate")
+	// AuditEventRefreshToken records a RefreshToken call, successful or not.
+	AuditEventRefreshToken = AuditEventType("refresh_token")
+	// AuditEventAuthenticateTo records an AuthenticateTo call, successful or not, where Entity
+	// delegates to ToEntity.
+	AuditEventAuthenticateTo = AuditEventType("authenticate_to")
+	// AuditEventTokenVerification records a request whose access token failed verification.
+	AuditEventTokenVerification = AuditEventType("token_verification")
+	// AuditEventRPC records every call to a method once it finishes.
+	AuditEventRPC = AuditEventType("rpc")
+)
+
+// An AuditEvent is a structured record of an authentication or access decision made by a server.
+type AuditEvent struct {
+	Time   time.Time      `json:"time" bson:"time"`
+	Type   AuditEventType `json:"type" bson:"type"`
+	Method string         `json:"method,omitempty" bson:"method,omitempty"`
+
+	// Entity is who made the request, if known. For authentication events, it is the entity
+	// authentication was requested for.
+	Entity          string          `json:"entity,omitempty" bson:"entity,omitempty"`
+	CredentialsType CredentialsType `json:"credentials_type,omitempty" bson:"credentials_type,omitempty"`
+	ToEntity        string          `json:"to_entity,omitempty" bson:"to_entity,omitempty"`
+
+	ConnectionType string `json:"connection_type" bson:"connection_type"`
+	RemoteAddress  string `json:"remote_address,omitempty" bson:"remote_address,omitempty"`
+
+	// Code is the gRPC status code the request ended with and Error its message, if it failed.
+	Code          string  `json:"code" bson:"code"`
+	Error         string  `json:"error,omitempty" bson:"error,omitempty"`
+	LatencyMillis float64 `json:"latency_ms,omitempty" bson:"latency_ms,omitempty"`
+}
+
+// An AuditSink records the audit events of a server. Audit is called while requests are being
+// handled so it should not block for long.
+type AuditSink interface {
+	Audit(event AuditEvent) error
+}
+
+var (
+	_ AuditSink = (*JSONLinesAuditSink)(nil)
+	_ AuditSink = (*MongoDBAuditSink)(nil)
+)
+
+// A JSONLinesAuditSink writes audit events as lines of JSON.
+type JSONLinesAuditSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLinesAuditSink returns an AuditSink writing to w.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// NewJSONLinesFileAuditSink returns an AuditSink appending to the file at the given path,
+// creating it if needed. The file is closed by Close.
+func NewJSONLinesFileAuditSink(path string) (*JSONLinesAuditSink, error) {
+	//nolint:gosec
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesAuditSink{w: file, closer: file}, nil
+}
+
+// Audit writes the event as one line of JSON.
+func (s *JSONLinesAuditSink) Audit(event AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// Close closes the file if the sink opened it.
+func (s *JSONLinesAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// Database configuration for the MongoDB audit sink.
+var (
+	mongodbAuditDBName           = "rpc_audit"
+	mongodbAuditEventsCollName   = "audit_events"
+	mongodbAuditEventsBufferSize = 1024
+	mongodbAuditEventsBatchSize  = 100
+)
+
+// A MongoDBAuditSink stores audit events in MongoDB. Events are buffered and inserted in batches
+// in the background so that requests do not wait on the database. When the database cannot keep
+// up and the buffer fills, new events are dropped and counted in the rpc/audit_events_dropped
+// metric.
+type MongoDBAuditSink struct {
+	coll   *mongo.Collection
+	logger utils.ZapCompatibleLogger
+
+	mu                      sync.RWMutex
+	closed                  bool
+	events                  chan AuditEvent
+	activeBackgroundWorkers sync.WaitGroup
+}
+
+// NewMongoDBAuditSink returns a new MongoDB based AuditSink. Events that cannot be inserted are
+// logged with the given logger.
+func NewMongoDBAuditSink(
+	ctx context.Context,
+	client *mongo.Client,
+	logger utils.ZapCompatibleLogger,
+) (*MongoDBAuditSink, error) {
+	coll := client.Database(mongodbAuditDBName).Collection(mongodbAuditEventsCollName)
+	if err := mongoutils.EnsureIndexes(ctx, coll,
+		mongo.IndexModel{Keys: bson.D{{Key: "time", Value: 1}}},
+		mongo.IndexModel{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "time", Value: 1}}},
+	); err != nil {
+		return nil, err
+	}
+
+	s := &MongoDBAuditSink{
+		coll:   coll,
+		logger: logger,
+		events: make(chan AuditEvent, mongodbAuditEventsBufferSize),
+	}
+	s.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(s.insertEvents, s.activeBackgroundWorkers.Done)
+	return s, nil
+}
+
+// Audit queues the event to be inserted. It never blocks; the event is dropped if the buffer of
+// queued events is full.
+func (s *MongoDBAuditSink) Audit(event AuditEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errors.New("audit sink is closed")
+	}
+	select {
+	case s.events <- event:
+	default:
+		auditEventsDropped.Inc()
+	}
+	return nil
+}
+
+// Close inserts the queued events and stops the sink.
+func (s *MongoDBAuditSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+	s.activeBackgroundWorkers.Wait()
+	return nil
+}
+
+func (s *MongoDBAuditSink) insertEvents() {
+	for event := range s.events {
+		batch := []interface{}{event}
+	drain:
+		for len(batch) < mongodbAuditEventsBatchSize {
+			select {
+			case event, ok := <-s.events:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, event)
+			default:
+				break drain
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if _, err := s.coll.InsertMany(ctx, batch); err != nil {
+			s.logger.Errorw("failed to insert audit events", "error", err, "count", len(batch))
+		}
+		cancel()
+	}
+}
+
+// audit fills in what the request context tells about the event, and the outcome from err, and
+// records it.
+func (ss *simpleServer) audit(ctx context.Context, event AuditEvent, err error) {
+	if ss.auditSink == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Method == "" {
+		event.Method, _ = grpc.Method(ctx)
+	}
+	connInfo := PeerConnectionInfoFromContext(ctx)
+	event.ConnectionType = connInfo.ConnectionType.String()
+	event.RemoteAddress = connInfo.RemoteAddress
+	event.Code = status.Code(err).String()
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if auditErr := ss.auditSink.Audit(event); auditErr != nil {
+		ss.logger.Warnw("failed to record audit event", "error", auditErr, "type", event.Type, "method", event.Method)
+	}
+}
+
+// An auditCall lets the auth interceptors, which run after the audit interceptors, tell them who
+// made a call.
+type auditCall struct {
+	authedCtx context.Context
+}
+
+func contextWithAuditCall(ctx context.Context, call *auditCall) context.Context {
+	return context.WithValue(ctx, ctxKeyAuditCall, call)
+}
+
+// recordAuditCaller notes the entity authenticated in authedCtx for the audit of the call.
+func recordAuditCaller(authedCtx context.Context) {
+	if call, ok := authedCtx.Value(ctxKeyAuditCall).(*auditCall); ok {
+		call.authedCtx = authedCtx
+	}
+}
+
+// auditRPC records a finished call along with who made it.
+func (ss *simpleServer) auditRPC(ctx context.Context, call *auditCall, method string, startTime time.Time, err error) {
+	event := AuditEvent{
+		Time:          startTime,
+		Type:          AuditEventRPC,
+		Method:        method,
+		LatencyMillis: float64(time.Since(startTime).Microseconds()) / 1000,
+	}
+	// WebRTC calls are authenticated before any interceptors run.
+	authedCtx := ctx
+	if call.authedCtx != nil {
+		authedCtx = call.authedCtx
+	}
+	if entity, ok := ContextAuthEntity(authedCtx); ok {
+		event.Entity = entity.Entity
+	}
+	if claims, ok := contextAuthClaims(authedCtx); ok {
+		event.CredentialsType = claims.CredentialsType()
+	}
+	ss.audit(ctx, event, err)
+}
+
+func (ss *simpleServer) auditUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	startTime := time.Now()
+	call := &auditCall{}
+	resp, err := handler(contextWithAuditCall(ctx, call), req)
+	ss.auditRPC(ctx, call, info.FullMethod, startTime, err)
+	return resp, err
+}
+
+func (ss *simpleServer) auditStreamInterceptor(
+	srv interface{},
+	serverStream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+	call := &auditCall{}
+	err := handler(srv, ctxWrappedServerStream{serverStream, contextWithAuditCall(serverStream.Context(), call)})
+	ss.auditRPC(serverStream.Context(), call, info.FullMethod, startTime, err)
+	return err
+}
@@ -0,0 +1,298 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestJSONLinesAuditSink(t *testing.T) {
+	events := []AuditEvent{
+		{Time: time.Now().UTC(), Type: AuditEventAuthenticate, Entity: "alice", CredentialsType: "fake", Code: "OK"},
+		{Time: time.Now().UTC(), Type: AuditEventRPC, Method: testEchoMethod, Code: "PermissionDenied", LatencyMillis: 1.5},
+	}
+	readEvents := func(data []byte) []AuditEvent {
+		var read []AuditEvent
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var event AuditEvent
+			test.That(t, json.Unmarshal(scanner.Bytes(), &event), test.ShouldBeNil)
+			read = append(read, event)
+		}
+		return read
+	}
+
+	var buf bytes.Buffer
+	sink := NewJSONLinesAuditSink(&buf)
+	for _, event := range events {
+		test.That(t, sink.Audit(event), test.ShouldBeNil)
+	}
+	test.That(t, sink.Close(), test.ShouldBeNil)
+	test.That(t, readEvents(buf.Bytes()), test.ShouldResemble, events)
+
+	// files are appended to.
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for _, event := range events {
+		fileSink, err := NewJSONLinesFileAuditSink(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fileSink.Audit(event), test.ShouldBeNil)
+		test.That(t, fileSink.Close(), test.ShouldBeNil)
+	}
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readEvents(data), test.ShouldResemble, events)
+}
+
+func TestMongoDBAuditSink(t *testing.T) {
+	client := testutils.BackingMongoDBClient(t)
+	test.That(t, client.Database(mongodbAuditDBName).Drop(context.Background()), test.ShouldBeNil)
+	sink, err := NewMongoDBAuditSink(context.Background(), client, golog.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+
+	numEvents := mongodbAuditEventsBatchSize + 10
+	for i := 0; i < numEvents; i++ {
+		test.That(t, sink.Audit(AuditEvent{Time: time.Now(), Type: AuditEventRPC, Entity: "alice"}), test.ShouldBeNil)
+	}
+	// queued events are inserted before closing.
+	test.That(t, sink.Close(), test.ShouldBeNil)
+	test.That(t, sink.Audit(AuditEvent{}), test.ShouldBeError, "audit sink is closed")
+
+	count, err := client.Database(mongodbAuditDBName).Collection(mongodbAuditEventsCollName).
+		CountDocuments(context.Background(), bson.M{"entity": "alice", "type": AuditEventRPC})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, count, test.ShouldEqual, numEvents)
+}
+
+func TestMongoDBAuditSinkFullBuffer(t *testing.T) {
+	sink := &MongoDBAuditSink{events: make(chan AuditEvent, 1)}
+	test.That(t, sink.Audit(AuditEvent{Entity: "alice"}), test.ShouldBeNil)
+
+	// events are dropped rather than holding up requests.
+	done := make(chan error, 1)
+	go func() {
+		done <- sink.Audit(AuditEvent{Entity: "bob"})
+	}()
+	select {
+	case err := <-done:
+		test.That(t, err, test.ShouldBeNil)
+	case <-time.After(5 * time.Second):
+		t.Fatal("audit blocked on a full buffer")
+	}
+	test.That(t, <-sink.events, test.ShouldResemble, AuditEvent{Entity: "alice"})
+	test.That(t, sink.events, test.ShouldBeEmpty)
+}
+
+type recordingAuditSink struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (s *recordingAuditSink) Audit(event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+// take returns and forgets the events of the given type.
+func (s *recordingAuditSink) take(eventType AuditEventType) []AuditEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var taken, kept []AuditEvent
+	for _, event := range s.events {
+		if event.Type == eventType {
+			taken = append(taken, event)
+		} else {
+			kept = append(kept, event)
+		}
+	}
+	s.events = kept
+	return taken
+}
+
+func TestServerAuditSink(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	internalSignalingHost := "yeehaw"
+	// serve starts a server recording to sink. Its internal signaling answerer authenticates and
+	// makes calls too, so only the webrtc test enables WebRTC.
+	serve := func(t *testing.T, webrtcEnabled bool) (*recordingAuditSink, net.Addr) {
+		t.Helper()
+		sink := &recordingAuditSink{}
+		rpcServer, err := NewServer(
+			logger,
+			WithAuthHandler("fake", AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+				if payload != "secret" {
+					return nil, errors.New("wrong secret")
+				}
+				return map[string]string{}, nil
+			})),
+			WithAuthenticateToHandler(func(ctx context.Context, toEntity string) (map[string]string, error) {
+				return map[string]string{}, nil
+			}),
+			WithWebRTCServerOptions(WebRTCServerOptions{
+				Enable:                 webrtcEnabled,
+				InternalSignalingHosts: []string{internalSignalingHost},
+			}),
+			WithAuditSink(sink),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rpcServer.RegisterServiceServer(
+			context.Background(),
+			&pb.EchoService_ServiceDesc,
+			&echoserver.Server{},
+			pb.RegisterEchoServiceHandlerFromEndpoint,
+		), test.ShouldBeNil)
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		errChan := make(chan error)
+		go func() {
+			errChan <- rpcServer.Serve(listener)
+		}()
+		t.Cleanup(func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+			test.That(t, <-errChan, test.ShouldBeNil)
+		})
+		return sink, listener.Addr()
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		sink, addr := serve(t, false)
+		conn, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		authClient := rpcpb.NewAuthServiceClient(conn)
+		echoClient := pb.NewEchoServiceClient(conn)
+
+		_, err = authClient.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+			Entity:      "alice",
+			Credentials: &rpcpb.Credentials{Type: "fake", Payload: "guess"},
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+		authResp, err := authClient.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+			Entity:      "alice",
+			Credentials: &rpcpb.Credentials{Type: "fake", Payload: "secret"},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		events := sink.take(AuditEventAuthenticate)
+		test.That(t, events, test.ShouldHaveLength, 2)
+		test.That(t, events[0].Entity, test.ShouldEqual, "alice")
+		test.That(t, events[0].CredentialsType, test.ShouldEqual, CredentialsType("fake"))
+		test.That(t, events[0].Code, test.ShouldEqual, codes.PermissionDenied.String())
+		test.That(t, events[0].Error, test.ShouldContainSubstring, "wrong secret")
+		test.That(t, events[0].Method, test.ShouldEqual, "/proto.rpc.v1.AuthService/Authenticate")
+		test.That(t, events[0].ConnectionType, test.ShouldEqual, "grpc")
+		test.That(t, events[0].RemoteAddress, test.ShouldStartWith, "127.0.0.1:")
+		test.That(t, events[1].Code, test.ShouldEqual, codes.OK.String())
+		test.That(t, events[1].Error, test.ShouldBeEmpty)
+
+		authCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+authResp.GetAccessToken())
+		_, err = echoClient.Echo(authCtx, &pb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		stream, err := echoClient.EchoMultiple(authCtx, &pb.EchoMultipleRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		for {
+			if _, err := stream.Recv(); err != nil {
+				break
+			}
+		}
+		badCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer nope")
+		_, err = echoClient.Echo(badCtx, &pb.EchoRequest{Message: "hello"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+
+		events = sink.take(AuditEventTokenVerification)
+		test.That(t, events, test.ShouldHaveLength, 1)
+		test.That(t, events[0].Method, test.ShouldEqual, testEchoMethod)
+		test.That(t, events[0].Code, test.ShouldEqual, codes.Unauthenticated.String())
+
+		_, err = rpcpb.NewExternalAuthServiceClient(conn).AuthenticateTo(authCtx, &rpcpb.AuthenticateToRequest{Entity: "bob"})
+		test.That(t, err, test.ShouldBeNil)
+		events = sink.take(AuditEventAuthenticateTo)
+		test.That(t, events, test.ShouldHaveLength, 1)
+		test.That(t, events[0].Entity, test.ShouldEqual, "alice")
+		test.That(t, events[0].ToEntity, test.ShouldEqual, "bob")
+		test.That(t, events[0].CredentialsType, test.ShouldEqual, CredentialsType("fake"))
+
+		// every call is recorded along with who made it.
+		events = sink.take(AuditEventRPC)
+		var methods []string
+		for _, event := range events {
+			methods = append(methods, event.Method)
+		}
+		test.That(t, methods, test.ShouldResemble, []string{
+			"/proto.rpc.v1.AuthService/Authenticate",
+			"/proto.rpc.v1.AuthService/Authenticate",
+			testEchoMethod,
+			testEchoMultipleMethod,
+			testEchoMethod,
+			"/proto.rpc.v1.ExternalAuthService/AuthenticateTo",
+		})
+		test.That(t, events[0].Entity, test.ShouldBeEmpty)
+		test.That(t, events[0].Code, test.ShouldEqual, codes.PermissionDenied.String())
+		test.That(t, events[2].Entity, test.ShouldEqual, "alice")
+		test.That(t, events[2].CredentialsType, test.ShouldEqual, CredentialsType("fake"))
+		test.That(t, events[2].Code, test.ShouldEqual, codes.OK.String())
+		test.That(t, events[2].LatencyMillis, test.ShouldBeGreaterThan, 0)
+		test.That(t, events[3].Entity, test.ShouldEqual, "alice")
+		test.That(t, events[4].Entity, test.ShouldBeEmpty)
+		test.That(t, events[4].Code, test.ShouldEqual, codes.Unauthenticated.String())
+	})
+
+	t.Run("webrtc", func(t *testing.T) {
+		sink, addr := serve(t, true)
+		rtcConn, err := dialWebRTC(context.Background(), addr.String(), internalSignalingHost, dialOptions{
+			webrtcOpts: DialWebRTCOptions{
+				SignalingInsecure:   true,
+				SignalingAuthEntity: "alice",
+				SignalingCreds:      Credentials{Type: "fake", Payload: "secret"},
+			},
+			webrtcOptsSet: true,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		sink.take(AuditEventRPC)
+
+		_, err = pb.NewEchoServiceClient(rtcConn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		var events []AuditEvent
+		for _, event := range sink.take(AuditEventRPC) {
+			if event.Method == testEchoMethod {
+				events = append(events, event)
+			}
+		}
+		test.That(t, events, test.ShouldHaveLength, 1)
+		test.That(t, events[0].ConnectionType, test.ShouldEqual, "webrtc")
+		test.That(t, events[0].Entity, test.ShouldEqual, internalSignalingHost)
+		test.That(t, events[0].Code, test.ShouldEqual, codes.OK.String())
+	})
+}
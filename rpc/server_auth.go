@@ -43,8 +43,12 @@ type JWTClaims struct {
 	AuthCredentialsType CredentialsType   `json:"rpc_creds_type,omitempty"`
 	AuthMetadata        map[string]string `json:"rpc_auth_md,omitempty"`
 	ApplicationID       string            `json:"applicationId,omitempty"`
+	// TokenUse is set to "refresh" for refresh tokens, which cannot be used as access tokens.
+	TokenUse string `json:"rpc_token_use,omitempty"`
 }
 
+const tokenUseRefresh = "refresh"
+
 // Entity returns the entity from the claims' Subject.
 func (c JWTClaims) Entity() string {
 	return c.RegisteredClaims.Subject
@@ -70,7 +74,18 @@ func (c JWTClaims) Metadata() map[string]string {
 // ensure JWTClaims implements Claims.
 var _ Claims = JWTClaims{}
 
-func (ss *simpleServer) Authenticate(ctx context.Context, req *rpcpb.AuthenticateRequest) (*rpcpb.AuthenticateResponse, error) {
+func (ss *simpleServer) Authenticate(
+	ctx context.Context,
+	req *rpcpb.AuthenticateRequest,
+) (resp *rpcpb.AuthenticateResponse, err error) {
+	defer func() {
+		ss.audit(ctx, AuditEvent{
+			Type:            AuditEventAuthenticate,
+			Entity:          req.GetEntity(),
+			CredentialsType: CredentialsType(req.GetCredentials().GetType()),
+		}, err)
+	}()
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, errors.New("expected metadata")
@@ -99,19 +114,116 @@ func (ss *simpleServer) Authenticate(ctx context.Context, req *rpcpb.Authenticat
 
 	// We sign tokens destined for ourselves. If they are not for ourselves but for the entity, then
 	// AuthenticateTo should be used.
-	token, err := ss.signAccessTokenForEntity(forType, ss.authAudience, req.GetEntity(), authMD)
+	accessToken, refreshToken, err := ss.signTokensForEntity(forType, req.GetEntity(), authMD)
 	if err != nil {
 		return nil, err
 	}
 
 	return &rpcpb.AuthenticateResponse{
-		AccessToken: token,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (ss *simpleServer) RefreshToken(
+	ctx context.Context,
+	req *rpcpb.RefreshTokenRequest,
+) (resp *rpcpb.RefreshTokenResponse, err error) {
+	var claims JWTClaims
+	defer func() {
+		ss.audit(ctx, AuditEvent{
+			Type:            AuditEventRefreshToken,
+			Entity:          claims.Entity(),
+			CredentialsType: claims.CredentialsType(),
+		}, err)
+	}()
+
+	if ss.refreshTokenTTL == 0 {
+		return nil, status.Error(codes.Unimplemented, "refresh tokens are not issued by this server")
+	}
+
+	if _, err := jwt.ParseWithClaims(
+		req.GetRefreshToken(),
+		&claims,
+		ss.ownTokenVerificationKey,
+		jwt.WithValidMethods(validSigningMethods),
+	); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token: %s", err)
+	}
+	if claims.TokenUse != tokenUseRefresh {
+		return nil, status.Error(codes.Unauthenticated, "not a refresh token")
+	}
+	if !ss.audienceVerified(claims) {
+		return nil, status.Error(codes.Unauthenticated, "invalid audience")
+	}
+	if claims.Entity() == "" || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh token claims")
+	}
+	if err := ss.checkTokenRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	// Revoking the refresh token doubles as making sure that it is only exchanged once, even if
+	// it is presented to more than one server at the same time.
+	if ss.tokenRevocationCache != nil {
+		ss.tokenRevocationCache.forget(claims.ID)
+	}
+	if err := ss.tokenRevocationStore.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		if errors.Is(err, ErrTokenRevoked) {
+			return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+		}
+		ss.logger.Errorw("failed to revoke refresh token", "error", err)
+		return nil, status.Error(codes.Unavailable, "failed to refresh token")
+	}
+
+	accessToken, refreshToken, err := ss.signTokensForEntity(claims.CredentialsType(), claims.Entity(), claims.Metadata())
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
-func (ss *simpleServer) AuthenticateTo(ctx context.Context, req *rpcpb.AuthenticateToRequest) (*rpcpb.AuthenticateToResponse, error) {
+// signTokensForEntity signs an access token for ourselves and, if refresh tokens are enabled, a
+// refresh token to get the next one with.
+func (ss *simpleServer) signTokensForEntity(
+	forType CredentialsType,
+	entity string,
+	authMD map[string]string,
+) (accessToken, refreshToken string, err error) {
+	accessToken, err = ss.signToken(forType, ss.authAudience, entity, authMD, "", ss.accessTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	if ss.refreshTokenTTL == 0 {
+		return accessToken, "", nil
+	}
+	refreshToken, err = ss.signToken(forType, ss.authAudience, entity, authMD, tokenUseRefresh, ss.refreshTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
+}
+
+func (ss *simpleServer) AuthenticateTo(
+	ctx context.Context,
+	req *rpcpb.AuthenticateToRequest,
+) (resp *rpcpb.AuthenticateToResponse, err error) {
 	// Use the entity from the original authenticated call/payload.
 	entity, ok := ContextAuthEntity(ctx)
+	defer func() {
+		event := AuditEvent{
+			Type:     AuditEventAuthenticateTo,
+			Entity:   entity.Entity,
+			ToEntity: req.GetEntity(),
+		}
+		if claims, ok := contextAuthClaims(ctx); ok {
+			event.CredentialsType = claims.CredentialsType()
+		}
+		ss.audit(ctx, event, err)
+	}()
 	if !ok {
 		return nil, status.Error(codes.Internal, "entity should be available")
 	}
@@ -137,26 +249,43 @@ func (ss *simpleServer) signAccessTokenForEntity(
 	entity string,
 	authMD map[string]string,
 ) (string, error) {
-	// TODO(GOUT-13): expiration
-	// TODO(GOUT-12): refresh token
+	return ss.signToken(forType, audience, entity, authMD, "", 0)
+}
+
+// signToken signs a token for the entity that expires after ttl, if set.
+func (ss *simpleServer) signToken(
+	forType CredentialsType,
+	audience []string,
+	entity string,
+	authMD map[string]string,
+	tokenUse string,
+	ttl time.Duration,
+) (string, error) {
 	// TODO(GOUT-9): more complete info
-	token := jwt.NewWithClaims(ss.authKeyForJWTSigning.method, JWTClaims{
+	now := time.Now()
+	claims := JWTClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:  entity,
 			Audience: audience,
 			Issuer:   ss.authIssuer,
-			IssuedAt: jwt.NewNumericDate(time.Now()),
+			IssuedAt: jwt.NewNumericDate(now),
 			ID:       uuid.NewString(),
 		},
 		AuthCredentialsType: forType,
 		AuthMetadata:        authMD,
-	})
+		TokenUse:            tokenUse,
+	}
+	if ttl != 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	}
+	signingKey := ss.authKeys.signingKey()
+	token := jwt.NewWithClaims(signingKey.method, claims)
 
 	// Set the Key ID (kid) to allow the auth handlers to selectively choose which key was used
 	// to sign the token.
-	token.Header["kid"] = ss.authKeyForJWTSigning.id
+	token.Header["kid"] = signingKey.id
 
-	tokenString, err := token.SignedString(ss.authKeyForJWTSigning.privateKey)
+	tokenString, err := token.SignedString(signingKey.privateKey)
 	if err != nil {
 		ss.logger.Errorw("failed to sign JWT", "error", err)
 		return "", status.Error(codes.PermissionDenied, "failed to authenticate")
@@ -188,6 +317,7 @@ func (ss *simpleServer) authUnaryInterceptor(
 		if err != nil {
 			return nil, err
 		}
+		recordAuditCaller(nextCtx)
 		return handler(nextCtx, req)
 	}
 
@@ -196,6 +326,10 @@ func (ss *simpleServer) authUnaryInterceptor(
 	if err != nil {
 		return nil, err
 	}
+	recordAuditCaller(nextCtx)
+	if err := ss.authorize(nextCtx, info.FullMethod); err != nil {
+		return nil, err
+	}
 
 	return handler(nextCtx, req)
 }
@@ -216,6 +350,7 @@ func (ss *simpleServer) authStreamInterceptor(
 		if err != nil {
 			return err
 		}
+		recordAuditCaller(nextCtx)
 		serverStream = ctxWrappedServerStream{serverStream, nextCtx}
 		return handler(srv, serverStream)
 	}
@@ -225,6 +360,10 @@ func (ss *simpleServer) authStreamInterceptor(
 	if err != nil {
 		return err
 	}
+	recordAuditCaller(nextCtx)
+	if err := ss.authorize(nextCtx, info.FullMethod); err != nil {
+		return err
+	}
 
 	serverStream = ctxWrappedServerStream{serverStream, nextCtx}
 	return handler(srv, serverStream)
@@ -294,6 +433,15 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 	tokenString, err := TokenFromContext(ctx)
 	if err != nil {
 		// check TLS state
+		if ss.mtlsAuth != nil {
+			entity, mtlsErr := ss.mtlsAuth.authenticate(ctx)
+			if mtlsErr == nil {
+				return ContextWithAuthEntity(contextWithAuthClaims(ctx, mtlsClaims(entity.Entity)), entity), nil
+			}
+			if !errors.Is(mtlsErr, errNotTLSAuthed) {
+				err = multierr.Combine(err, mtlsErr)
+			}
+		}
 		if ss.tlsAuthHandler == nil {
 			return nil, err
 		}
@@ -328,6 +476,16 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 		return nil, err
 	}
 
+	nextCtx, err := ss.verifyToken(ctx, tokenString)
+	if err != nil {
+		ss.audit(ctx, AuditEvent{Type: AuditEventTokenVerification}, err)
+		return nil, err
+	}
+	return nextCtx, nil
+}
+
+// verifyToken verifies the given access token and returns a context for the entity it is for.
+func (ss *simpleServer) verifyToken(ctx context.Context, tokenString string) (context.Context, error) {
 	var claims JWTClaims
 	var handlers credAuthHandlers
 	if _, err := jwt.ParseWithClaims(
@@ -343,27 +501,7 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 			if handlers.TokenVerificationKeyProvider != nil {
 				return handlers.TokenVerificationKeyProvider.TokenVerificationKey(ctx, token)
 			}
-
-			// signed by us, so we always have a kid
-			keyID, ok := token.Header["kid"].(string)
-			if !ok {
-				return nil, errors.New("kid header not in token header")
-			}
-
-			keyData, ok := ss.authKeys[keyID]
-			if !ok {
-				return nil, fmt.Errorf("this server did not sign this JWT with kid %q", keyID)
-			}
-
-			if keyData.method == nil {
-				ss.logger.Errorw("invariant: auth key data has no method", "kid", keyID)
-				return "", errors.New("internal server error")
-			}
-			if token.Method != keyData.method {
-				return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
-			}
-
-			return keyData.publicKey, nil
+			return ss.ownTokenVerificationKey(token)
 		},
 		jwt.WithValidMethods(validSigningMethods),
 	); err != nil {
@@ -373,15 +511,7 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 	// Audience verification is critical for security. Without it, we have a higher chance
 	// of validating a JWT is valid, but not that it is intended for us. Of course, that means
 	// we trust whomever owns the private keys to signing access tokens.
-	audVerified := false
-	for _, allowdAud := range ss.authAudience {
-		if claims.RegisteredClaims.VerifyAudience(allowdAud, true) {
-			audVerified = true
-			break
-		}
-	}
-
-	if !audVerified {
+	if !ss.audienceVerified(claims) {
 		var claimAudience []byte
 		err := claims.RegisteredClaims.Audience.UnmarshalJSON(claimAudience)
 		if err != nil {
@@ -401,8 +531,7 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 	// Note(erd): may want to verify issuers in the future where the claims/scope are
 	// treated differently if it comes down to permissions encoded in a JWT.
 
-	err = claims.Valid()
-	if err != nil {
+	if err := claims.Valid(); err != nil {
 		ss.logger.Errorw("invalid claims",
 			"error", err,
 			"registered_claims", claims.RegisteredClaims)
@@ -412,11 +541,17 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 	claimsEntity := claims.Entity()
 	if claimsEntity == "" {
 		ss.logger.Errorw("invalid claims entity: expected entity (sub) in claims",
-			"error", err,
 			"registered_claims", claims.RegisteredClaims)
 		return nil, status.Errorf(codes.Unauthenticated, "expected entity (sub) in claims")
 	}
 
+	if claims.TokenUse == tokenUseRefresh {
+		return nil, status.Error(codes.Unauthenticated, "refresh tokens cannot be used for authentication")
+	}
+	if err := ss.checkTokenRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+
 	var entityData interface{}
 	if handlers.EntityDataLoader != nil {
 		data, err := handlers.EntityDataLoader.EntityData(ctx, claims)
@@ -429,5 +564,66 @@ func (ss *simpleServer) ensureAuthed(ctx context.Context) (context.Context, erro
 		entityData = data
 	}
 
-	return ContextWithAuthEntity(ctx, EntityInfo{claimsEntity, entityData}), nil
+	return ContextWithAuthEntity(contextWithAuthClaims(ctx, claims), EntityInfo{claimsEntity, entityData}), nil
+}
+
+// ownTokenVerificationKey returns the key to verify a token signed by this server with.
+func (ss *simpleServer) ownTokenVerificationKey(token *jwt.Token) (interface{}, error) {
+	// signed by us, so we always have a kid
+	keyID, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("kid header not in token header")
+	}
+
+	keyData, ok := ss.authKeys.verificationKey(keyID)
+	if !ok {
+		return nil, fmt.Errorf("this server did not sign this JWT with kid %q or the key has retired", keyID)
+	}
+
+	if keyData.method == nil {
+		ss.logger.Errorw("invariant: auth key data has no method", "kid", keyID)
+		return "", errors.New("internal server error")
+	}
+	if token.Method != keyData.method {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+
+	return keyData.publicKey, nil
+}
+
+// audienceVerified returns whether the claims are intended for us.
+func (ss *simpleServer) audienceVerified(claims JWTClaims) bool {
+	for _, allowdAud := range ss.authAudience {
+		if claims.RegisteredClaims.VerifyAudience(allowdAud, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTokenRevoked returns an error if the token with the given claims has been revoked.
+func (ss *simpleServer) checkTokenRevoked(ctx context.Context, claims JWTClaims) error {
+	if ss.tokenRevocationStore == nil {
+		return nil
+	}
+	if ss.tokenRevocationCache != nil && ss.tokenRevocationCache.notRevoked(claims.ID) {
+		return nil
+	}
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	revoked, err := ss.tokenRevocationStore.IsRevoked(ctx, claims.ID, claims.Entity(), issuedAt)
+	if err != nil {
+		// a token that cannot be checked may have been revoked so it is not let through.
+		ss.logger.Errorw("failed to check if token is revoked", "error", err)
+		return status.Error(codes.Unavailable, "failed to check if token is revoked")
+	}
+	if revoked {
+		return status.Error(codes.Unauthenticated, "token has been revoked")
+	}
+	if ss.tokenRevocationCache != nil {
+		ss.tokenRevocationCache.rememberNotRevoked(claims.ID)
+	}
+	return nil
 }
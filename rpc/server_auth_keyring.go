@@ -0,0 +1,358 @@
+package rpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/pkg/errors"
+
+	"go.viam.com/utils"
+	"go.viam.com/utils/jwks"
+)
+
+const (
+	// JWKSPath is where a server serves the public keys that verify the tokens it signs.
+	JWKSPath = "/.well-known/jwks.json"
+	// OIDCDiscoveryPath is where a server serves the OpenID Connect discovery document pointing at
+	// JWKSPath. This lets jwks.NewCachingOIDCJWKKeyProvider use the server's issuer.
+	OIDCDiscoveryPath = "/.well-known/openid-configuration"
+)
+
+// AuthKeys are the keys a server signs and verifies tokens with.
+type AuthKeys struct {
+	// Signer signs new tokens. It must be an ed25519.PrivateKey or an *rsa.PrivateKey.
+	Signer crypto.Signer
+	// Verifiers are other keys, of the same kinds, whose tokens still verify. This includes keys
+	// that were replaced and the one that will sign next, so that it is published beforehand.
+	Verifiers []crypto.Signer
+}
+
+// An AuthKeySource provides the keys servers sign and verify tokens with when they are kept
+// outside of the server, such as in a store shared by every instance behind a load balancer so
+// that a token signed by one instance verifies on the others. Rotating the keys is up to the
+// source.
+type AuthKeySource interface {
+	AuthKeys(ctx context.Context) (AuthKeys, error)
+}
+
+// loadAuthKeys returns the keys the source provides keyed by their IDs, along with the ID of the
+// signing key.
+func loadAuthKeys(ctx context.Context, source AuthKeySource) (map[string]authKeyData, string, error) {
+	authKeys, err := source.AuthKeys(ctx)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get auth keys")
+	}
+	if authKeys.Signer == nil {
+		return nil, "", errors.New("auth key source returned no signing key")
+	}
+	keys := make(map[string]authKeyData, len(authKeys.Verifiers)+1)
+	var signerID string
+	for i, privKey := range append([]crypto.Signer{authKeys.Signer}, authKeys.Verifiers...) {
+		var keyData authKeyData
+		switch privKey := privKey.(type) {
+		case ed25519.PrivateKey:
+			pubKey := privKey.Public()
+			keyData = authKeyData{
+				id:         ED25519PublicKeyThumbprint(pubKey.(ed25519.PublicKey)),
+				method:     jwt.SigningMethodEdDSA,
+				privateKey: privKey,
+				publicKey:  pubKey,
+			}
+		case *rsa.PrivateKey:
+			thumbprint, err := RSAPublicKeyThumbprint(&privKey.PublicKey)
+			if err != nil {
+				return nil, "", err
+			}
+			keyData = authKeyData{
+				id:         thumbprint,
+				method:     jwt.SigningMethodRS256,
+				privateKey: privKey,
+				publicKey:  &privKey.PublicKey,
+			}
+		default:
+			return nil, "", errors.Errorf("unsupported auth key type %T", privKey)
+		}
+		if i == 0 {
+			signerID = keyData.id
+		}
+		keys[keyData.id] = keyData
+	}
+	return keys, signerID, nil
+}
+
+// An authKeyRing holds the keys a server signs and verifies tokens with. Tokens are signed with the
+// newest key. Keys that were replaced keep verifying tokens until they retire. When rotating, the
+// key that will be used next is created one rotation ahead so that it is published before any
+// token is signed with it. Rotated keys only live in the memory of the server, so servers sharing
+// keys get them from an AuthKeySource instead.
+type authKeyRing struct {
+	mu       sync.RWMutex
+	keys     map[string]authKeyData
+	retireAt map[string]time.Time
+	signerID string
+	nextID   string
+}
+
+func newAuthKeyRing(keys map[string]authKeyData, signerID string) *authKeyRing {
+	return &authKeyRing{
+		keys:     keys,
+		retireAt: map[string]time.Time{},
+		signerID: signerID,
+	}
+}
+
+// signingKey returns the key to sign new tokens with.
+func (kr *authKeyRing) signingKey() authKeyData {
+	if kr == nil {
+		return authKeyData{}
+	}
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.keys[kr.signerID]
+}
+
+// verificationKey returns the key with the given ID if it has not retired.
+func (kr *authKeyRing) verificationKey(keyID string) (authKeyData, bool) {
+	if kr == nil {
+		return authKeyData{}, false
+	}
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	if retireAt, ok := kr.retireAt[keyID]; ok && !time.Now().Before(retireAt) {
+		return authKeyData{}, false
+	}
+	keyData, ok := kr.keys[keyID]
+	return keyData, ok
+}
+
+// replace swaps all keys for the given ones, as provided by an AuthKeySource.
+func (kr *authKeyRing) replace(keys map[string]authKeyData, signerID string) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.keys = keys
+	kr.retireAt = map[string]time.Time{}
+	kr.signerID = signerID
+	kr.nextID = ""
+}
+
+// prepareRotation creates the key that the next rotation will switch to so that it can be
+// published ahead of time.
+func (kr *authKeyRing) prepareRotation() error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	return kr.ensureNextKey()
+}
+
+// rotate makes the next key the signing key and creates a new next key. The replaced signing key
+// retires after retireAfter and keys that have retired are forgotten.
+func (kr *authKeyRing) rotate(retireAfter time.Duration) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if err := kr.ensureNextKey(); err != nil {
+		return err
+	}
+	now := time.Now()
+	kr.retireAt[kr.signerID] = now.Add(retireAfter)
+	kr.signerID = kr.nextID
+	kr.nextID = ""
+	if err := kr.ensureNextKey(); err != nil {
+		return err
+	}
+
+	for keyID, retireAt := range kr.retireAt {
+		if !now.Before(retireAt) {
+			delete(kr.keys, keyID)
+			delete(kr.retireAt, keyID)
+		}
+	}
+	return nil
+}
+
+func (kr *authKeyRing) ensureNextKey() error {
+	if kr.nextID != "" {
+		return nil
+	}
+	next, err := generateAuthKey(kr.keys[kr.signerID])
+	if err != nil {
+		return err
+	}
+	kr.keys[next.id] = next
+	kr.nextID = next.id
+	return nil
+}
+
+// keySet returns the public keys that have not retired, including the next signing key.
+func (kr *authKeyRing) keySet() (jwks.KeySet, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	now := time.Now()
+	keySet := jwk.NewSet()
+	for keyID, keyData := range kr.keys {
+		if retireAt, ok := kr.retireAt[keyID]; ok && !now.Before(retireAt) {
+			continue
+		}
+		key, err := jwk.New(keyData.publicKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := key.Set(jwk.KeyIDKey, keyData.id); err != nil {
+			return nil, err
+		}
+		if err := key.Set(jwk.AlgorithmKey, keyData.method.Alg()); err != nil {
+			return nil, err
+		}
+		if err := key.Set(jwk.KeyUsageKey, jwk.ForSignature); err != nil {
+			return nil, err
+		}
+		keySet.Add(key)
+	}
+	return keySet, nil
+}
+
+// generateAuthKey returns a new key of the same kind as like.
+func generateAuthKey(like authKeyData) (authKeyData, error) {
+	switch privKey := like.privateKey.(type) {
+	case ed25519.PrivateKey:
+		pubKey, newPrivKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return authKeyData{}, err
+		}
+		return authKeyData{
+			id:         ED25519PublicKeyThumbprint(pubKey),
+			method:     jwt.SigningMethodEdDSA,
+			privateKey: newPrivKey,
+			publicKey:  pubKey,
+		}, nil
+	case *rsa.PrivateKey:
+		newPrivKey, err := rsa.GenerateKey(rand.Reader, privKey.N.BitLen())
+		if err != nil {
+			return authKeyData{}, err
+		}
+		keyID, err := RSAPublicKeyThumbprint(&newPrivKey.PublicKey)
+		if err != nil {
+			return authKeyData{}, err
+		}
+		return authKeyData{
+			id:         keyID,
+			method:     like.method,
+			privateKey: newPrivKey,
+			publicKey:  &newPrivKey.PublicKey,
+		}, nil
+	default:
+		return authKeyData{}, errors.Errorf("do not know how to generate a key like %T", privKey)
+	}
+}
+
+// rotateAuthKeysPeriodically rotates the signing key every interval until ctx is done.
+func (ss *simpleServer) rotateAuthKeysPeriodically(ctx context.Context, interval, retireAfter time.Duration) {
+	ss.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		for utils.SelectContextOrWait(ctx, interval) {
+			if err := ss.authKeys.rotate(retireAfter); err != nil {
+				ss.logger.Errorw("failed to rotate auth key", "error", err)
+				continue
+			}
+			ss.logger.Debugw("rotated auth key", "kid", ss.authKeys.signingKey().id)
+		}
+	}, ss.activeBackgroundWorkers.Done)
+}
+
+// refreshAuthKeysPeriodically replaces the keys with those of the source every interval until ctx
+// is done.
+func (ss *simpleServer) refreshAuthKeysPeriodically(ctx context.Context, source AuthKeySource, interval time.Duration) {
+	ss.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		for utils.SelectContextOrWait(ctx, interval) {
+			keys, signerID, err := loadAuthKeys(ctx, source)
+			if err != nil {
+				ss.logger.Errorw("failed to refresh auth keys", "error", err)
+				continue
+			}
+			ss.authKeys.replace(keys, signerID)
+		}
+	}, ss.activeBackgroundWorkers.Done)
+}
+
+// A JWKSServer serves the public keys that verify the tokens it signs. Servers returned by
+// NewServer implement it; callers type-assert a Server to it.
+type JWKSServer interface {
+	// JWKSHandler returns a handler serving the public keys that verify the tokens this server
+	// signs at JWKSPath along with an OpenID Connect discovery document at OIDCDiscoveryPath.
+	// The all-in-one handler serves it too.
+	JWKSHandler() http.Handler
+}
+
+var _ JWKSServer = (*simpleServer)(nil)
+
+// JWKSHandler serves the public keys that verify the tokens this server signs at JWKSPath and an
+// OpenID Connect discovery document for the server's issuer at OIDCDiscoveryPath.
+func (ss *simpleServer) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ss.authKeys == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var doc interface{}
+		switch r.URL.Path {
+		case JWKSPath:
+			keySet, err := ss.authKeys.keySet()
+			if err != nil {
+				ss.logger.Errorw("failed to make key set", "error", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			doc = keySet
+		case OIDCDiscoveryPath:
+			doc = map[string]interface{}{
+				"issuer":   ss.authIssuer,
+				"jwks_uri": ss.jwksURI(r),
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		out, err := json.Marshal(doc)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// keep consumers close to the newest keys.
+		w.Header().Set("Cache-Control", "max-age=300")
+		if _, err := w.Write(out); err != nil {
+			ss.logger.Debugw("failed to write response", "error", err)
+		}
+	})
+}
+
+// jwksURI returns where the JWKS is served. Issuers that are URLs are expected to serve it
+// themselves, otherwise it is wherever the request came in.
+func (ss *simpleServer) jwksURI(r *http.Request) string {
+	if issuerURL, err := url.Parse(ss.authIssuer); err == nil &&
+		(issuerURL.Scheme == "http" || issuerURL.Scheme == "https") && issuerURL.Host != "" {
+		return strings.TrimSuffix(ss.authIssuer, "/") + JWKSPath
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + JWKSPath
+}
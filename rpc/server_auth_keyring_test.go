@@ -0,0 +1,261 @@
+package rpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang-jwt/jwt/v4"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/utils/jwks"
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestAuthKeyRing(t *testing.T) {
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	first := authKeyData{
+		id:         ED25519PublicKeyThumbprint(privKey.Public().(ed25519.PublicKey)),
+		method:     jwt.SigningMethodEdDSA,
+		privateKey: privKey,
+		publicKey:  privKey.Public(),
+	}
+	kr := newAuthKeyRing(map[string]authKeyData{first.id: first}, first.id)
+
+	keyIDs := func() []string {
+		keySet, err := kr.keySet()
+		test.That(t, err, test.ShouldBeNil)
+		var ids []string
+		for i := 0; i < keySet.Len(); i++ {
+			key, _ := keySet.Get(i)
+			test.That(t, key.Algorithm(), test.ShouldEqual, jwt.SigningMethodEdDSA.Alg())
+			ids = append(ids, key.KeyID())
+		}
+		return ids
+	}
+
+	// the next key is published before it is used.
+	test.That(t, kr.prepareRotation(), test.ShouldBeNil)
+	test.That(t, kr.signingKey().id, test.ShouldEqual, first.id)
+	test.That(t, keyIDs(), test.ShouldHaveLength, 2)
+	next := kr.nextID
+	test.That(t, keyIDs(), test.ShouldContain, next)
+
+	test.That(t, kr.rotate(50*time.Millisecond), test.ShouldBeNil)
+	test.That(t, kr.signingKey().id, test.ShouldEqual, next)
+	_, ok := kr.verificationKey(first.id)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, keyIDs(), test.ShouldHaveLength, 3)
+
+	// replaced keys stop verifying once retired and are forgotten on the next rotation.
+	time.Sleep(50 * time.Millisecond)
+	_, ok = kr.verificationKey(first.id)
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, keyIDs(), test.ShouldHaveLength, 2)
+	test.That(t, keyIDs(), test.ShouldNotContain, first.id)
+	test.That(t, kr.rotate(time.Hour), test.ShouldBeNil)
+	kr.mu.RLock()
+	test.That(t, kr.keys, test.ShouldHaveLength, 3)
+	test.That(t, kr.keys, test.ShouldNotContainKey, first.id)
+	kr.mu.RUnlock()
+
+	// keys are replaced by ones of the same kind.
+	//nolint:gosec
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	test.That(t, err, test.ShouldBeNil)
+	generated, err := generateAuthKey(authKeyData{method: jwt.SigningMethodRS256, privateKey: rsaKey})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, generated.method, test.ShouldEqual, jwt.SigningMethodRS256)
+	test.That(t, generated.privateKey.(*rsa.PrivateKey).N.BitLen(), test.ShouldEqual, 1024)
+	test.That(t, generated.Validate(), test.ShouldBeNil)
+
+	var nilRing *authKeyRing
+	_, ok = nilRing.verificationKey(first.id)
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+func TestServerAuthKeyRotation(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	issuer := fmt.Sprintf("http://%s", listener.Addr().String())
+
+	rpcServer, err := NewServer(
+		logger,
+		WithAuthHandler("fake", AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+			return map[string]string{}, nil
+		})),
+		WithAuthIssuer(issuer),
+		WithAuthKeyRotation(time.Hour, time.Hour),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	authClient := rpcpb.NewAuthServiceClient(conn)
+	echoClient := pb.NewEchoServiceClient(conn)
+
+	authenticate := func() (string, string) {
+		resp, err := authClient.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+			Entity:      "entity",
+			Credentials: &rpcpb.Credentials{Type: "fake"},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		token, _, err := jwt.NewParser().ParseUnverified(resp.GetAccessToken(), &JWTClaims{})
+		test.That(t, err, test.ShouldBeNil)
+		return resp.GetAccessToken(), token.Header["kid"].(string)
+	}
+	echo := func(token string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		_, err := echoClient.Echo(ctx, &pb.EchoRequest{Message: "hello"})
+		return err
+	}
+
+	oldToken, oldKeyID := authenticate()
+
+	// other services can verify tokens with the published keys.
+	keyProvider, err := jwks.NewCachingOIDCJWKKeyProvider(context.Background(), issuer)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, keyProvider.Close(), test.ShouldBeNil)
+	}()
+	_, err = keyProvider.LookupKey(context.Background(), oldKeyID, jwt.SigningMethodEdDSA.Alg())
+	test.That(t, err, test.ShouldBeNil)
+
+	ss, ok := rpcServer.(*simpleServer)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, ss.authKeys.rotate(time.Hour), test.ShouldBeNil)
+
+	newToken, newKeyID := authenticate()
+	test.That(t, newKeyID, test.ShouldNotEqual, oldKeyID)
+	test.That(t, echo(oldToken), test.ShouldBeNil)
+	test.That(t, echo(newToken), test.ShouldBeNil)
+
+	// the new key was published before it was used so cached keys already include it.
+	_, err = keyProvider.LookupKey(context.Background(), newKeyID, jwt.SigningMethodEdDSA.Alg())
+	test.That(t, err, test.ShouldBeNil)
+
+	resp, err := http.Post(issuer+JWKSPath, "application/json", nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusMethodNotAllowed)
+}
+
+func TestServerAuthKeyRotationSchedule(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	_, err := NewServer(logger, WithAuthKeyRotation(0, time.Hour))
+	test.That(t, err, test.ShouldBeError, "key rotation interval and retirement must be positive")
+
+	rpcServer, err := NewServer(logger, WithAuthKeyRotation(10*time.Millisecond, time.Hour))
+	test.That(t, err, test.ShouldBeNil)
+	ss, ok := rpcServer.(*simpleServer)
+	test.That(t, ok, test.ShouldBeTrue)
+	firstKeyID := ss.authKeys.signingKey().id
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, ss.authKeys.signingKey().id, test.ShouldNotEqual, firstKeyID)
+	})
+	test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+}
+
+type fakeAuthKeySource struct {
+	mu   sync.Mutex
+	keys AuthKeys
+}
+
+func (src *fakeAuthKeySource) AuthKeys(ctx context.Context) (AuthKeys, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	return src.keys, nil
+}
+
+func (src *fakeAuthKeySource) set(keys AuthKeys) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	src.keys = keys
+}
+
+func TestServerAuthKeySource(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	_, first, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	//nolint:gosec
+	next, err := rsa.GenerateKey(rand.Reader, 1024)
+	test.That(t, err, test.ShouldBeNil)
+	source := &fakeAuthKeySource{keys: AuthKeys{Signer: first, Verifiers: []crypto.Signer{next}}}
+
+	_, err = NewServer(logger, WithAuthKeySource(source, time.Hour), WithAuthKeyRotation(time.Hour, time.Hour))
+	test.That(t, err, test.ShouldBeError, "cannot use WithAuthKeySource with other auth key options")
+	_, err = NewServer(logger, WithAuthKeySource(&fakeAuthKeySource{}, time.Hour))
+	test.That(t, err, test.ShouldBeError, "auth key source returned no signing key")
+
+	// instances sharing a source sign and verify with the same keys.
+	var servers []*simpleServer
+	for i := 0; i < 2; i++ {
+		rpcServer, err := NewServer(logger, WithAuthKeySource(source, 10*time.Millisecond))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		}()
+		ss, ok := rpcServer.(*simpleServer)
+		test.That(t, ok, test.ShouldBeTrue)
+		servers = append(servers, ss)
+	}
+	firstID := ED25519PublicKeyThumbprint(first.Public().(ed25519.PublicKey))
+	nextID, err := RSAPublicKeyThumbprint(&next.PublicKey)
+	test.That(t, err, test.ShouldBeNil)
+	for _, ss := range servers {
+		test.That(t, ss.authKeys.signingKey().id, test.ShouldEqual, firstID)
+		_, ok := ss.authKeys.verificationKey(nextID)
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+
+	// rotating the keys of the source rotates them for every instance.
+	source.set(AuthKeys{Signer: next})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		for _, ss := range servers {
+			test.That(tb, ss.authKeys.signingKey().id, test.ShouldEqual, nextID)
+			test.That(tb, ss.authKeys.signingKey().method, test.ShouldEqual, jwt.SigningMethodRS256)
+			_, ok := ss.authKeys.verificationKey(firstID)
+			test.That(tb, ok, test.ShouldBeFalse)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// CredentialsTypeMTLS is for entities authenticated by a client certificate. The certificate is
+// presented during the TLS handshake of a direct connection or as the DTLS certificate of a
+// WebRTC connection, so the credentials themselves carry no payload.
+const CredentialsTypeMTLS = CredentialsType("mtls")
+
+// An MTLSEntityMapper maps a verified client certificate to the entity it authenticates.
+type MTLSEntityMapper func(ctx context.Context, cert *x509.Certificate) (EntityInfo, error)
+
+// MTLSEntityData is the entity data of entities authenticated by DefaultMTLSEntityMapper.
+type MTLSEntityData struct {
+	Certificate *x509.Certificate
+	// Fingerprint is the SHA-256 fingerprint of the certificate in the colon separated form used
+	// by SDP. Over WebRTC, this is the fingerprint the peer connection was negotiated with.
+	Fingerprint string
+}
+
+// DefaultMTLSEntityMapper identifies a certificate by its SPIFFE ID. Certificates without one
+// are identified by their first URI SAN and then by their first DNS SAN.
+func DefaultMTLSEntityMapper(ctx context.Context, cert *x509.Certificate) (EntityInfo, error) {
+	var entity string
+	if spiffeID, ok := SPIFFEIDFromCertificate(cert); ok {
+		entity = spiffeID
+	} else if len(cert.URIs) != 0 {
+		entity = cert.URIs[0].String()
+	} else if len(cert.DNSNames) != 0 {
+		entity = cert.DNSNames[0]
+	} else {
+		return EntityInfo{}, errors.New("client certificate has no SAN to identify it by")
+	}
+	return EntityInfo{
+		Entity: entity,
+		Data: MTLSEntityData{
+			Certificate: cert,
+			Fingerprint: CertificateFingerprint(cert),
+		},
+	}, nil
+}
+
+// SPIFFEIDFromCertificate returns the SPIFFE ID of an X.509 SVID, which is its only URI SAN.
+func SPIFFEIDFromCertificate(cert *x509.Certificate) (string, bool) {
+	if len(cert.URIs) != 1 || !strings.EqualFold(cert.URIs[0].Scheme, "spiffe") || cert.URIs[0].Host == "" {
+		return "", false
+	}
+	return cert.URIs[0].String(), true
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of the certificate in the colon
+// separated form used by SDP.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// An mtlsAuthenticator authenticates the client certificate of a connection.
+type mtlsAuthenticator struct {
+	clientCAs    *x509.CertPool
+	entityMapper MTLSEntityMapper
+}
+
+// authenticate verifies the client certificate of the connection the context is for and maps
+// it to an entity. It returns errNotTLSAuthed if there is no client certificate.
+func (ma *mtlsAuthenticator) authenticate(ctx context.Context) (EntityInfo, error) {
+	certs, err := peerCertificates(ctx)
+	if err != nil {
+		return EntityInfo{}, err
+	}
+	if len(certs) == 0 {
+		return EntityInfo{}, errNotTLSAuthed
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         ma.clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return EntityInfo{}, status.Errorf(codes.Unauthenticated, "invalid client certificate: %s", err)
+	}
+
+	entity, err := ma.entityMapper(ctx, certs[0])
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return EntityInfo{}, err
+		}
+		return EntityInfo{}, status.Errorf(codes.Unauthenticated, "cannot identify client certificate: %s", err)
+	}
+	return entity, nil
+}
+
+// mtlsClaims are the claims of an entity authenticated by its client certificate.
+func mtlsClaims(entity string) Claims {
+	return JWTClaims{
+		RegisteredClaims:    jwt.RegisteredClaims{Subject: entity},
+		AuthCredentialsType: CredentialsTypeMTLS,
+	}
+}
+
+// Authenticate authenticates the entity of the client certificate of the connection the
+// request came in on.
+func (ma *mtlsAuthenticator) Authenticate(ctx context.Context, entity, payload string) (map[string]string, error) {
+	info, err := ma.authenticate(ctx)
+	if err != nil {
+		if errors.Is(err, errNotTLSAuthed) {
+			return nil, errInvalidCredentials
+		}
+		return nil, err
+	}
+	if info.Entity != entity {
+		return nil, errCannotAuthEntity
+	}
+	return map[string]string{}, nil
+}
+
+// peerCertificates returns the certificates the peer of a connection presented, leaf first. On
+// direct connections these come from the TLS handshake. On WebRTC connections, the DTLS
+// handshake proves the peer owns the certificate that matches the fingerprint in its offer but
+// only the leaf certificate is available, so client certificates must be issued directly by
+// one of the trusted CAs.
+func peerCertificates(ctx context.Context) ([]*x509.Certificate, error) {
+	if p, ok := peer.FromContext(ctx); ok && p.AuthInfo != nil {
+		if authInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			return authInfo.State.PeerCertificates, nil
+		}
+	}
+	pc, ok := ContextPeerConnection(ctx)
+	if !ok || pc.SCTP() == nil {
+		return nil, nil
+	}
+	certDER := pc.SCTP().Transport().GetRemoteCertificate()
+	if len(certDER) == 0 {
+		return nil, nil
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid client certificate: %s", err)
+	}
+	return []*x509.Certificate{cert}, nil
+}
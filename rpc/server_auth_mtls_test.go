@@ -0,0 +1,272 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	test.That(t, err, test.ShouldBeNil)
+	cert, err := x509.ParseCertificate(certDER)
+	test.That(t, err, test.ShouldBeNil)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	test.That(t, err, test.ShouldBeNil)
+	leaf, err := x509.ParseCertificate(certDER)
+	test.That(t, err, test.ShouldBeNil)
+	return tls.Certificate{Certificate: [][]byte{certDER}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestDefaultMTLSEntityMapper(t *testing.T) {
+	spiffeID, err := url.Parse("spiffe://example.org/service/echo")
+	test.That(t, err, test.ShouldBeNil)
+	otherURI, err := url.Parse("urn:example:echo")
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name     string
+		cert     *x509.Certificate
+		spiffeID string
+		entity   string
+	}{
+		{"spiffe id", &x509.Certificate{URIs: []*url.URL{spiffeID}, DNSNames: []string{"echo"}}, spiffeID.String(), spiffeID.String()},
+		{"other uri", &x509.Certificate{URIs: []*url.URL{otherURI}, DNSNames: []string{"echo"}}, "", otherURI.String()},
+		{"many uris", &x509.Certificate{URIs: []*url.URL{otherURI, spiffeID}}, "", otherURI.String()},
+		{"dns name", &x509.Certificate{DNSNames: []string{"echo", "echo2"}}, "", "echo"},
+		{"no san", &x509.Certificate{Subject: pkix.Name{CommonName: "echo"}}, "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			id, ok := SPIFFEIDFromCertificate(tc.cert)
+			test.That(t, ok, test.ShouldEqual, tc.spiffeID != "")
+			test.That(t, id, test.ShouldEqual, tc.spiffeID)
+
+			entity, err := DefaultMTLSEntityMapper(context.Background(), tc.cert)
+			if tc.entity == "" {
+				test.That(t, err, test.ShouldBeError, "client certificate has no SAN to identify it by")
+				return
+			}
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, entity.Entity, test.ShouldEqual, tc.entity)
+			test.That(t, entity.Data.(MTLSEntityData).Certificate, test.ShouldEqual, tc.cert)
+		})
+	}
+
+	ca := newTestCA(t)
+	cert := ca.issue(t, &x509.Certificate{DNSNames: []string{"echo"}})
+	fingerprint := CertificateFingerprint(cert.Leaf)
+	test.That(t, fingerprint, test.ShouldHaveLength, 32*3-1)
+	webrtcCert, err := webrtcCertificate(cert)
+	test.That(t, err, test.ShouldBeNil)
+	fingerprints, err := webrtcCert.GetFingerprints()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fingerprints[0].Algorithm, test.ShouldEqual, "sha-256")
+	test.That(t, fingerprints[0].Value, test.ShouldEqual, fingerprint)
+}
+
+func TestServerMTLSAuth(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	ca := newTestCA(t)
+	serverCert := ca.issue(t, &x509.Certificate{
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	test.That(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: serverCert.Certificate[0],
+	}), 0o600), test.ShouldBeNil)
+	keyDER, err := x509.MarshalECPrivateKey(serverCert.PrivateKey.(*ecdsa.PrivateKey))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: keyDER,
+	}), 0o600), test.ShouldBeNil)
+
+	spiffeID, err := url.Parse("spiffe://example.org/client")
+	test.That(t, err, test.ShouldBeNil)
+	clientCert := ca.issue(t, &x509.Certificate{
+		URIs:        []*url.URL{spiffeID},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	_, err = NewServer(logger, WithMTLSAuthHandler(nil, nil))
+	test.That(t, err, test.ShouldBeError, "client CAs must be set")
+
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithMTLSAuthHandler(ca.pool, nil),
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+		}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	echoServer := &echoserver.Server{
+		MustContextAuthEntity: func(ctx context.Context) echoserver.RPCEntityInfo {
+			ent := MustContextAuthEntity(ctx)
+			return echoserver.RPCEntityInfo{
+				Entity: ent.Entity,
+				Data:   ent.Data,
+			}
+		},
+	}
+	echoServer.SetExpectedAuthEntity(spiffeID.String())
+	echoServer.SetAuthorized(true)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		echoServer,
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.ServeTLS(listener, certFile, keyFile, nil)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	tlsConfig := &tls.Config{RootCAs: ca.pool, MinVersion: tls.VersionTLS12}
+	dial := func(opts ...DialOption) ClientConn {
+		conn, err := Dial(context.Background(), listener.Addr().String(), logger, append([]DialOption{
+			WithTLSConfig(tlsConfig),
+			WithWebRTCOptions(DialWebRTCOptions{Disable: true}),
+			WithDialMulticastDNSOptions(DialMulticastDNSOptions{Disable: true}),
+		}, opts...)...)
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		})
+		return conn
+	}
+	echo := func(conn ClientConn) error {
+		_, err := pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		return err
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		test.That(t, echo(dial(WithClientCertificate(clientCert))), test.ShouldBeNil)
+
+		err := echo(dial())
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+
+		otherCA := newTestCA(t)
+		err = echo(dial(WithClientCertificate(otherCA.issue(t, &x509.Certificate{
+			URIs:        []*url.URL{spiffeID},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}))))
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid client certificate")
+
+		// certificates must be meant for clients.
+		err = echo(dial(WithClientCertificate(ca.issue(t, &x509.Certificate{
+			URIs:        []*url.URL{spiffeID},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}))))
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+	})
+
+	t.Run("authenticate", func(t *testing.T) {
+		authClient := rpcpb.NewAuthServiceClient(dial(WithClientCertificate(clientCert)))
+		_, err := authClient.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+			Entity:      "someone else",
+			Credentials: &rpcpb.Credentials{Type: string(CredentialsTypeMTLS)},
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+
+		_, err = rpcpb.NewAuthServiceClient(dial()).Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+			Entity:      spiffeID.String(),
+			Credentials: &rpcpb.Credentials{Type: string(CredentialsTypeMTLS)},
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+
+		// clients can exchange their certificate for a JWT.
+		conn := dial(
+			WithClientCertificate(clientCert),
+			WithEntityCredentials(spiffeID.String(), Credentials{Type: CredentialsTypeMTLS}),
+		)
+		test.That(t, echo(conn), test.ShouldBeNil)
+		_, err = authClient.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+			Entity:      spiffeID.String(),
+			Credentials: &rpcpb.Credentials{Type: string(CredentialsTypeMTLS)},
+		})
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("webrtc", func(t *testing.T) {
+		rtcConn, err := dialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, dialOptions{
+			tlsConfig:     tlsConfig,
+			clientCert:    &clientCert,
+			webrtcOptsSet: true,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		test.That(t, echo(rtcConn), test.ShouldBeNil)
+	})
+}
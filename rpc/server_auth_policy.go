@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// An AuthorizationRule allows or denies authenticated entities calls to methods. Every condition
+// that is set must hold for the rule to match.
+type AuthorizationRule struct {
+	// Methods are patterns, as in path.Match, of the full method names the rule applies to (e.g.
+	// "/proto.rpc.examples.echo.v1.EchoService/Echo" or "/proto.rpc.examples.echo.v1.EchoService/*").
+	// No methods means every method.
+	Methods []string
+
+	// CredentialsTypes are the types of credentials the entity must have authenticated with.
+	CredentialsTypes []CredentialsType
+
+	// Entities are the entities the rule applies to.
+	Entities []string
+
+	// Metadata maps keys of the entity's auth metadata (see JWTClaims.AuthMetadata) to the values
+	// of which the entity must have at least one. Metadata values are treated as lists separated by
+	// spaces or commas so that, for example, a "roles" or "scope" key can hold many values.
+	Metadata map[string][]string
+
+	// Deny makes calls matching the rule be denied instead of allowed.
+	Deny bool
+}
+
+// An AuthorizationPolicy decides which methods authenticated entities may call. Its rules are
+// checked in order and the first one that matches a call decides it; calls matching no rule are
+// denied. Exempt and public methods are not subject to the policy.
+type AuthorizationPolicy struct {
+	Rules []AuthorizationRule
+}
+
+func (policy AuthorizationPolicy) validate() error {
+	for ruleIdx, rule := range policy.Rules {
+		for _, pattern := range rule.Methods {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.Wrapf(err, "invalid method pattern %q in rule %d", pattern, ruleIdx)
+			}
+		}
+	}
+	return nil
+}
+
+// authorizationSubject is what an authorization decision is made about.
+type authorizationSubject struct {
+	method          string
+	entity          string
+	credentialsType CredentialsType
+	metadata        map[string]string
+}
+
+// decide returns whether the subject is allowed and the index of the rule that decided it or
+// -1 if no rule matched.
+func (policy AuthorizationPolicy) decide(subject authorizationSubject) (bool, int) {
+	for ruleIdx, rule := range policy.Rules {
+		if rule.matches(subject) {
+			return !rule.Deny, ruleIdx
+		}
+	}
+	return false, -1
+}
+
+func (rule AuthorizationRule) matches(subject authorizationSubject) bool {
+	if len(rule.Methods) != 0 && !slices.ContainsFunc(rule.Methods, func(pattern string) bool {
+		matched, err := path.Match(pattern, subject.method)
+		return err == nil && matched
+	}) {
+		return false
+	}
+	if len(rule.CredentialsTypes) != 0 && !slices.Contains(rule.CredentialsTypes, subject.credentialsType) {
+		return false
+	}
+	if len(rule.Entities) != 0 && !slices.Contains(rule.Entities, subject.entity) {
+		return false
+	}
+	for key, wantValues := range rule.Metadata {
+		values := strings.FieldsFunc(subject.metadata[key], func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+		if !slices.ContainsFunc(wantValues, func(want string) bool {
+			return slices.Contains(values, want)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// authorize checks that the entity authenticated in ctx may call the given method.
+func (ss *simpleServer) authorize(ctx context.Context, method string) error {
+	if ss.authorizationPolicy == nil {
+		return nil
+	}
+
+	subject := authorizationSubject{method: method}
+	if entity, ok := ContextAuthEntity(ctx); ok {
+		subject.entity = entity.Entity
+	}
+	if claims, ok := contextAuthClaims(ctx); ok {
+		subject.credentialsType = claims.CredentialsType()
+		subject.metadata = claims.Metadata()
+	}
+	// the server's own connections, like its internal signaling answerer, are always allowed.
+	if subject.credentialsType == credentialsTypeInternal {
+		return nil
+	}
+
+	allowed, ruleIdx := ss.authorizationPolicy.decide(subject)
+	if !allowed {
+		ss.logger.Warnw("authorization denied",
+			"method", method,
+			"entity", subject.entity,
+			"credentials_type", subject.credentialsType,
+			"rule", ruleIdx)
+		return status.Errorf(codes.PermissionDenied, "not authorized to call %s", method)
+	}
+	ss.logger.Debugw("authorization allowed",
+		"method", method,
+		"entity", subject.entity,
+		"credentials_type", subject.credentialsType,
+		"rule", ruleIdx)
+	return nil
+}
+
+// authorizeUnaryInterceptor enforces the authorization policy on connections where
+// authentication is handled outside of the auth interceptors, like WebRTC.
+func (ss *simpleServer) authorizeUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if !ss.exemptMethods[info.FullMethod] && !ss.isPublicMethod(info.FullMethod) {
+		if err := ss.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+	}
+	return handler(ctx, req)
+}
+
+// authorizeStreamInterceptor is the streaming version of authorizeUnaryInterceptor.
+func (ss *simpleServer) authorizeStreamInterceptor(
+	srv interface{},
+	serverStream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if !ss.exemptMethods[info.FullMethod] && !ss.isPublicMethod(info.FullMethod) {
+		if err := ss.authorize(serverStream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+	}
+	return handler(srv, serverStream)
+}
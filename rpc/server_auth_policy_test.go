@@ -0,0 +1,206 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+const (
+	testEchoMethod         = "/proto.rpc.examples.echo.v1.EchoService/Echo"
+	testEchoMultipleMethod = "/proto.rpc.examples.echo.v1.EchoService/EchoMultiple"
+)
+
+func TestAuthorizationPolicy(t *testing.T) {
+	policy := AuthorizationPolicy{Rules: []AuthorizationRule{
+		{Entities: []string{"mallory"}, Deny: true},
+		{Methods: []string{testEchoMethod}, Metadata: map[string][]string{"roles": {"echoer", "admin"}}},
+		{Methods: []string{"/proto.rpc.examples.echo.v1.EchoService/*"}, CredentialsTypes: []CredentialsType{CredentialsTypeMTLS}},
+		{Methods: []string{"/proto.rpc.examples.echo.v1.EchoService/*"}, Metadata: map[string][]string{"roles": {"admin"}}},
+	}}
+	test.That(t, policy.validate(), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name    string
+		subject authorizationSubject
+		allowed bool
+		rule    int
+	}{
+		{
+			"denied entity",
+			authorizationSubject{method: testEchoMethod, entity: "mallory", metadata: map[string]string{"roles": "admin"}},
+			false, 0,
+		},
+		{
+			"one of many roles",
+			authorizationSubject{method: testEchoMethod, entity: "alice", metadata: map[string]string{"roles": "reader, echoer"}},
+			true, 1,
+		},
+		{
+			"role for another method",
+			authorizationSubject{method: testEchoMultipleMethod, entity: "alice", metadata: map[string]string{"roles": "echoer"}},
+			false, -1,
+		},
+		{
+			"credentials type",
+			authorizationSubject{method: testEchoMultipleMethod, entity: "spiffe://example.org/a", credentialsType: CredentialsTypeMTLS},
+			true, 2,
+		},
+		{
+			"method pattern",
+			authorizationSubject{method: testEchoMultipleMethod, entity: "bob", metadata: map[string]string{"roles": "admin"}},
+			true, 3,
+		},
+		{
+			"pattern does not cross services",
+			authorizationSubject{method: "/proto.rpc.v1.AuthService/Authenticate", entity: "bob", metadata: map[string]string{"roles": "admin"}},
+			false, -1,
+		},
+		{
+			"role is not a substring",
+			authorizationSubject{method: testEchoMethod, entity: "carol", metadata: map[string]string{"roles": "administrator"}},
+			false, -1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, rule := policy.decide(tc.subject)
+			test.That(t, allowed, test.ShouldEqual, tc.allowed)
+			test.That(t, rule, test.ShouldEqual, tc.rule)
+		})
+	}
+
+	badPolicy := AuthorizationPolicy{Rules: []AuthorizationRule{{Methods: []string{"/a/["}}}}
+	test.That(t, badPolicy.validate(), test.ShouldNotBeNil)
+	_, err := NewServer(golog.NewTestLogger(t), WithAuthorizationPolicy(badPolicy))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid method pattern")
+
+	_, err = NewServer(golog.NewTestLogger(t), WithUnauthenticated(), WithAuthorizationPolicy(policy))
+	test.That(t, err, test.ShouldBeError, errMixedUnauthAndAuth)
+
+	_, err = NewServer(golog.NewTestLogger(t), WithAuthorizationPolicy(policy),
+		WithEnsureAuthedHandler(func(ctx context.Context) (context.Context, error) {
+			return ctx, nil
+		}))
+	test.That(t, err, test.ShouldBeError, errMixedPolicyAndEnsureAuthed)
+}
+
+func TestServerAuthorizationPolicy(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	roles := map[string]string{
+		"alice": "echoer",
+		"bob":   "echoer admin",
+	}
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithAuthHandler("fake", AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+			return map[string]string{"roles": roles[entity]}, nil
+		})),
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+		}),
+		WithAuthorizationPolicy(AuthorizationPolicy{Rules: []AuthorizationRule{
+			{Methods: []string{testEchoMethod}, Metadata: map[string][]string{"roles": {"echoer"}}},
+			{Methods: []string{"/proto.rpc.examples.echo.v1.EchoService/*"}, Metadata: map[string][]string{"roles": {"admin"}}},
+			{Methods: []string{"/proto.rpc.webrtc.v1.SignalingService/*"}, CredentialsTypes: []CredentialsType{"fake"}},
+			// WebRTC peers are only known by the host they connected to.
+			{Methods: []string{testEchoMethod}, Entities: []string{internalSignalingHost}},
+		}}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	testCalls := func(t *testing.T, client pb.EchoServiceClient, ctx context.Context, echoMultipleAllowed bool) {
+		t.Helper()
+		_, err := client.Echo(ctx, &pb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+
+		stream, err := client.EchoMultiple(ctx, &pb.EchoMultipleRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = stream.Recv()
+		if echoMultipleAllowed {
+			test.That(t, err, test.ShouldBeNil)
+			return
+		}
+		test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+		test.That(t, err.Error(), test.ShouldContainSubstring, testEchoMultipleMethod)
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		authClient := rpcpb.NewAuthServiceClient(conn)
+		echoClient := pb.NewEchoServiceClient(conn)
+
+		authedCtx := func(entity string) context.Context {
+			resp, err := authClient.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+				Entity:      entity,
+				Credentials: &rpcpb.Credentials{Type: "fake"},
+			})
+			test.That(t, err, test.ShouldBeNil)
+			return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+resp.GetAccessToken())
+		}
+
+		testCalls(t, echoClient, authedCtx("alice"), false)
+		testCalls(t, echoClient, authedCtx("bob"), true)
+
+		_, err = echoClient.Echo(authedCtx("carol"), &pb.EchoRequest{Message: "hello"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+
+		// authentication still comes first.
+		_, err = echoClient.Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+	})
+
+	t.Run("webrtc", func(t *testing.T) {
+		rtcConn, err := dialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, dialOptions{
+			webrtcOpts: DialWebRTCOptions{
+				SignalingInsecure:   true,
+				SignalingAuthEntity: "carol",
+				SignalingCreds:      Credentials{Type: "fake"},
+			},
+			webrtcOptsSet: true,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		testCalls(t, pb.NewEchoServiceClient(rtcConn), context.Background(), false)
+	})
+}
@@ -0,0 +1,260 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongoutils "go.viam.com/utils/mongo"
+)
+
+func init() {
+	mongoutils.MustRegisterNamespace(&mongodbAuthDBName, &mongodbRevokedTokensCollName)
+	mongoutils.MustRegisterNamespace(&mongodbAuthDBName, &mongodbRevokedEntitiesCollName)
+}
+
+// ErrTokenRevoked is returned by TokenRevocationStore.RevokeToken when the token was already revoked.
+var ErrTokenRevoked = errors.New("token already revoked")
+
+// A TokenRevocationStore keeps track of tokens that must no longer be accepted even though they
+// have not expired yet. A store shared between servers, like a MongoDBTokenRevocationStore, cuts
+// a token off on all of them at once.
+type TokenRevocationStore interface {
+	// RevokeToken revokes the token with the given ID (jti) until it expires. A zero expiresAt
+	// revokes the token forever. It returns ErrTokenRevoked if the token was already revoked which
+	// makes it suitable for ensuring a token is only ever used once.
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+
+	// RevokeEntity revokes every token issued to the entity at or before issuedBefore.
+	RevokeEntity(ctx context.Context, entity string, issuedBefore time.Time) error
+
+	// IsRevoked returns whether the token with the given ID, issued to entity at issuedAt, has
+	// been revoked.
+	IsRevoked(ctx context.Context, tokenID, entity string, issuedAt time.Time) (bool, error)
+}
+
+var (
+	_ TokenRevocationStore = (*MemoryTokenRevocationStore)(nil)
+	_ TokenRevocationStore = (*MongoDBTokenRevocationStore)(nil)
+)
+
+// A MemoryTokenRevocationStore is a TokenRevocationStore kept in memory. Revocations are only
+// seen by the server using it and are lost when it restarts.
+type MemoryTokenRevocationStore struct {
+	mu        sync.Mutex
+	tokens    map[string]time.Time
+	entities  map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryTokenRevocationStore returns a new, empty in-memory TokenRevocationStore.
+func NewMemoryTokenRevocationStore() *MemoryTokenRevocationStore {
+	return &MemoryTokenRevocationStore{
+		tokens:   map[string]time.Time{},
+		entities: map[string]time.Time{},
+	}
+}
+
+// memoryTokenRevocationSweepInterval is how often expired token revocations are forgotten.
+const memoryTokenRevocationSweepInterval = time.Minute
+
+// RevokeToken revokes the token with the given ID until it expires.
+func (s *MemoryTokenRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= memoryTokenRevocationSweepInterval {
+		s.lastSweep = now
+		for id, until := range s.tokens {
+			if !until.IsZero() && until.Before(now) {
+				delete(s.tokens, id)
+			}
+		}
+	}
+
+	if s.tokenRevoked(tokenID, now) {
+		return ErrTokenRevoked
+	}
+	s.tokens[tokenID] = expiresAt
+	return nil
+}
+
+// RevokeEntity revokes every token issued to the entity at or before issuedBefore.
+func (s *MemoryTokenRevocationStore) RevokeEntity(ctx context.Context, entity string, issuedBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if issuedBefore.After(s.entities[entity]) {
+		s.entities[entity] = issuedBefore
+	}
+	return nil
+}
+
+// IsRevoked returns whether the token has been revoked by ID or by entity.
+func (s *MemoryTokenRevocationStore) IsRevoked(ctx context.Context, tokenID, entity string, issuedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tokenID != "" && s.tokenRevoked(tokenID, time.Now()) {
+		return true, nil
+	}
+	revokedBefore, ok := s.entities[entity]
+	return ok && !issuedAt.After(revokedBefore), nil
+}
+
+func (s *MemoryTokenRevocationStore) tokenRevoked(tokenID string, now time.Time) bool {
+	until, ok := s.tokens[tokenID]
+	return ok && (until.IsZero() || until.After(now))
+}
+
+// defaultTokenRevocationCacheTTL is how long tokens found not to be revoked are remembered for
+// by default.
+const defaultTokenRevocationCacheTTL = 2 * time.Second
+
+// A tokenRevocationCache remembers, for a short while, the IDs of tokens a TokenRevocationStore
+// found not to be revoked so that a server does not consult the store on every request.
+type tokenRevocationCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	checked   map[string]time.Time
+	lastSweep time.Time
+}
+
+func newTokenRevocationCache(ttl time.Duration) *tokenRevocationCache {
+	return &tokenRevocationCache{ttl: ttl, checked: map[string]time.Time{}}
+}
+
+// notRevoked returns whether the token with the given ID was recently found not to be revoked.
+func (c *tokenRevocationCache) notRevoked(tokenID string) bool {
+	if tokenID == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	checkedAt, ok := c.checked[tokenID]
+	return ok && time.Since(checkedAt) < c.ttl
+}
+
+// rememberNotRevoked records that the token with the given ID was just found not to be revoked.
+func (c *tokenRevocationCache) rememberNotRevoked(tokenID string) {
+	if tokenID == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		c.lastSweep = now
+		for id, checkedAt := range c.checked {
+			if now.Sub(checkedAt) >= c.ttl {
+				delete(c.checked, id)
+			}
+		}
+	}
+	c.checked[tokenID] = now
+}
+
+// forget makes the next check of the token with the given ID consult the store.
+func (c *tokenRevocationCache) forget(tokenID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.checked, tokenID)
+}
+
+// Database configuration and collection names for the MongoDB token revocation store.
+var (
+	mongodbAuthDBName              = "rpc_auth"
+	mongodbRevokedTokensCollName   = "revoked_tokens"
+	mongodbRevokedEntitiesCollName = "revoked_entities"
+	mongodbRevokedTokensTTLName    = "revoked_token_expire"
+)
+
+// A MongoDBTokenRevocationStore is a MongoDB implementation of a TokenRevocationStore designed to
+// be shared by multi-node, distributed deployments.
+type MongoDBTokenRevocationStore struct {
+	tokensColl   *mongo.Collection
+	entitiesColl *mongo.Collection
+}
+
+// NewMongoDBTokenRevocationStore returns a new MongoDB based TokenRevocationStore. Token
+// revocations are removed by MongoDB once the tokens they are for expire.
+func NewMongoDBTokenRevocationStore(ctx context.Context, client *mongo.Client) (*MongoDBTokenRevocationStore, error) {
+	db := client.Database(mongodbAuthDBName)
+	tokensColl := db.Collection(mongodbRevokedTokensCollName)
+
+	ttlSeconds := int32(0)
+	if err := mongoutils.EnsureIndexes(ctx, tokensColl, mongo.IndexModel{
+		Keys: bson.D{{Key: "expires_at", Value: 1}},
+		Options: &options.IndexOptions{
+			Name:               &mongodbRevokedTokensTTLName,
+			ExpireAfterSeconds: &ttlSeconds,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return &MongoDBTokenRevocationStore{
+		tokensColl:   tokensColl,
+		entitiesColl: db.Collection(mongodbRevokedEntitiesCollName),
+	}, nil
+}
+
+// RevokeToken revokes the token with the given ID until it expires.
+func (s *MongoDBTokenRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	doc := bson.M{"_id": tokenID}
+	if !expiresAt.IsZero() {
+		doc["expires_at"] = expiresAt
+	}
+	if _, err := s.tokensColl.InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTokenRevoked
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeEntity revokes every token issued to the entity at or before issuedBefore.
+func (s *MongoDBTokenRevocationStore) RevokeEntity(ctx context.Context, entity string, issuedBefore time.Time) error {
+	_, err := s.entitiesColl.UpdateOne(ctx,
+		bson.M{"_id": entity},
+		bson.M{"$max": bson.M{"revoked_before": issuedBefore}},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+// IsRevoked returns whether the token has been revoked by ID or by entity.
+func (s *MongoDBTokenRevocationStore) IsRevoked(ctx context.Context, tokenID, entity string, issuedAt time.Time) (bool, error) {
+	if tokenID != "" {
+		// MongoDB removes expired documents periodically so they may still be around.
+		revoked, err := s.exists(ctx, s.tokensColl, bson.M{
+			"_id": tokenID,
+			"$or": bson.A{
+				bson.M{"expires_at": bson.M{"$exists": false}},
+				bson.M{"expires_at": bson.M{"$gt": time.Now()}},
+			},
+		})
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	return s.exists(ctx, s.entitiesColl, bson.M{
+		"_id":            entity,
+		"revoked_before": bson.M{"$gte": issuedAt},
+	})
+}
+
+func (s *MongoDBTokenRevocationStore) exists(ctx context.Context, coll *mongo.Collection, filter bson.M) (bool, error) {
+	if err := coll.FindOne(ctx, filter).Err(); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
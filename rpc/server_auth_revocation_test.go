@@ -0,0 +1,312 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang-jwt/jwt/v4"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func testTokenRevocationStore(t *testing.T, store TokenRevocationStore) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	revoked, err := store.IsRevoked(ctx, "token1", "entity1", now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, revoked, test.ShouldBeFalse)
+
+	// tokens are revoked by ID, once.
+	test.That(t, store.RevokeToken(ctx, "token1", now.Add(time.Hour)), test.ShouldBeNil)
+	test.That(t, store.RevokeToken(ctx, "token1", now.Add(time.Hour)), test.ShouldBeError, ErrTokenRevoked)
+	revoked, err = store.IsRevoked(ctx, "token1", "entity1", now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, revoked, test.ShouldBeTrue)
+	revoked, err = store.IsRevoked(ctx, "token2", "entity1", now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, revoked, test.ShouldBeFalse)
+
+	// revocations of expired tokens no longer matter.
+	test.That(t, store.RevokeToken(ctx, "token3", now.Add(-time.Second)), test.ShouldBeNil)
+	revoked, err = store.IsRevoked(ctx, "token3", "entity1", now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, revoked, test.ShouldBeFalse)
+
+	// entities are revoked up to a point in time.
+	test.That(t, store.RevokeEntity(ctx, "entity1", now), test.ShouldBeNil)
+	for _, tc := range []struct {
+		entity   string
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{"entity1", now.Add(-time.Hour), true},
+		{"entity1", now, true},
+		{"entity1", now.Add(time.Second), false},
+		{"entity2", now.Add(-time.Hour), false},
+	} {
+		revoked, err := store.IsRevoked(ctx, "", tc.entity, tc.issuedAt)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, revoked, test.ShouldEqual, tc.revoked)
+	}
+
+	// an earlier revocation does not undo a later one.
+	test.That(t, store.RevokeEntity(ctx, "entity1", now.Add(-time.Hour)), test.ShouldBeNil)
+	revoked, err = store.IsRevoked(ctx, "", "entity1", now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, revoked, test.ShouldBeTrue)
+}
+
+func TestMemoryTokenRevocationStore(t *testing.T) {
+	testTokenRevocationStore(t, NewMemoryTokenRevocationStore())
+}
+
+func TestMongoDBTokenRevocationStore(t *testing.T) {
+	client := testutils.BackingMongoDBClient(t)
+	test.That(t, client.Database(mongodbAuthDBName).Drop(context.Background()), test.ShouldBeNil)
+	store, err := NewMongoDBTokenRevocationStore(context.Background(), client)
+	test.That(t, err, test.ShouldBeNil)
+	testTokenRevocationStore(t, store)
+}
+
+func TestServerAuthRefreshToken(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	_, privKey, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	keyOpt, _ := WithAuthED25519PrivateKey(privKey)
+	store := NewMemoryTokenRevocationStore()
+
+	// both servers share a key and a revocation store.
+	newServer := func() (pb.EchoServiceClient, rpcpb.AuthServiceClient) {
+		rpcServer, err := NewServer(
+			logger,
+			WithAuthHandler("fake", AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+				return map[string]string{"some": "metadata"}, nil
+			})),
+			WithEntityDataLoader("fake", EntityDataLoaderFunc(func(ctx context.Context, claims Claims) (interface{}, error) {
+				if claims.Metadata()["some"] != "metadata" {
+					return nil, fmt.Errorf("bad metadata %v", claims.Metadata())
+				}
+				return nil, nil
+			})),
+			WithAuthAudience("audience"),
+			keyOpt,
+			WithRefreshTokens(time.Minute, time.Hour),
+			WithTokenRevocationStore(store),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rpcServer.RegisterServiceServer(
+			context.Background(),
+			&pb.EchoService_ServiceDesc,
+			&echoserver.Server{},
+			pb.RegisterEchoServiceHandlerFromEndpoint,
+		), test.ShouldBeNil)
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		errChan := make(chan error)
+		go func() {
+			errChan <- rpcServer.Serve(listener)
+		}()
+		conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+			test.That(t, <-errChan, test.ShouldBeNil)
+		})
+		return pb.NewEchoServiceClient(conn), rpcpb.NewAuthServiceClient(conn)
+	}
+	echoClient1, authClient1 := newServer()
+	echoClient2, authClient2 := newServer()
+
+	echo := func(client pb.EchoServiceClient, token string) error {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		_, err := client.Echo(ctx, &pb.EchoRequest{Message: "hello"})
+		return err
+	}
+	claimsOf := func(token string) JWTClaims {
+		var claims JWTClaims
+		_, _, err := jwt.NewParser().ParseUnverified(token, &claims)
+		test.That(t, err, test.ShouldBeNil)
+		return claims
+	}
+
+	authResp, err := authClient1.Authenticate(context.Background(), &rpcpb.AuthenticateRequest{
+		Entity:      "entity",
+		Credentials: &rpcpb.Credentials{Type: "fake"},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, authResp.GetRefreshToken(), test.ShouldNotBeEmpty)
+	accessClaims := claimsOf(authResp.GetAccessToken())
+	test.That(t, accessClaims.ExpiresAt.Sub(accessClaims.IssuedAt.Time), test.ShouldEqual, time.Minute)
+	test.That(t, echo(echoClient1, authResp.GetAccessToken()), test.ShouldBeNil)
+	test.That(t, echo(echoClient2, authResp.GetAccessToken()), test.ShouldBeNil)
+
+	t.Run("refresh tokens are not access tokens", func(t *testing.T) {
+		err := echo(echoClient1, authResp.GetRefreshToken())
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "refresh tokens cannot be used")
+
+		_, err = authClient1.RefreshToken(context.Background(), &rpcpb.RefreshTokenRequest{
+			RefreshToken: authResp.GetAccessToken(),
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not a refresh token")
+	})
+
+	var refreshResp *rpcpb.RefreshTokenResponse
+	t.Run("refresh tokens rotate", func(t *testing.T) {
+		refreshResp, err = authClient1.RefreshToken(context.Background(), &rpcpb.RefreshTokenRequest{
+			RefreshToken: authResp.GetRefreshToken(),
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, refreshResp.GetRefreshToken(), test.ShouldNotEqual, authResp.GetRefreshToken())
+		test.That(t, claimsOf(refreshResp.GetAccessToken()).Entity(), test.ShouldEqual, "entity")
+		test.That(t, echo(echoClient2, refreshResp.GetAccessToken()), test.ShouldBeNil)
+
+		// the exchanged refresh token cannot be used again on any server.
+		_, err = authClient2.RefreshToken(context.Background(), &rpcpb.RefreshTokenRequest{
+			RefreshToken: authResp.GetRefreshToken(),
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "token has been revoked")
+	})
+
+	t.Run("revoked tokens are rejected everywhere", func(t *testing.T) {
+		claims := claimsOf(authResp.GetAccessToken())
+		test.That(t, store.RevokeToken(context.Background(), claims.ID, claims.ExpiresAt.Time), test.ShouldBeNil)
+		for _, client := range []pb.EchoServiceClient{echoClient1, echoClient2} {
+			err := echo(client, authResp.GetAccessToken())
+			test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+			test.That(t, err.Error(), test.ShouldContainSubstring, "token has been revoked")
+		}
+		test.That(t, echo(echoClient1, refreshResp.GetAccessToken()), test.ShouldBeNil)
+
+		test.That(t, store.RevokeEntity(context.Background(), "entity", time.Now()), test.ShouldBeNil)
+		err := echo(echoClient1, refreshResp.GetAccessToken())
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		_, err = authClient2.RefreshToken(context.Background(), &rpcpb.RefreshTokenRequest{
+			RefreshToken: refreshResp.GetRefreshToken(),
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+	})
+}
+
+// countingTokenRevocationStore counts how often it is asked whether tokens are revoked.
+type countingTokenRevocationStore struct {
+	*MemoryTokenRevocationStore
+	isRevokedCalls atomic.Int64
+}
+
+func (s *countingTokenRevocationStore) IsRevoked(ctx context.Context, tokenID, entity string, issuedAt time.Time) (bool, error) {
+	s.isRevokedCalls.Add(1)
+	return s.MemoryTokenRevocationStore.IsRevoked(ctx, tokenID, entity, issuedAt)
+}
+
+func TestServerTokenRevocationCache(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	_, err := NewServer(logger, WithTokenRevocationCacheTTL(-time.Second))
+	test.That(t, err, test.ShouldBeError, "token revocation cache TTL must not be negative")
+
+	newServer := func(t *testing.T, opts ...ServerOption) (*simpleServer, *countingTokenRevocationStore) {
+		t.Helper()
+		store := &countingTokenRevocationStore{MemoryTokenRevocationStore: NewMemoryTokenRevocationStore()}
+		rpcServer, err := NewServer(logger, append([]ServerOption{WithTokenRevocationStore(store)}, opts...)...)
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		})
+		ss, ok := rpcServer.(*simpleServer)
+		test.That(t, ok, test.ShouldBeTrue)
+		return ss, store
+	}
+	claims := JWTClaims{RegisteredClaims: jwt.RegisteredClaims{
+		ID:       "token",
+		Subject:  "entity",
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	}}
+
+	t.Run("tokens found not to be revoked are remembered", func(t *testing.T) {
+		ss, store := newServer(t, WithTokenRevocationCacheTTL(time.Hour))
+		for i := 0; i < 3; i++ {
+			test.That(t, ss.checkTokenRevoked(context.Background(), claims), test.ShouldBeNil)
+		}
+		test.That(t, store.isRevokedCalls.Load(), test.ShouldEqual, 1)
+
+		// revoked tokens are not remembered.
+		revokedClaims := claims
+		revokedClaims.ID = "revoked"
+		test.That(t, store.RevokeToken(context.Background(), revokedClaims.ID, time.Time{}), test.ShouldBeNil)
+		for i := 0; i < 2; i++ {
+			err := ss.checkTokenRevoked(context.Background(), revokedClaims)
+			test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		}
+		test.That(t, store.isRevokedCalls.Load(), test.ShouldEqual, 3)
+	})
+
+	t.Run("remembering expires", func(t *testing.T) {
+		ss, store := newServer(t, WithTokenRevocationCacheTTL(50*time.Millisecond))
+		test.That(t, ss.checkTokenRevoked(context.Background(), claims), test.ShouldBeNil)
+		test.That(t, store.RevokeToken(context.Background(), claims.ID, time.Time{}), test.ShouldBeNil)
+		test.That(t, ss.checkTokenRevoked(context.Background(), claims), test.ShouldBeNil)
+		time.Sleep(100 * time.Millisecond)
+		err := ss.checkTokenRevoked(context.Background(), claims)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ss, store := newServer(t, WithTokenRevocationCacheTTL(0))
+		test.That(t, ss.tokenRevocationCache, test.ShouldBeNil)
+		for i := 0; i < 3; i++ {
+			test.That(t, ss.checkTokenRevoked(context.Background(), claims), test.ShouldBeNil)
+		}
+		test.That(t, store.isRevokedCalls.Load(), test.ShouldEqual, 3)
+	})
+
+	t.Run("memory stores are always consulted", func(t *testing.T) {
+		rpcServer, err := NewServer(logger, WithTokenRevocationStore(NewMemoryTokenRevocationStore()))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		}()
+		ss, ok := rpcServer.(*simpleServer)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, ss.tokenRevocationCache, test.ShouldBeNil)
+	})
+}
+
+func TestServerAuthRefreshTokenDisabled(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	_, err := NewServer(logger, WithRefreshTokens(0, time.Hour))
+	test.That(t, err, test.ShouldBeError, "token lifetimes must be positive")
+
+	rpcServer, err := NewServer(logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+	}()
+	ss, ok := rpcServer.(*simpleServer)
+	test.That(t, ok, test.ShouldBeTrue)
+	_, err = ss.RefreshToken(context.Background(), &rpcpb.RefreshTokenRequest{})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+}
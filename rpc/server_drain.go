@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+)
+
+// errServerDraining is returned for calls made once the server has started stopping gracefully.
+var errServerDraining = status.Error(codes.Unavailable, "server is shutting down")
+
+// DrainStats report the progress of stopping a server gracefully.
+type DrainStats struct {
+	// Draining is set once the server has started stopping gracefully and refuses new calls.
+	Draining bool
+	// ActiveRPCs are the calls being handled, over every transport.
+	ActiveRPCs int64
+	// RejectedRPCs are the calls refused while draining.
+	RejectedRPCs int64
+	// ForceClosedRPCs are the calls that were still being handled when the server gave up
+	// waiting for them.
+	ForceClosedRPCs int64
+}
+
+// rpcTracker counts the calls being handled so that a graceful stop can wait for them.
+type rpcTracker struct {
+	mu       sync.Mutex
+	draining bool
+	active   int64
+	// idle is closed once draining and no calls are left.
+	idle chan struct{}
+
+	rejected    atomic.Int64
+	forceClosed atomic.Int64
+}
+
+// begin counts a new call unless the server is draining, in which case it returns false.
+func (t *rpcTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		t.rejected.Add(1)
+		return false
+	}
+	t.active++
+	return true
+}
+
+func (t *rpcTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.draining && t.active == 0 {
+		close(t.idle)
+	}
+}
+
+// drain refuses new calls and returns a channel closed once the ones being handled are done.
+func (t *rpcTracker) drain() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.draining {
+		t.draining = true
+		t.idle = make(chan struct{})
+		if t.active == 0 {
+			close(t.idle)
+		}
+	}
+	return t.idle
+}
+
+func (t *rpcTracker) stats() DrainStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return DrainStats{
+		Draining:        t.draining,
+		ActiveRPCs:      t.active,
+		RejectedRPCs:    t.rejected.Load(),
+		ForceClosedRPCs: t.forceClosed.Load(),
+	}
+}
+
+// tracksRPC reports whether a graceful stop waits for calls to the method. Health checks are
+// left alone so that they keep reporting the server as not serving, and watches of its health
+// do not hold it up. Signaling is left alone too since answerers keep their Answer streams open
+// for as long as they are connected, and Call streams only last until a connection is made.
+func tracksRPC(fullMethod string) bool {
+	switch fullMethod {
+	case healthCheckMethod, healthWatchMethod,
+		webrtcpb.SignalingService_Answer_FullMethodName, webrtcpb.SignalingService_Call_FullMethodName:
+		return false
+	default:
+		return true
+	}
+}
+
+func (ss *simpleServer) drainUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if !tracksRPC(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	if !ss.rpcs.begin() {
+		return nil, errServerDraining
+	}
+	defer ss.rpcs.end()
+	return handler(ctx, req)
+}
+
+func (ss *simpleServer) drainStreamInterceptor(
+	srv interface{},
+	serverStream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if !tracksRPC(info.FullMethod) {
+		return handler(srv, serverStream)
+	}
+	if !ss.rpcs.begin() {
+		return errServerDraining
+	}
+	defer ss.rpcs.end()
+	return handler(srv, serverStream)
+}
+
+// A GracefulStopper is a server that can stop without cutting off the calls it is handling.
+// Servers returned by NewServer implement it; callers type-assert a Server to it.
+type GracefulStopper interface {
+	// StopGracefully stops accepting new calls and WebRTC connections, tells connected WebRTC
+	// clients to go away, and waits for the calls being handled to finish before stopping. Calls
+	// still running once ctx is done are closed. Progress is reported in Stats.
+	StopGracefully(ctx context.Context) error
+}
+
+var _ GracefulStopper = (*simpleServer)(nil)
+
+func (ss *simpleServer) StopGracefully(ctx context.Context) error {
+	ss.mu.Lock()
+	if ss.stopped {
+		ss.mu.Unlock()
+		return nil
+	}
+	idle := ss.rpcs.drain()
+	ss.logger.Infow("stopping gracefully", "active_rpcs", ss.rpcs.stats().ActiveRPCs)
+	if ss.healthServer != nil {
+		ss.healthServer.Shutdown()
+	}
+	for _, answerer := range ss.webrtcAnswerers {
+		answerer.Stop()
+	}
+	if ss.webrtcServer != nil {
+		ss.webrtcServer.goAway()
+	}
+	ss.mu.Unlock()
+
+	select {
+	case <-idle:
+		ss.logger.Info("all calls finished")
+	case <-ctx.Done():
+		active := ss.rpcs.stats().ActiveRPCs
+		ss.rpcs.forceClosed.Store(active)
+		ss.logger.Warnw("gave up waiting for calls to finish; closing them", "active_rpcs", active)
+	}
+	return ss.Stop()
+}
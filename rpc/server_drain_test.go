@@ -0,0 +1,183 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestServerStopGracefully(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	internalSignalingHost := "yeehaw"
+	serve := func(t *testing.T) (Server, net.Addr, <-chan error) {
+		t.Helper()
+		rpcServer, err := NewServer(
+			logger,
+			WithUnauthenticated(),
+			WithWebRTCServerOptions(WebRTCServerOptions{
+				Enable:                 true,
+				InternalSignalingHosts: []string{internalSignalingHost},
+			}),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rpcServer.RegisterServiceServer(
+			context.Background(),
+			&pb.EchoService_ServiceDesc,
+			&echoserver.Server{},
+			pb.RegisterEchoServiceHandlerFromEndpoint,
+		), test.ShouldBeNil)
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- rpcServer.Serve(listener)
+		}()
+		return rpcServer, listener.Addr(), errChan
+	}
+
+	dial := func(t *testing.T, addr net.Addr) (*grpc.ClientConn, ClientConn) {
+		t.Helper()
+		conn, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		test.That(t, err, test.ShouldBeNil)
+		rtcConn, err := dialWebRTC(context.Background(), addr.String(), internalSignalingHost, dialOptions{
+			webrtcOpts:    DialWebRTCOptions{SignalingInsecure: true},
+			webrtcOptsSet: true,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		return conn, rtcConn
+	}
+
+	echoOnce := func(t *testing.T, stream pb.EchoService_EchoBiDiClient) {
+		t.Helper()
+		test.That(t, stream.Send(&pb.EchoBiDiRequest{Message: "a"}), test.ShouldBeNil)
+		resp, err := stream.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, "a")
+	}
+
+	drainStats := func(rpcServer Server) DrainStats {
+		//nolint:forcetypeassert
+		return rpcServer.Stats().(SimpleServerStats).DrainStats
+	}
+
+	stopGracefully := func(ctx context.Context, rpcServer Server) error {
+		//nolint:forcetypeassert
+		return rpcServer.(GracefulStopper).StopGracefully(ctx)
+	}
+
+	t.Run("drained", func(t *testing.T) {
+		rpcServer, addr, errChan := serve(t)
+		conn, rtcConn := dial(t, addr)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+
+		grpcStream, err := pb.NewEchoServiceClient(conn).EchoBiDi(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		echoOnce(t, grpcStream)
+		rtcStream, err := pb.NewEchoServiceClient(rtcConn).EchoBiDi(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		echoOnce(t, rtcStream)
+		test.That(t, drainStats(rpcServer).ActiveRPCs, test.ShouldEqual, 2)
+
+		// answerers waiting for calls do not hold up stopping.
+		answerCtx, answerCancel := context.WithCancel(
+			metadata.AppendToOutgoingContext(context.Background(), RPCHostMetadataField, internalSignalingHost))
+		defer answerCancel()
+		_, err = webrtcpb.NewSignalingServiceClient(conn).Answer(answerCtx)
+		test.That(t, err, test.ShouldBeNil)
+
+		stopErr := make(chan error, 1)
+		go func() {
+			stopErr <- stopGracefully(context.Background(), rpcServer)
+		}()
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			//nolint:forcetypeassert
+			stats := rpcServer.Stats().(SimpleServerStats)
+			test.That(tb, stats.DrainStats.Draining, test.ShouldBeTrue)
+			test.That(tb, stats.WebRTCGrpcStats.GoAwaysSent, test.ShouldEqual, 1)
+		})
+
+		// new calls are refused while the ones already started keep going.
+		_, err = pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			_, err := pb.NewEchoServiceClient(rtcConn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+			test.That(tb, errors.Is(err, ErrGoingAway), test.ShouldBeTrue)
+		})
+		echoOnce(t, grpcStream)
+		echoOnce(t, rtcStream)
+
+		select {
+		case err := <-stopErr:
+			t.Fatalf("stopped before calls finished: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		for _, stream := range []pb.EchoService_EchoBiDiClient{grpcStream, rtcStream} {
+			test.That(t, stream.CloseSend(), test.ShouldBeNil)
+			_, err = stream.Recv()
+			test.That(t, err, test.ShouldEqual, io.EOF)
+		}
+		test.That(t, <-stopErr, test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+
+		stats := drainStats(rpcServer)
+		test.That(t, stats.ActiveRPCs, test.ShouldEqual, 0)
+		test.That(t, stats.RejectedRPCs, test.ShouldEqual, 1)
+		test.That(t, stats.ForceClosedRPCs, test.ShouldEqual, 0)
+
+		// stopping again does nothing.
+		test.That(t, stopGracefully(context.Background(), rpcServer), test.ShouldBeNil)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		rpcServer, addr, errChan := serve(t)
+		conn, rtcConn := dial(t, addr)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+
+		grpcStream, err := pb.NewEchoServiceClient(conn).EchoBiDi(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		echoOnce(t, grpcStream)
+		rtcStream, err := pb.NewEchoServiceClient(rtcConn).EchoBiDi(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		echoOnce(t, rtcStream)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		test.That(t, stopGracefully(ctx, rpcServer), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+		test.That(t, drainStats(rpcServer).ForceClosedRPCs, test.ShouldBeGreaterThanOrEqualTo, 2)
+
+		// calls still running once the deadline passes are closed.
+		for _, stream := range []pb.EchoService_EchoBiDiClient{grpcStream, rtcStream} {
+			_, err = stream.Recv()
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+}
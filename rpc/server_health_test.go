@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestServerHealthService(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithAuthHandler("fake", AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+			return map[string]string{}, nil
+		})),
+		WithAllowUnauthenticatedHealthCheck(),
+		WithHealthService(),
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+		}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	healthSetter, ok := rpcServer.(HealthStatusSetter)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	stopped := false
+	defer func() {
+		if !stopped {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+			test.That(t, <-errChan, test.ShouldBeNil)
+		}
+	}()
+
+	checkStatus := func(t *testing.T, client healthpb.HealthClient, service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetStatus(), test.ShouldEqual, expected)
+	}
+	echoService := pb.EchoService_ServiceDesc.ServiceName
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	healthClient := healthpb.NewHealthClient(conn)
+
+	t.Run("grpc", func(t *testing.T) {
+		checkStatus(t, healthClient, "", healthpb.HealthCheckResponse_SERVING)
+		checkStatus(t, healthClient, echoService, healthpb.HealthCheckResponse_SERVING)
+		// registered before the health service.
+		checkStatus(t, healthClient, "proto.rpc.webrtc.v1.SignalingService", healthpb.HealthCheckResponse_SERVING)
+		_, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "not.a.Service"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+
+		healthSetter.SetServingStatus(echoService, healthpb.HealthCheckResponse_NOT_SERVING)
+		checkStatus(t, healthClient, echoService, healthpb.HealthCheckResponse_NOT_SERVING)
+		healthSetter.SetServingStatus(echoService, healthpb.HealthCheckResponse_SERVING)
+		checkStatus(t, healthClient, echoService, healthpb.HealthCheckResponse_SERVING)
+
+		// only the health check is unauthenticated.
+		_, err = pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+	})
+
+	t.Run("webrtc", func(t *testing.T) {
+		rtcConn, err := dialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, dialOptions{
+			webrtcOpts: DialWebRTCOptions{
+				SignalingInsecure:   true,
+				SignalingAuthEntity: "alice",
+				SignalingCreds:      Credentials{Type: "fake"},
+			},
+			webrtcOptsSet: true,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		checkStatus(t, healthpb.NewHealthClient(rtcConn), echoService, healthpb.HealthCheckResponse_SERVING)
+
+		reflectionClient, err := reflectionpb.NewServerReflectionClient(rtcConn).ServerReflectionInfo(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, reflectionClient.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		}), test.ShouldBeNil)
+		resp, err := reflectionClient.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		var services []string
+		for _, service := range resp.GetListServicesResponse().GetService() {
+			services = append(services, service.GetName())
+		}
+		test.That(t, services, test.ShouldContain, echoService)
+		test.That(t, services, test.ShouldContain, healthpb.Health_ServiceDesc.ServiceName)
+		test.That(t, reflectionClient.CloseSend(), test.ShouldBeNil)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		watchClient, err := healthClient.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := watchClient.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetStatus(), test.ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
+		stopped = true
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+		// watchers hear about the server going away before their stream ends.
+		resp, err = watchClient.Recv()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetStatus(), test.ShouldEqual, healthpb.HealthCheckResponse_NOT_SERVING)
+
+		// changes after stopping are ignored.
+		healthSetter.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	})
+}
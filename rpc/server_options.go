@@ -6,8 +6,10 @@ import (
 	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/pkg/errors"
@@ -37,6 +39,9 @@ type serverOptions struct {
 	// allowUnauthenticatedHealthCheck allows the server to have an unauthenticated healthcheck endpoint
 	allowUnauthenticatedHealthCheck bool
 
+	// healthService registers the gRPC health service.
+	healthService bool
+
 	// publicMethods are api routes that attempt, but do not require, authentication
 	publicMethods []string
 
@@ -44,13 +49,29 @@ type serverOptions struct {
 	authKeys       map[string]authKeyData
 	jwtSignerKeyID string
 
+	// authKeyRotationInterval is how often the key used to sign JWTs is replaced and
+	// authKeyRetireAfter is how long replaced keys keep verifying JWTs.
+	authKeyRotationInterval time.Duration
+	authKeyRetireAfter      time.Duration
+
+	// authKeySource replaces authKeys and is checked again every authKeySourceRefreshInterval.
+	authKeySource                AuthKeySource
+	authKeySourceRefreshInterval time.Duration
+
 	// debug is helpful to turn on when the library isn't working quite right.
 	// It will output much more logs.
 	debug bool
 
 	tlsAuthHandler       func(ctx context.Context, entities ...string) error
+	mtlsAuth             *mtlsAuthenticator
 	authHandlersForCreds map[CredentialsType]credAuthHandlers
 
+	// authorizationPolicy decides which methods authenticated entities may call.
+	authorizationPolicy *AuthorizationPolicy
+
+	// auditSink records authentication and access events.
+	auditSink AuditSink
+
 	// authAudience is the JWT audience (aud) that will be used/expected
 	// for our service. When unset, it will be debug logged that
 	// the instance names will be used instead.
@@ -72,6 +93,22 @@ type serverOptions struct {
 	ensureAuthedHandler func(ctx context.Context) (context.Context, error)
 
 	unknownStreamDesc *grpc.StreamDesc
+
+	// rateLimiter limits how often each caller may call each method.
+	rateLimiter RateLimiter
+
+	// tokenRevocationStore is consulted to reject tokens revoked before they expire.
+	tokenRevocationStore TokenRevocationStore
+
+	// tokenRevocationCacheTTL is how long tokens found not to be revoked are remembered for,
+	// if tokenRevocationCacheTTLSet.
+	tokenRevocationCacheTTL    time.Duration
+	tokenRevocationCacheTTLSet bool
+
+	// accessTokenTTL and refreshTokenTTL are how long issued access and refresh tokens
+	// are valid for. Refresh tokens are only issued when refreshTokenTTL is set.
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
 type authKeyData struct {
@@ -129,6 +166,50 @@ type WebRTCServerOptions struct {
 
 	// Config is the WebRTC specific configuration (i.e. ICE settings)
 	Config *webrtc.Configuration
+
+	// DataChannelPoolSize, when non-zero, opens this many additional data channels on every
+	// peer connection. Clients that set DialWebRTCOptions.DataChannelPoolSize spread their
+	// streams across them so that one busy or stalled stream does not hold up the others.
+	// Older clients ignore the additional data channels.
+	DataChannelPoolSize int
+
+	// InitialWindowSize is how many bytes of request messages a client may send on a stream
+	// before waiting for the server to read them. Zero uses a 1 MiB window.
+	InitialWindowSize int32
+
+	// InitialConnWindowSize is how many bytes of request messages a client may send across
+	// all streams of a connection before waiting for the server to read them. Zero uses a
+	// 4 MiB window.
+	InitialConnWindowSize int32
+
+	// KeepaliveInterval, when positive, is how often the server pings each client. A client
+	// that stops answering pings has its connection closed. Servers always answer pings from
+	// clients regardless.
+	KeepaliveInterval time.Duration
+
+	// KeepaliveMissedPings is how many pings in a row may go unanswered before a connection is
+	// closed. Zero allows 3.
+	KeepaliveMissedPings int
+
+	// ICERestartTimeout, when positive, lets clients that set DialWebRTCOptions.ICERestartTimeout
+	// recover disconnected connections by restarting ICE through the signaling server. A
+	// connection that has not recovered this long after disconnecting is closed. By default
+	// disconnected connections are closed right away. Keepalives must allow at least as long
+	// for the connection to recover.
+	ICERestartTimeout time.Duration
+
+	// Relay, if set, starts an embedded TURN and STUN relay next to the internal signaling
+	// server, which hands it out to the callers and answerers the server authenticated. It
+	// has no effect without internal signaling.
+	Relay *WebRTCRelayOptions
+
+	// CallAdmission configures how the internal signaling server prioritizes calls and caps
+	// them per host.
+	CallAdmission WebRTCCallAdmissionOptions
+
+	// OnlineHostsFilter, if set, enables ListOnlineHosts on the internal signaling server (see
+	// WebRTCSignalingServer.SetOnlineHostsFilter).
+	OnlineHostsFilter WebRTCOnlineHostsFilter
 }
 
 // A ServerOption changes the runtime behavior of the server.
@@ -156,7 +237,11 @@ func newFuncServerOption(f func(*serverOptions) error) *funcServerOption {
 // WithInternalBindAddress returns a ServerOption which sets the bind address
 // for the gRPC listener. If unset, the address is localhost on a
 // random port unless TLS is turned on and authentication is enabled
-// in which case the server will bind to all interfaces.
+// in which case the server will bind to all interfaces. Unix sockets are
+// bound to with "unix:path" or "unix:///absolute/path" addresses and, on
+// Linux, sockets in the abstract namespace with "unix-abstract:name"
+// addresses. Callers connected over a unix socket can authenticate with
+// MakeUnixPeerAuthHandler.
 func WithInternalBindAddress(address string) ServerOption {
 	return newFuncServerOption(func(o *serverOptions) error {
 		o.bindAddress = address
@@ -178,8 +263,8 @@ func WithExternalListenerAddress(address *net.TCPAddr) ServerOption {
 
 // WithInternalTLSConfig returns a ServerOption which sets the TLS config
 // for the internal listener. The internal listener does not request client
-// certificates; client authentication is handled at the application level
-// via credential-based auth.
+// certificates unless WithMTLSAuthHandler is used; client authentication is
+// otherwise handled at the application level via credential-based auth.
 func WithInternalTLSConfig(config *tls.Config) ServerOption {
 	return newFuncServerOption(func(o *serverOptions) error {
 		o.tlsConfig = config.Clone()
@@ -290,6 +375,38 @@ func WithJWTSignerKeyID(keyID string) ServerOption {
 	})
 }
 
+// WithAuthKeyRotation returns a ServerOption which replaces the key used to sign JWTs every
+// interval with a newly generated key of the same kind. Replaced keys keep verifying JWTs for
+// retireAfter, which should be longer than the tokens they signed are used for. Each key is
+// published by JWKSServer.JWKSHandler one interval before it starts signing so that consumers
+// caching the published keys know about it by then. Generated keys are only known to this server,
+// so this only works for a single instance; instances behind a load balancer should share their
+// keys with WithAuthKeySource instead.
+func WithAuthKeyRotation(interval, retireAfter time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		if interval <= 0 || retireAfter <= 0 {
+			return errors.New("key rotation interval and retirement must be positive")
+		}
+		o.authKeyRotationInterval = interval
+		o.authKeyRetireAfter = retireAfter
+		return nil
+	})
+}
+
+// WithAuthKeySource returns a ServerOption which gets the keys used to sign and verify JWTs from
+// the given source, and again every refreshInterval, instead of from the other auth key options.
+// Use it to share keys, and their rotation, between the instances of a server.
+func WithAuthKeySource(source AuthKeySource, refreshInterval time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		if source == nil || refreshInterval <= 0 {
+			return errors.New("auth key source and a positive refresh interval are required")
+		}
+		o.authKeySource = source
+		o.authKeySourceRefreshInterval = refreshInterval
+		return nil
+	})
+}
+
 // WithAuthAudience returns a ServerOption which sets the JWT audience (aud) to
 // use/expect in all processed JWTs. When unset, it will be debug logged that
 // the instance names will be used instead. It is recommended this option
@@ -343,6 +460,51 @@ func WithTLSAuthHandler(entities []string) ServerOption {
 	})
 }
 
+// WithMTLSAuthHandler returns a ServerOption which authenticates clients by certificates issued
+// by clientCAs and maps them to entities with entityMapper, or DefaultMTLSEntityMapper if it is
+// nil. Client certificates are requested from direct connections and the DTLS certificate of
+// WebRTC connections is used. Clients may also exchange their certificate for a JWT by
+// authenticating with CredentialsTypeMTLS.
+func WithMTLSAuthHandler(clientCAs *x509.CertPool, entityMapper MTLSEntityMapper) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		if clientCAs == nil {
+			return errors.New("client CAs must be set")
+		}
+		if entityMapper == nil {
+			entityMapper = DefaultMTLSEntityMapper
+		}
+		o.mtlsAuth = &mtlsAuthenticator{clientCAs: clientCAs, entityMapper: entityMapper}
+		return withCredAuthHandlers(CredentialsTypeMTLS, credAuthHandlers{
+			AuthHandler: o.mtlsAuth,
+		}).apply(o)
+	})
+}
+
+// WithAuthorizationPolicy returns a ServerOption which only lets authenticated entities call the
+// methods the given policy allows; other calls fail with PermissionDenied and are logged. Over
+// WebRTC, peers that did not authenticate with a client certificate are only known by the hosts
+// they connected to, without a credentials type or metadata. It cannot be combined with
+// WithEnsureAuthedHandler.
+func WithAuthorizationPolicy(policy AuthorizationPolicy) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+		o.authorizationPolicy = &policy
+		return nil
+	})
+}
+
+// WithAuditSink returns a ServerOption which records authentication attempts, token
+// verification failures, AuthenticateTo delegations and every call, along with who made it, to
+// the given sink.
+func WithAuditSink(sink AuditSink) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		o.auditSink = sink
+		return nil
+	})
+}
+
 // WithAuthHandler returns a ServerOption which adds an auth handler associated
 // to the given credential type to use for authentication requests.
 func WithAuthHandler(forType CredentialsType, handler AuthHandler) ServerOption {
@@ -517,6 +679,18 @@ func WithAllowUnauthenticatedHealthCheck() ServerOption {
 	})
 }
 
+// WithHealthService returns a server option that registers the standard gRPC health service
+// (grpc.health.v1.Health) over both gRPC and WebRTC. Every registered service, and the server as a
+// whole under the empty service name, is reported as serving until the server stops or its status
+// is changed with HealthStatusSetter.SetServingStatus. Combine with
+// WithAllowUnauthenticatedHealthCheck for probes that cannot authenticate.
+func WithHealthService() ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		o.healthService = true
+		return nil
+	})
+}
+
 // WithPublicMethods returns a server option with grpc methods that can bypass auth validation.
 func WithPublicMethods(fullMethods []string) ServerOption {
 	return newFuncServerOption(func(o *serverOptions) error {
@@ -524,3 +698,55 @@ func WithPublicMethods(fullMethods []string) ServerOption {
 		return nil
 	})
 }
+
+// WithRateLimiter returns a ServerOption that limits how often each method may be called by each
+// caller, identified by its authenticated entity (see ContextAuthEntity) and the address it
+// connects from. It runs after authentication and applies to both gRPC and WebRTC calls.
+// Rejected calls fail with codes.ResourceExhausted and carry a RetryAfterMetadataKey trailer.
+func WithRateLimiter(limiter RateLimiter) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		o.rateLimiter = limiter
+		return nil
+	})
+}
+
+// WithTokenRevocationStore returns a ServerOption that rejects access tokens revoked in the given
+// store. Sharing a store between servers revokes tokens on all of them at once. So that every
+// request does not wait on a shared store, tokens found not to be revoked are remembered for two
+// seconds (see WithTokenRevocationCacheTTL); a MemoryTokenRevocationStore is always consulted.
+func WithTokenRevocationStore(store TokenRevocationStore) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		o.tokenRevocationStore = store
+		return nil
+	})
+}
+
+// WithTokenRevocationCacheTTL returns a ServerOption that sets how long tokens found not to be
+// revoked are remembered for before the token revocation store is consulted again. This is how
+// long a revocation made elsewhere may take to cut off a token on this server. Zero disables
+// remembering.
+func WithTokenRevocationCacheTTL(ttl time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		if ttl < 0 {
+			return errors.New("token revocation cache TTL must not be negative")
+		}
+		o.tokenRevocationCacheTTL = ttl
+		o.tokenRevocationCacheTTLSet = true
+		return nil
+	})
+}
+
+// WithRefreshTokens returns a ServerOption that makes Authenticate issue access tokens that expire
+// after accessTokenTTL along with a refresh token that expires after refreshTokenTTL. Refresh tokens
+// are exchanged for new tokens with RefreshToken and can only be used once. Used refresh tokens are
+// recorded in the store set by WithTokenRevocationStore, or in memory if there is none.
+func WithRefreshTokens(accessTokenTTL, refreshTokenTTL time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) error {
+		if accessTokenTTL <= 0 || refreshTokenTTL <= 0 {
+			return errors.New("token lifetimes must be positive")
+		}
+		o.accessTokenTTL = accessTokenTTL
+		o.refreshTokenTTL = refreshTokenTTL
+		return nil
+	})
+}
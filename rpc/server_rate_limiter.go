@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.viam.com/utils"
+)
+
+// RetryAfterMetadataKey is the trailer metadata key set on rate limited calls. Its value is the
+// number of whole seconds the caller should wait before trying again.
+const RetryAfterMetadataKey = "retry-after"
+
+// A RateLimiter decides whether a request identified by a key may proceed.
+type RateLimiter interface {
+	// Allow records a request for the given key. It returns an error when the request must be
+	// rejected, usually one made by RateLimitExceededError.
+	Allow(ctx context.Context, key string) error
+}
+
+// RateLimitExceededError returns a codes.ResourceExhausted error for the given key that tells
+// the caller to wait retryAfter before trying again.
+func RateLimitExceededError(key string, config RateLimitConfig, retryAfter time.Duration) error {
+	st := status.Newf(codes.ResourceExhausted,
+		"request exceeds rate limit (limit: %d in %v) for %s",
+		config.MaxRequests, config.Window, key)
+	if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// RateLimitRetryAfter returns how long a rate limited caller was told to wait by err.
+func RateLimitRetryAfter(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// rateLimitKey identifies the caller of a method by the authenticated entity, if any, and the
+// host it connects from.
+func rateLimitKey(ctx context.Context, method string) string {
+	var entity string
+	if entityInfo, ok := ContextAuthEntity(ctx); ok {
+		entity = entityInfo.Entity
+	}
+	return strings.Join([]string{method, entity, peerHost(ctx)}, ":")
+}
+
+// peerHost returns the host, without a port, that the caller connects from.
+func peerHost(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p != nil && p.Addr != nil {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return host
+		}
+		return addr
+	}
+	if pc, ok := ContextPeerConnection(ctx); ok {
+		if candPair, ok := webrtcPeerConnCandPair(pc); ok && candPair.Remote != nil {
+			return candPair.Remote.Address
+		}
+	}
+	return ""
+}
+
+// retryAfterTrailer returns the trailer telling a caller rejected with err when to retry.
+func retryAfterTrailer(err error) (metadata.MD, bool) {
+	retryAfter, ok := RateLimitRetryAfter(err)
+	if !ok {
+		return nil, false
+	}
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	return metadata.Pairs(RetryAfterMetadataKey, strconv.FormatInt(seconds, 10)), true
+}
+
+func rateLimitUnaryInterceptor(limiter RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := limiter.Allow(ctx, rateLimitKey(ctx, info.FullMethod)); err != nil {
+			if trailer, ok := retryAfterTrailer(err); ok {
+				utils.UncheckedError(grpc.SetTrailer(ctx, trailer))
+			}
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func rateLimitStreamInterceptor(limiter RateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, serverStream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := serverStream.Context()
+		if err := limiter.Allow(ctx, rateLimitKey(ctx, info.FullMethod)); err != nil {
+			if trailer, ok := retryAfterTrailer(err); ok {
+				serverStream.SetTrailer(trailer)
+			}
+			return err
+		}
+		return handler(srv, serverStream)
+	}
+}
+
+var _ RateLimiter = (*MemoryRateLimiter)(nil)
+
+// A MemoryRateLimiter is a token bucket rate limiter kept in memory. Every key starts with
+// MaxRequests tokens which are refilled evenly over Window. It is suited to a single server; use
+// a MongoDBRateLimiter to share limits between servers.
+type MemoryRateLimiter struct {
+	config RateLimitConfig
+	// now is swapped out in tests.
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewMemoryRateLimiter returns a new in-memory token bucket rate limiter allowing bursts of up
+// to MaxRequests requests per key and MaxRequests requests per key every Window on average.
+func NewMemoryRateLimiter(config RateLimitConfig) *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		config:  config,
+		now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// Allow takes a token from the bucket for key, failing with codes.ResourceExhausted if it is empty.
+func (rl *MemoryRateLimiter) Allow(ctx context.Context, key string) error {
+	capacity := float64(rl.config.MaxRequests)
+	perToken := rl.config.Window / time.Duration(max(rl.config.MaxRequests, 1))
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	rl.sweep(now)
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		rl.buckets[key] = bucket
+	} else if perToken > 0 {
+		refilled := float64(now.Sub(bucket.updated)) / float64(perToken)
+		bucket.tokens = min(capacity, bucket.tokens+refilled)
+		bucket.updated = now
+	}
+
+	if bucket.tokens < 1 {
+		retryAfter := time.Duration((1 - bucket.tokens) * float64(perToken))
+		return RateLimitExceededError(key, rl.config, retryAfter)
+	}
+	bucket.tokens--
+	return nil
+}
+
+// sweep forgets buckets that have had time to fill up again so that callers that go away do not
+// hold on to memory. It runs at most once per window.
+func (rl *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rl.config.Window {
+		return
+	}
+	rl.lastSweep = now
+	for key, bucket := range rl.buckets {
+		if now.Sub(bucket.updated) >= rl.config.Window {
+			delete(rl.buckets, key)
+		}
+	}
+}
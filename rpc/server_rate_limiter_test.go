@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestMemoryRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	rl := NewMemoryRateLimiter(RateLimitConfig{MaxRequests: 2, Window: 10 * time.Second})
+	rl.now = func() time.Time { return now }
+
+	test.That(t, rl.Allow(ctx, "a"), test.ShouldBeNil)
+	test.That(t, rl.Allow(ctx, "a"), test.ShouldBeNil)
+	err := rl.Allow(ctx, "a")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.ResourceExhausted)
+	retryAfter, ok := RateLimitRetryAfter(err)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, retryAfter, test.ShouldEqual, 5*time.Second)
+
+	// keys are limited separately.
+	test.That(t, rl.Allow(ctx, "b"), test.ShouldBeNil)
+
+	// tokens are refilled over the window.
+	now = now.Add(4 * time.Second)
+	err = rl.Allow(ctx, "a")
+	retryAfter, ok = RateLimitRetryAfter(err)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, retryAfter, test.ShouldAlmostEqual, time.Second, float64(time.Millisecond))
+	now = now.Add(time.Second)
+	test.That(t, rl.Allow(ctx, "a"), test.ShouldBeNil)
+	test.That(t, rl.Allow(ctx, "a"), test.ShouldNotBeNil)
+
+	// idle keys are forgotten.
+	now = now.Add(time.Minute)
+	test.That(t, rl.Allow(ctx, "c"), test.ShouldBeNil)
+	rl.mu.Lock()
+	test.That(t, rl.buckets, test.ShouldHaveLength, 1)
+	rl.mu.Unlock()
+
+	_, ok = RateLimitRetryAfter(status.Error(codes.Internal, "nope"))
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+type recordingRateLimiter struct {
+	mu      sync.Mutex
+	limiter RateLimiter
+	keys    []string
+}
+
+func (rl *recordingRateLimiter) reset(limiter RateLimiter) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.limiter = limiter
+	rl.keys = nil
+}
+
+func (rl *recordingRateLimiter) Allow(ctx context.Context, key string) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.keys = append(rl.keys, key)
+	return rl.limiter.Allow(ctx, key)
+}
+
+func TestServerRateLimiter(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+	config := RateLimitConfig{MaxRequests: 2, Window: time.Minute}
+	limiter := &recordingRateLimiter{limiter: NewMemoryRateLimiter(config)}
+
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+		}),
+		WithUnauthenticated(),
+		WithRateLimiter(limiter),
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	err = rpcServer.RegisterServiceServer(
+		context.Background(),
+		&pb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		pb.RegisterEchoServiceHandlerFromEndpoint,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	echoMethod := "/proto.rpc.examples.echo.v1.EchoService/Echo"
+	echoMultipleMethod := "/proto.rpc.examples.echo.v1.EchoService/EchoMultiple"
+
+	testLimits := func(t *testing.T, client pb.EchoServiceClient) {
+		t.Helper()
+		for i := 0; i < config.MaxRequests; i++ {
+			_, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+			test.That(t, err, test.ShouldBeNil)
+		}
+		var trailer metadata.MD
+		_, err := client.Echo(context.Background(), &pb.EchoRequest{Message: "hello"}, grpc.Trailer(&trailer))
+		test.That(t, status.Code(err), test.ShouldEqual, codes.ResourceExhausted)
+		test.That(t, trailer.Get(RetryAfterMetadataKey), test.ShouldResemble, []string{"30"})
+
+		// streams are limited too, separately from other methods.
+		for i := 0; i < config.MaxRequests; i++ {
+			stream, err := client.EchoMultiple(context.Background(), &pb.EchoMultipleRequest{Message: "hello"})
+			test.That(t, err, test.ShouldBeNil)
+			_, err = stream.Recv()
+			test.That(t, err, test.ShouldBeNil)
+		}
+		stream, err := client.EchoMultiple(context.Background(), &pb.EchoMultipleRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = stream.Recv()
+		test.That(t, status.Code(err), test.ShouldEqual, codes.ResourceExhausted)
+		test.That(t, stream.Trailer().Get(RetryAfterMetadataKey), test.ShouldResemble, []string{"30"})
+	}
+
+	keysFor := func(method string) []string {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		var keys []string
+		for _, key := range limiter.keys {
+			if strings.HasPrefix(key, method+":") {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+
+	t.Run("grpc", func(t *testing.T) {
+		limiter.reset(NewMemoryRateLimiter(config))
+		conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		testLimits(t, pb.NewEchoServiceClient(conn))
+
+		keys := keysFor(echoMethod)
+		test.That(t, keys, test.ShouldHaveLength, config.MaxRequests+1)
+		test.That(t, keys[0], test.ShouldEqual, echoMethod+"::127.0.0.1")
+	})
+
+	t.Run("webrtc", func(t *testing.T) {
+		rtcConn, err := dialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, dialOptions{
+			webrtcOpts: DialWebRTCOptions{
+				SignalingInsecure: true,
+			},
+			webrtcOptsSet: true,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		limiter.reset(NewMemoryRateLimiter(config))
+		testLimits(t, pb.NewEchoServiceClient(rtcConn))
+
+		keys := keysFor(echoMultipleMethod)
+		test.That(t, keys, test.ShouldHaveLength, config.MaxRequests+1)
+		test.That(t, keys[0], test.ShouldStartWith, echoMultipleMethod+":"+internalSignalingHost+":")
+		test.That(t, keys[0], test.ShouldNotEndWith, ":")
+	})
+}
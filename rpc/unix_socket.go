@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// CredentialsTypeUnixPeer is for entities authenticated by the credentials of the process on the
+// other end of a unix socket, as reported by the kernel. The credentials carry no payload.
+const CredentialsTypeUnixPeer = CredentialsType("unix-peer")
+
+// UnixPeerCredentials are the credentials of the process on the other end of a unix socket.
+type UnixPeerCredentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// ContextUnixPeerCredentials returns the credentials of the process that made the call, if it
+// is connected to the internal listener over a unix socket and the platform reports them. Only
+// Linux does at the moment.
+func ContextUnixPeerCredentials(ctx context.Context) (UnixPeerCredentials, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return UnixPeerCredentials{}, false
+	}
+	authInfo, ok := p.AuthInfo.(unixPeerAuthInfo)
+	if !ok {
+		return UnixPeerCredentials{}, false
+	}
+	return authInfo.creds, true
+}
+
+// MakeUnixPeerAuthHandler returns an AuthHandler for CredentialsTypeUnixPeer that authenticates
+// processes connected to the internal listener over a unix socket and running as one of the
+// given user IDs, or as the user of this process if none are given. Entities are the decimal
+// user IDs they run as (see WithUnixPeerCredentials) and are given "uid", "gid" and "pid"
+// metadata.
+func MakeUnixPeerAuthHandler(uids ...uint32) AuthHandler {
+	if len(uids) == 0 {
+		uids = []uint32{uint32(os.Getuid())} //nolint:gosec
+	}
+	return AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+		creds, ok := ContextUnixPeerCredentials(ctx)
+		if !ok {
+			return nil, errInvalidCredentials
+		}
+		uid := strconv.FormatUint(uint64(creds.UID), 10)
+		if entity != uid || !slices.Contains(uids, creds.UID) {
+			return nil, errInvalidCredentials
+		}
+		return map[string]string{
+			"uid": uid,
+			"gid": strconv.FormatUint(uint64(creds.GID), 10),
+			"pid": strconv.FormatInt(int64(creds.PID), 10),
+		}, nil
+	})
+}
+
+// unixSocketPath returns the path of the socket named by a "unix:" or "unix-abstract:" address
+// in the form net.Listen expects, where names in the abstract namespace start with "@".
+func unixSocketPath(address string) (string, bool) {
+	switch {
+	case strings.HasPrefix(address, "unix-abstract:"):
+		return "@" + strings.TrimPrefix(address, "unix-abstract:"), true
+	case strings.HasPrefix(address, "unix://"):
+		return strings.TrimPrefix(address, "unix://"), true
+	case strings.HasPrefix(address, "unix:"):
+		return strings.TrimPrefix(address, "unix:"), true
+	default:
+		return "", false
+	}
+}
+
+func isUnixSocketAddress(address string) bool {
+	_, ok := unixSocketPath(address)
+	return ok
+}
+
+// listenerTarget returns the gRPC target that dials the given listener address.
+func listenerTarget(addr net.Addr) string {
+	unixAddr, ok := addr.(*net.UnixAddr)
+	if !ok {
+		return addr.String()
+	}
+	if name, ok := strings.CutPrefix(unixAddr.Name, "@"); ok {
+		return "unix-abstract:" + name
+	}
+	return "unix:" + unixAddr.Name
+}
+
+// gatewayTarget is the gRPC target the gateway dials a gatewayListener with.
+const gatewayTarget = "passthrough:///gateway"
+
+// newGatewayListener returns an in-memory listener for the gateway to reach the gRPC server
+// through when the internal listener is a unix socket. The gateway runs in this process, so
+// connections it made to the socket would carry this process' peer credentials and let anyone
+// who can reach the gateway authenticate as this process' user.
+func newGatewayListener() *bufconn.Listener {
+	return bufconn.Listen(1 << 20)
+}
+
+// unixPeerAuthInfo is the AuthInfo of plaintext connections to a unix socket whose peer
+// credentials could be read.
+type unixPeerAuthInfo struct {
+	credentials.CommonAuthInfo
+	creds UnixPeerCredentials
+}
+
+func (unixPeerAuthInfo) AuthType() string {
+	return "unix-peer"
+}
+
+// unixPeerTransportCredentials are plaintext credentials that also record the peer credentials
+// of connections accepted on a unix socket.
+type unixPeerTransportCredentials struct {
+	credentials.TransportCredentials
+}
+
+func newUnixPeerTransportCredentials() credentials.TransportCredentials {
+	return unixPeerTransportCredentials{insecure.NewCredentials()}
+}
+
+func (c unixPeerTransportCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	unixConn, ok := rawConn.(*net.UnixConn)
+	if !ok {
+		return conn, authInfo, nil
+	}
+	creds, err := readUnixPeerCredentials(unixConn)
+	if err != nil {
+		// callers just cannot authenticate by their peer credentials.
+		return conn, authInfo, nil //nolint:nilerr
+	}
+	return conn, unixPeerAuthInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity},
+		creds:          creds,
+	}, nil
+}
+
+func (c unixPeerTransportCredentials) Clone() credentials.TransportCredentials {
+	return unixPeerTransportCredentials{c.TransportCredentials.Clone()}
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package rpc
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// readUnixPeerCredentials reads the credentials of the peer of conn with SO_PEERCRED.
+func readUnixPeerCredentials(conn *net.UnixConn) (UnixPeerCredentials, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return UnixPeerCredentials{}, err
+	}
+	var ucred *unix.Ucred
+	var credErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		ucred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return UnixPeerCredentials{}, err
+	}
+	if credErr != nil {
+		return UnixPeerCredentials{}, credErr
+	}
+	return UnixPeerCredentials{PID: ucred.Pid, UID: ucred.Uid, GID: ucred.Gid}, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package rpc
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// readUnixPeerCredentials is only supported on Linux.
+func readUnixPeerCredentials(conn *net.UnixConn) (UnixPeerCredentials, error) {
+	return UnixPeerCredentials{}, errors.New("unix peer credentials are not supported on this platform")
+}
@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/google/uuid"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+)
+
+func TestServerUnixSocket(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials and abstract sockets are only supported on linux")
+	}
+	logger := golog.NewTestLogger(t)
+	uid := strconv.Itoa(os.Getuid())
+
+	socketPath := filepath.Join(t.TempDir(), "rpc.sock")
+	abstractName := "rpc-test-" + uuid.NewString()
+	for _, tc := range []struct {
+		name    string
+		address string
+		target  string
+	}{
+		{"path", "unix://" + socketPath, "unix:" + socketPath},
+		{"abstract", "unix-abstract:" + abstractName, "unix-abstract:" + abstractName},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var peerCreds []UnixPeerCredentials
+			rpcServer, err := NewServer(
+				logger,
+				WithInternalBindAddress(tc.address),
+				WithAuthHandler(CredentialsTypeUnixPeer, MakeUnixPeerAuthHandler()),
+				WithUnaryServerInterceptor(func(
+					ctx context.Context,
+					req interface{},
+					info *grpc.UnaryServerInfo,
+					handler grpc.UnaryHandler,
+				) (interface{}, error) {
+					if creds, ok := ContextUnixPeerCredentials(ctx); ok {
+						peerCreds = append(peerCreds, creds)
+					}
+					return handler(ctx, req)
+				}),
+			)
+			test.That(t, err, test.ShouldBeNil)
+			echoServer := &echoserver.Server{
+				MustContextAuthEntity: func(ctx context.Context) echoserver.RPCEntityInfo {
+					ent := MustContextAuthEntity(ctx)
+					return echoserver.RPCEntityInfo{
+						Entity: ent.Entity,
+						Data:   ent.Data,
+					}
+				},
+			}
+			echoServer.SetAuthorized(true)
+			echoServer.SetExpectedAuthEntity(uid)
+			test.That(t, rpcServer.RegisterServiceServer(
+				context.Background(),
+				&pb.EchoService_ServiceDesc,
+				echoServer,
+				pb.RegisterEchoServiceHandlerFromEndpoint,
+			), test.ShouldBeNil)
+			test.That(t, rpcServer.Start(), test.ShouldBeNil)
+			defer func() {
+				test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+			}()
+			test.That(t, listenerTarget(rpcServer.InternalAddr()), test.ShouldEqual, tc.target)
+
+			echo := func(t *testing.T, opts ...DialOption) error {
+				t.Helper()
+				conn, err := Dial(context.Background(), tc.address, logger, opts...)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					test.That(t, conn.Close(), test.ShouldBeNil)
+				}()
+				_, err = pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+				return err
+			}
+
+			test.That(t, echo(t, WithUnixPeerCredentials()), test.ShouldBeNil)
+			test.That(t, peerCreds, test.ShouldNotBeEmpty)
+			test.That(t, peerCreds[len(peerCreds)-1], test.ShouldResemble, UnixPeerCredentials{
+				PID: int32(os.Getpid()),
+				UID: uint32(os.Getuid()),
+				GID: uint32(os.Getgid()),
+			})
+
+			err = echo(t)
+			test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+
+			// the entity must be the user the caller runs as.
+			err = echo(t, WithEntityCredentials(strconv.Itoa(os.Getuid()+1), Credentials{Type: CredentialsTypeUnixPeer}))
+			test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+		})
+	}
+
+	t.Run("gateway", func(t *testing.T) {
+		// the gateway dials the internal listener from this process so its calls must not carry
+		// this process' credentials.
+		rpcServer, err := NewServer(
+			logger,
+			WithInternalBindAddress("unix:"+filepath.Join(t.TempDir(), "rpc.sock")),
+			WithAuthHandler(CredentialsTypeUnixPeer, MakeUnixPeerAuthHandler()),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		httpListener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- rpcServer.Serve(httpListener)
+		}()
+		defer func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+			test.That(t, <-errChan, test.ShouldBeNil)
+		}()
+
+		httpURL := fmt.Sprintf("http://%s/rpc/v1/authenticate?entity=%s&credentials.type=%s",
+			httpListener.Addr().String(), uid, CredentialsTypeUnixPeer)
+		req, err := http.NewRequest(http.MethodPost, httpURL, nil)
+		test.That(t, err, test.ShouldBeNil)
+		httpResp, err := http.DefaultClient.Do(req)
+		test.That(t, err, test.ShouldBeNil)
+		defer httpResp.Body.Close()
+		test.That(t, httpResp.StatusCode, test.ShouldEqual, http.StatusUnauthorized)
+	})
+
+	t.Run("other users", func(t *testing.T) {
+		socketAddress := "unix:" + filepath.Join(t.TempDir(), "rpc.sock")
+		rpcServer, err := NewServer(
+			logger,
+			WithInternalBindAddress(socketAddress),
+			WithAuthHandler(CredentialsTypeUnixPeer, MakeUnixPeerAuthHandler(uint32(os.Getuid()+1))),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, rpcServer.RegisterServiceServer(
+			context.Background(),
+			&pb.EchoService_ServiceDesc,
+			&echoserver.Server{},
+		), test.ShouldBeNil)
+		test.That(t, rpcServer.Start(), test.ShouldBeNil)
+		defer func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		}()
+
+		conn, err := Dial(context.Background(), socketAddress, logger, WithUnixPeerCredentials())
+		if err == nil {
+			defer func() {
+				test.That(t, conn.Close(), test.ShouldBeNil)
+			}()
+			_, err = pb.NewEchoServiceClient(conn).Echo(context.Background(), &pb.EchoRequest{Message: "hello"})
+		}
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unauthenticated)
+	})
+}
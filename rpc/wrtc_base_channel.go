@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/pion/sctp"
 	"github.com/viamrobotics/webrtc/v3"
@@ -28,8 +29,35 @@ type webrtcBaseChannel struct {
 	logger                  utils.ZapCompatibleLogger
 	bufferWriteMu           sync.RWMutex
 	bufferWriteCond         *sync.Cond
+
+	// pinger pings the peer when keepalives are configured.
+	pinger *pinger
+
+	// iceRestartTimeout, when positive, is how long a disconnected connection is given to
+	// recover before the channel is closed. restartICE, if set, renegotiates ICE with new
+	// candidates; only the offering (client) side sets it. iceRestartTimer runs while the
+	// connection is disconnected and iceRestarting is set while restartICE runs. All are
+	// guarded by mu.
+	iceRestartTimeout time.Duration
+	restartICE        func(ctx context.Context) error
+	iceRestartTimer   *time.Timer
+	iceRestarting     bool
+
+	// poolMu guards poolChannels.
+	poolMu sync.Mutex
+	// poolChannels are the additional data channels streams may be multiplexed over, keyed by
+	// their SCTP stream identifier. The primary data channel is never in this map.
+	poolChannels map[uint32]*webrtc.DataChannel
 }
 
+// dataChannelPoolLabel is the label of every additional data channel a server opens when data
+// channel pooling is enabled. The primary data channel keeps the "data" label.
+const dataChannelPoolLabel = "data-pool"
+
+// errUnknownDataChannel is returned when a stream refers to a data channel that is not part of
+// the connection.
+var errUnknownDataChannel = errors.New("stream refers to an unknown data channel")
+
 const bufferThreshold = 1024 * 1024
 
 func newBaseChannel(
@@ -55,11 +83,7 @@ func newBaseChannel(
 	dataChannel.OnClose(ch.Close)
 	dataChannel.OnError(ch.onChannelError)
 	dataChannel.SetBufferedAmountLowThreshold(bufferThreshold)
-	dataChannel.OnBufferedAmountLow(func() {
-		ch.bufferWriteMu.Lock()
-		ch.bufferWriteCond.Broadcast()
-		ch.bufferWriteMu.Unlock()
-	})
+	dataChannel.OnBufferedAmountLow(ch.onBufferedAmountLow)
 
 	var connID string
 	var connIDMu sync.Mutex
@@ -72,11 +96,16 @@ func newBaseChannel(
 			webrtc.ICEConnectionStateFailed,
 			webrtc.ICEConnectionStateClosed:
 
-			// The Disconnected/Failed/Closed states are all interpreted as terminal. In theory,
-			// WebRTC allows for moving from the disconnected -> connected state if the network
-			// heals or new candidates are presented. We forgo that optimization of preserving an
-			// existing PeerConnection object and instead opt for clients to redial and perform a
-			// new round of signaling/answering.
+			// The Disconnected/Failed/Closed states are all interpreted as terminal unless ICE
+			// restarts are enabled. In theory, WebRTC allows for moving from the disconnected ->
+			// connected state if the network heals or new candidates are presented. By default we
+			// forgo that optimization of preserving an existing PeerConnection object and instead
+			// opt for clients to redial and perform a new round of signaling/answering.
+			//
+			// Presenting new candidates takes an ICE restart, which cannot be negotiated over the
+			// `negotiation` DataChannel: pion drops the selected candidate pair as soon as the
+			// restart offer is created, so the offer could never reach the peer. It goes through
+			// the signaling server instead (see restartICE).
 			connIDMu.Lock()
 			currConnID := connID
 			connIDMu.Unlock()
@@ -86,6 +115,14 @@ func newBaseChannel(
 				return
 			}
 
+			if connectionState != webrtc.ICEConnectionStateClosed && ch.awaitICERestart(currConnID) {
+				logger.Infow("connection state changed; waiting for it to recover",
+					"conn_id", currConnID,
+					"conn_state", connectionState.String(),
+				)
+				return
+			}
+
 			// only log at Info if state change is unexpected, e.g. not closed
 			if connectionState != webrtc.ICEConnectionStateClosed {
 				logger.Infow("connection state changed",
@@ -104,6 +141,7 @@ func newBaseChannel(
 			// perform that as necessary.
 			ch.Close()
 		case webrtc.ICEConnectionStateConnected:
+			ch.stopICERestartTimer()
 			if onICEConnected != nil {
 				// The user of `onICEConnected` waits for a few seconds before enumerating/logging
 				// candidates. Spin off a goroutine to avoid consequences of sleeping.
@@ -176,7 +214,12 @@ func (ch *webrtcBaseChannel) Close() {
 	ch.cancel()
 	ch.bufferWriteCond.Broadcast()
 	ch.bufferWriteMu.Unlock()
+	pinger := ch.pinger
+	if ch.iceRestartTimer != nil {
+		ch.iceRestartTimer.Stop()
+	}
 	ch.mu.Unlock()
+	pinger.stop()
 
 	ch.activeBackgroundWorkers.Wait()
 	if ch.wrtcServer != nil {
@@ -196,6 +239,49 @@ func (ch *webrtcBaseChannel) onChannelOpen() {
 	close(ch.ready)
 }
 
+// onBufferedAmountLow wakes up writers waiting on any of the channel's data channels. All data
+// channels share the same condition variable; woken writers recheck their own data channel.
+func (ch *webrtcBaseChannel) onBufferedAmountLow() {
+	ch.bufferWriteMu.Lock()
+	ch.bufferWriteCond.Broadcast()
+	ch.bufferWriteMu.Unlock()
+}
+
+// addPoolChannel makes an open data channel available for streams to be multiplexed over. Its
+// messages are handled by onMessage, just like the primary data channel. Losing a pooled data
+// channel closes the whole channel since its streams cannot continue elsewhere.
+func (ch *webrtcBaseChannel) addPoolChannel(dc *webrtc.DataChannel, onMessage func(webrtc.DataChannelMessage)) uint32 {
+	id := uint32(*dc.ID())
+	dc.SetBufferedAmountLowThreshold(bufferThreshold)
+	dc.OnBufferedAmountLow(ch.onBufferedAmountLow)
+	dc.OnError(ch.onChannelError)
+	dc.OnClose(ch.Close)
+	dc.OnMessage(onMessage)
+
+	ch.poolMu.Lock()
+	if ch.poolChannels == nil {
+		ch.poolChannels = map[uint32]*webrtc.DataChannel{}
+	}
+	ch.poolChannels[id] = dc
+	ch.poolMu.Unlock()
+	return id
+}
+
+// dataChannelByID returns the data channel with the given SCTP stream identifier. Zero always
+// refers to the primary data channel.
+func (ch *webrtcBaseChannel) dataChannelByID(id uint32) (*webrtc.DataChannel, error) {
+	if id == 0 {
+		return ch.dataChannel, nil
+	}
+	ch.poolMu.Lock()
+	defer ch.poolMu.Unlock()
+	dc, ok := ch.poolChannels[id]
+	if !ok {
+		return nil, errUnknownDataChannel
+	}
+	return dc, nil
+}
+
 // isUserInitiatedAbortChunkErr returns true if the error is an abort chunk
 // error that the user initiated through Close. Certain browsers (Safari,
 // Chrome and potentially others) close RTCPeerConnections with this type of
@@ -217,6 +303,16 @@ func (ch *webrtcBaseChannel) onChannelError(err error) {
 const maxDataChannelSize = 65535
 
 func (ch *webrtcBaseChannel) write(msg proto.Message) error {
+	return ch.writeOn(0, msg)
+}
+
+// writeOn writes the message to the data channel with the given SCTP stream identifier, where
+// zero is the primary data channel.
+func (ch *webrtcBaseChannel) writeOn(channelID uint32, msg proto.Message) error {
+	dataChannel, err := ch.dataChannelByID(channelID)
+	if err != nil {
+		return err
+	}
 	data, err := proto.Marshal(msg)
 	if err != nil {
 		return err
@@ -231,14 +327,14 @@ func (ch *webrtcBaseChannel) write(msg proto.Message) error {
 		// RSDK-9239: Only wait when we're strictly over the threshold. Pion invokes the registered
 		// callback (notify `bufferWriteCond`) when moving from larger than bufferThreshold to less
 		// than or equal to.
-		if ch.dataChannel.BufferedAmount() > bufferThreshold {
+		if dataChannel.BufferedAmount() > bufferThreshold {
 			ch.bufferWriteCond.Wait()
 			continue
 		}
 		ch.bufferWriteCond.L.Unlock()
 		break
 	}
-	if err := ch.dataChannel.Send(data); err != nil {
+	if err := dataChannel.Send(data); err != nil {
 		if strings.Contains(err.Error(), "sending payload data in non-established state") {
 			return io.ErrClosedPipe
 		}
@@ -9,7 +9,10 @@ import (
 	"sync/atomic"
 
 	protov1 "github.com/golang/protobuf/proto" //nolint:staticcheck
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/utils"
@@ -17,18 +20,36 @@ import (
 )
 
 type webrtcBaseStream struct {
-	mu            sync.RWMutex
-	ctx           context.Context
-	cancel        context.CancelFunc
-	stream        *webrtcpb.Stream
-	msgCh         chan []byte
-	onDone        func(id uint64)
-	err           error
-	recvClosed    atomic.Bool
-	closed        atomic.Bool
-	logger        utils.ZapCompatibleLogger
-	packetBuf     bytes.Buffer
-	activeSenders sync.WaitGroup
+	mu         sync.RWMutex
+	ctx        context.Context
+	cancel     context.CancelFunc
+	stream     *webrtcpb.Stream
+	msgs       *messageQueue
+	onDone     func(id uint64)
+	err        error
+	recvClosed atomic.Bool
+	closed     atomic.Bool
+	logger     utils.ZapCompatibleLogger
+	packetBuf  bytes.Buffer
+
+	// sendWindow and connSendWindow limit the messages sent on this stream when the peer flow
+	// controls them; both are nil otherwise.
+	sendWindow     *sendWindow
+	connSendWindow *sendWindow
+	// recvWindow and connRecvWindow collect the bytes of read messages to hand back to the peer
+	// when this side flow controls received messages; both are nil otherwise.
+	recvWindow     *recvWindow
+	connRecvWindow *recvWindow
+	// unreadBytes counts the window bytes of packets received since the last queued message
+	// that have not been handed back yet.
+	unreadBytes int64
+	// writeWindowUpdate sends a WindowUpdate for the given stream to the peer.
+	writeWindowUpdate func(stream *webrtcpb.Stream, increment uint32) error
+
+	// sendCompressor and recvCompressor are the compressors negotiated for messages sent
+	// and received on this stream, if any. They are set while processing headers.
+	sendCompressor encoding.Compressor
+	recvCompressor encoding.Compressor
 }
 
 // newWebRTCBaseStream makes a new webrtcBaseStream where the context should originate
@@ -49,10 +70,92 @@ func newWebRTCBaseStream(
 		onDone: onDone,
 		logger: logger,
 	}
-	bs.msgCh = make(chan []byte, 1)
+	bs.msgs = newMessageQueue()
 	return &bs
 }
 
+// A queuedMessage is a received message waiting to be read along with the window bytes to
+// hand back to the peer once it is.
+type queuedMessage struct {
+	data        []byte
+	windowBytes int64
+}
+
+// A messageQueue holds received messages, in order, until they are read.
+type messageQueue struct {
+	mu     sync.Mutex
+	msgs   []queuedMessage
+	closed bool
+	// changed is closed and replaced whenever msgs or closed changes.
+	changed chan struct{}
+}
+
+func newMessageQueue() *messageQueue {
+	return &messageQueue{changed: make(chan struct{})}
+}
+
+func (q *messageQueue) notifyLocked() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// push adds a message to the queue unless it is closed. When limit is positive, push first
+// waits for there to be fewer than limit messages queued, giving up when ctx is done.
+func (q *messageQueue) push(ctx context.Context, msg queuedMessage, limit int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for limit > 0 && len(q.msgs) >= limit && !q.closed {
+		changed := q.changed
+		q.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			return false
+		case <-changed:
+		}
+		q.mu.Lock()
+	}
+	if q.closed {
+		return false
+	}
+	q.msgs = append(q.msgs, msg)
+	q.notifyLocked()
+	return true
+}
+
+// pop removes the oldest message from the queue. If there is none, it reports whether the
+// queue is closed and otherwise returns a channel that is closed once the queue changes.
+func (q *messageQueue) pop() (msg queuedMessage, ok, closed bool, changed <-chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.msgs) == 0 {
+		return queuedMessage{}, false, q.closed, q.changed
+	}
+	msg = q.msgs[0]
+	q.msgs[0] = queuedMessage{}
+	q.msgs = q.msgs[1:]
+	q.notifyLocked()
+	return msg, true, q.closed, nil
+}
+
+// empty reports whether no messages are waiting to be read.
+func (q *messageQueue) empty() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.msgs) == 0
+}
+
+// close stops the queue from accepting messages. Queued messages may still be read.
+func (q *messageQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.notifyLocked()
+}
+
 // Context returns the context for this stream.
 func (s *webrtcBaseStream) Context() context.Context {
 	return s.ctx
@@ -71,31 +174,21 @@ func (s *webrtcBaseStream) RecvMsg(m interface{}) error {
 		m = protov1.MessageV2(v1Msg)
 	}
 
-	checkLastOrErr := func(origErr error) ([]byte, error) {
-		// checkLastOrErr is an attempt to return the most informative, relevant
-		// error message to the user when we cannot receive a message in the base
-		// stream for some reason.
-		select {
-		case msgBytes, ok := <-s.msgCh:
-			if ok {
-				return msgBytes, nil
-			}
-			s.mu.Lock()
-			if s.err != nil {
-				s.mu.Unlock()
-				if errors.Is(s.err, errExpectedClosure) {
-					return nil, io.EOF
-				}
-				return nil, s.err
+	// closedErr is an attempt to return the most informative, relevant error message to the
+	// user when we cannot receive a message in the base stream for some reason.
+	closedErr := func(origErr error) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.err != nil {
+			if errors.Is(s.err, errExpectedClosure) {
+				return io.EOF
 			}
-			s.mu.Unlock()
-			if origErr == nil {
-				return nil, io.EOF
-			}
-			return nil, origErr
-		default:
-			return nil, nil
+			return s.err
+		}
+		if origErr == nil {
+			return io.EOF
 		}
+		return origErr
 	}
 
 	// RSDK-4473: There are three ways a stream can be signaled that it should
@@ -103,24 +196,31 @@ func (s *webrtcBaseStream) RecvMsg(m interface{}) error {
 	//   - `s.ctx` errors due to a timeout or some other grpc/webrtc error.
 	//   - `s.ctx` is canceled when the underlying webrtc data channel
 	//      connection experiences an error.
-	//   - `s.msgCh` is closed when the stream, channel or connection has been
+	//   - `s.msgs` is closed when the stream, channel or connection has been
 	//      closed.
-	select {
-	case <-s.ctx.Done():
-		msgBytes, err := checkLastOrErr(s.ctx.Err())
-		if err != nil {
-			return err
+	// Messages that were already received are read before any of these are reported.
+	for {
+		msg, ok, closed, changed := s.msgs.pop()
+		if ok {
+			s.handBackWindow(msg.windowBytes)
+			return proto.Unmarshal(msg.data, m.(proto.Message))
 		}
-		if msgBytes != nil {
-			return proto.Unmarshal(msgBytes, m.(proto.Message))
+		if closed {
+			return closedErr(nil)
 		}
-		return s.ctx.Err()
-	case msgBytes, ok := <-s.msgCh:
-		if ok {
-			return proto.Unmarshal(msgBytes, m.(proto.Message))
+		select {
+		case <-s.ctx.Done():
+			msg, ok, closed, _ := s.msgs.pop()
+			if ok {
+				s.handBackWindow(msg.windowBytes)
+				return proto.Unmarshal(msg.data, m.(proto.Message))
+			}
+			if closed {
+				return closedErr(s.ctx.Err())
+			}
+			return s.ctx.Err()
+		case <-changed:
 		}
-		_, err := checkLastOrErr(nil)
-		return err
 	}
 }
 
@@ -136,8 +236,7 @@ func (s *webrtcBaseStream) closeRecv() {
 	if !s.recvClosed.CompareAndSwap(false, true) {
 		return
 	}
-	s.activeSenders.Wait()
-	close(s.msgCh)
+	s.msgs.close()
 }
 
 // Must be called with the `webrtcBaseStream.mu` mutex held.
@@ -171,23 +270,105 @@ func (s *webrtcBaseStream) closeWithError(err error, expected bool) {
 	s.onDone(s.stream.GetId())
 }
 
-func (s *webrtcBaseStream) processMessage(msg *webrtcpb.PacketMessage) ([]byte, bool) {
+// encodeMessage marshals m, compressing it if a compressor was negotiated and it is worth it.
+func (s *webrtcBaseStream) encodeMessage(m interface{}) ([]byte, bool, error) {
+	if v1Msg, ok := m.(protov1.Message); ok {
+		m = protov1.MessageV2(v1Msg)
+	}
+	data, err := proto.Marshal(m.(proto.Message))
+	if err != nil {
+		return nil, false, err
+	}
+	return compressMessage(s.sendCompressor, data)
+}
+
+// enqueueMessage makes a received message available to RecvMsg. Without flow control, it waits
+// while a message is already waiting to be read, which pushes back on the peer by blocking the
+// data channel. With flow control, it only waits once flowControlMaxQueuedMessages are.
+func (s *webrtcBaseStream) enqueueMessage(data []byte) {
+	limit := 1
+	if s.recvWindow != nil {
+		limit = flowControlMaxQueuedMessages
+	}
+	msg := queuedMessage{data: data, windowBytes: s.unreadBytes}
+	s.unreadBytes = 0
+	s.msgs.push(s.ctx, msg, limit)
+}
+
+// handBackWindow hands the given number of stream window bytes back to the peer once enough of
+// them have accumulated.
+func (s *webrtcBaseStream) handBackWindow(n int64) {
+	if n == 0 || s.recvWindow == nil || s.recvClosed.Load() {
+		return
+	}
+	if increment := s.recvWindow.consume(n); increment > 0 {
+		if err := s.writeWindowUpdate(s.stream, increment); err != nil {
+			s.logger.Debugw("failed to write stream window update", "error", err)
+		}
+	}
+}
+
+// waitForSendWindow waits until a packet with the given data length may be sent on this stream.
+// The caller's hold on mu, released by unlock and taken back by lock, is let go while waiting
+// since processing the headers, trailers, and closures that end the wait needs mu.
+func (s *webrtcBaseStream) waitForSendWindow(side string, dataLen int, unlock, lock func()) error {
+	if s.sendWindow == nil && s.connSendWindow == nil {
+		return nil
+	}
+	unlock()
+	defer lock()
+	return waitForSendWindows(s.ctx, side, s.sendWindow, s.connSendWindow, packetCost(dataLen))
+}
+
+// processMessage collects the packets of a message and returns the message once its last
+// packet arrives. An error means the stream should be reset.
+func (s *webrtcBaseStream) processMessage(msg *webrtcpb.PacketMessage) ([]byte, bool, error) {
+	if s.recvWindow != nil {
+		cost := packetCost(len(msg.GetData()))
+		if !s.recvWindow.receive(cost) || !s.connRecvWindow.receive(cost) {
+			return nil, false, errFlowControlWindowExceeded
+		}
+		// Like in HTTP/2 implementations, connection window bytes are handed back as soon as
+		// packets arrive so that streams abandoned with unread messages do not use them up.
+		if increment := s.connRecvWindow.consume(cost); increment > 0 {
+			if err := s.writeWindowUpdate(&webrtcpb.Stream{ChannelId: s.stream.GetChannelId()}, increment); err != nil {
+				s.logger.Debugw("failed to write connection window update", "error", err)
+			}
+		}
+		// Stream window bytes are held until the message they belong to is read. When nothing
+		// is waiting to be read, the reader is keeping up, so they are handed back right away;
+		// that also lets messages larger than the window through.
+		if s.msgs.empty() {
+			s.handBackWindow(cost)
+		} else {
+			s.unreadBytes += cost
+		}
+	}
 	if len(msg.GetData()) == 0 && msg.GetEom() {
-		return []byte{}, true
+		return []byte{}, true, nil
 	}
 	if len(msg.GetData())+s.packetBuf.Len() > MaxMessageSize {
 		s.packetBuf.Reset()
 		s.logger.Errorf("message size larger than max %d; discarding", MaxMessageSize)
-		return nil, false
+		return nil, false, nil
 	}
 	s.packetBuf.Write(msg.GetData())
 	if msg.GetEom() {
+		if msg.GetCompressed() {
+			data, err := decompressMessage(s.recvCompressor, s.packetBuf.Bytes())
+			s.packetBuf.Reset()
+			if err != nil {
+				// like gRPC, a message that cannot be decompressed ends the call.
+				return nil, false, status.Errorf(codes.Internal, "grpc: failed to decompress the received message: %v", err)
+			}
+			return data, true, nil
+		}
 		data := make([]byte, s.packetBuf.Len())
 		copy(data, s.packetBuf.Bytes())
 		s.packetBuf.Reset()
-		return data, true
+		return data, true, nil
 	}
-	return nil, false
+	return nil, false, nil
 }
 
 func metadataToProto(md metadata.MD) *webrtcpb.Metadata {
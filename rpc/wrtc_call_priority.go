@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/utils/perf/statz"
+	"go.viam.com/utils/perf/statz/units"
+)
+
+// CallPriorityMetadataField is the identifier of the priority class a caller asks for its call
+// to have (see ParseWebRTCCallPriority).
+const CallPriorityMetadataField = "rpc-call-priority"
+
+// A WebRTCCallPriority is the priority class of a call. Answerers are handed the waiting offers
+// of the highest class first and, within a class, in the order they arrived.
+type WebRTCCallPriority int
+
+// The priority classes of calls.
+const (
+	// WebRTCCallPriorityLow is for calls that can wait, such as dashboards reconnecting in bulk.
+	WebRTCCallPriorityLow WebRTCCallPriority = iota - 1
+	// WebRTCCallPriorityNormal is the priority class of calls by default.
+	WebRTCCallPriorityNormal
+	// WebRTCCallPriorityHigh is for calls that should not wait behind others, such as operator
+	// sessions.
+	WebRTCCallPriorityHigh
+)
+
+var webrtcCallPriorities = []WebRTCCallPriority{WebRTCCallPriorityLow, WebRTCCallPriorityNormal, WebRTCCallPriorityHigh}
+
+// String returns the name of the priority class.
+func (p WebRTCCallPriority) String() string {
+	switch p {
+	case WebRTCCallPriorityLow:
+		return "low"
+	case WebRTCCallPriorityNormal:
+		return "normal"
+	case WebRTCCallPriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseWebRTCCallPriority returns the priority class of the given name ("low", "normal" or "high").
+func ParseWebRTCCallPriority(name string) (WebRTCCallPriority, error) {
+	for _, p := range webrtcCallPriorities {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return WebRTCCallPriorityNormal, errors.Errorf("unknown call priority %q", name)
+}
+
+// clampWebRTCCallPriority returns the nearest known priority class to p.
+func clampWebRTCCallPriority(p WebRTCCallPriority) WebRTCCallPriority {
+	return min(max(p, WebRTCCallPriorityLow), WebRTCCallPriorityHigh)
+}
+
+// WebRTCCallAdmissionOptions configure how a WebRTCSignalingServer admits calls.
+type WebRTCCallAdmissionOptions struct {
+	// Priority returns the priority class of a call from the context it was made with, which
+	// carries the auth entity (see ContextAuthEntity) and metadata of the caller. When unset,
+	// callers may ask for a class in the rpc-call-priority metadata field but are given
+	// WebRTCCallPriorityNormal at most, since they are not trusted to outrank others.
+	Priority func(ctx context.Context) WebRTCCallPriority
+
+	// MaxCallsPerHost caps, by priority class, how many calls to a single host may be in
+	// progress on the signaling server at once. Calls over the cap are refused right away.
+	// Classes without a cap are unlimited.
+	MaxCallsPerHost map[WebRTCCallPriority]int
+}
+
+// WebRTCCallStats are stats of the calls a WebRTCSignalingServer is handling.
+type WebRTCCallStats struct {
+	// Waiting is how many calls to each host are waiting for an answer, by priority class.
+	Waiting map[string]map[WebRTCCallPriority]int
+
+	// InProgress is how many calls to each host are in progress, by priority class.
+	InProgress map[string]map[WebRTCCallPriority]int
+
+	// Rejected is how many calls were refused for exceeding a per host cap, by priority class.
+	Rejected map[WebRTCCallPriority]int64
+}
+
+var (
+	callQueueDepth = statz.NewGauge2[string, string]("signaling/call_queue_depth", statz.MetricConfig{
+		Description: "The number of calls waiting for an answer.",
+		Unit:        units.Dimensionless,
+		Labels: []statz.Label{
+			{Name: "hostname", Description: "The robot being requested"},
+			{Name: "priority", Description: "The priority class of the calls ('low', 'normal' or 'high')."},
+		},
+	})
+
+	callHostCapExceeded = statz.NewCounter2[string, string]("signaling/call_host_cap_exceeded", statz.MetricConfig{
+		Description: "The number of calls refused for exceeding the per host cap of their priority class.",
+		Unit:        units.Dimensionless,
+		Labels: []statz.Label{
+			{Name: "hostname", Description: "The robot being requested"},
+			{Name: "priority", Description: "The priority class of the call ('low', 'normal' or 'high')."},
+		},
+	})
+)
+
+// webrtcCallAdmission keeps track of the calls a signaling server is handling in order to
+// enforce per host caps and report queue depths.
+type webrtcCallAdmission struct {
+	mu         sync.Mutex
+	opts       WebRTCCallAdmissionOptions
+	waiting    map[string]map[WebRTCCallPriority]int
+	inProgress map[string]map[WebRTCCallPriority]int
+	rejected   map[WebRTCCallPriority]int64
+}
+
+func newWebRTCCallAdmission() *webrtcCallAdmission {
+	return &webrtcCallAdmission{
+		waiting:    map[string]map[WebRTCCallPriority]int{},
+		inProgress: map[string]map[WebRTCCallPriority]int{},
+		rejected:   map[WebRTCCallPriority]int64{},
+	}
+}
+
+func (a *webrtcCallAdmission) setOptions(opts WebRTCCallAdmissionOptions) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.opts = opts
+}
+
+// priority returns the priority class of a call made with the given context.
+func (a *webrtcCallAdmission) priority(ctx context.Context) WebRTCCallPriority {
+	a.mu.Lock()
+	priorityFunc := a.opts.Priority
+	a.mu.Unlock()
+	if priorityFunc != nil {
+		return clampWebRTCCallPriority(priorityFunc(ctx))
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[CallPriorityMetadataField]) == 0 {
+		return WebRTCCallPriorityNormal
+	}
+	requested, err := ParseWebRTCCallPriority(md[CallPriorityMetadataField][0])
+	if err != nil {
+		return WebRTCCallPriorityNormal
+	}
+	return min(requested, WebRTCCallPriorityNormal)
+}
+
+// admit counts a call to the host against the cap of its class. It returns how to mark the
+// call answered, which may be called more than once, and how to release it once done.
+func (a *webrtcCallAdmission) admit(host string, priority WebRTCCallPriority) (func(), func(), error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if maxCalls, ok := a.opts.MaxCallsPerHost[priority]; ok && a.inProgress[host][priority] >= maxCalls {
+		a.rejected[priority]++
+		callHostCapExceeded.Inc(host, priority.String())
+		return nil, nil, errTooManyConns
+	}
+	adjustWebRTCCallCount(a.inProgress, host, priority, 1)
+	a.adjustWaiting(host, priority, 1)
+
+	var answerOnce sync.Once
+	answered := func() {
+		answerOnce.Do(func() {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			a.adjustWaiting(host, priority, -1)
+		})
+	}
+	release := func() {
+		answered()
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		adjustWebRTCCallCount(a.inProgress, host, priority, -1)
+	}
+	return answered, release, nil
+}
+
+func (a *webrtcCallAdmission) adjustWaiting(host string, priority WebRTCCallPriority, by int) {
+	callQueueDepth.Set(host, priority.String(), int64(adjustWebRTCCallCount(a.waiting, host, priority, by)))
+}
+
+func (a *webrtcCallAdmission) stats() WebRTCCallStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := WebRTCCallStats{
+		Waiting:    make(map[string]map[WebRTCCallPriority]int, len(a.waiting)),
+		InProgress: make(map[string]map[WebRTCCallPriority]int, len(a.inProgress)),
+		Rejected:   make(map[WebRTCCallPriority]int64, len(a.rejected)),
+	}
+	for host, counts := range a.waiting {
+		stats.Waiting[host] = copyWebRTCCallCounts(counts)
+	}
+	for host, counts := range a.inProgress {
+		stats.InProgress[host] = copyWebRTCCallCounts(counts)
+	}
+	for priority, count := range a.rejected {
+		stats.Rejected[priority] = count
+	}
+	return stats
+}
+
+// adjustWebRTCCallCount adjusts the count of calls to the host of the given class, forgetting
+// it once there are none, and returns the new count.
+func adjustWebRTCCallCount(counts map[string]map[WebRTCCallPriority]int, host string, priority WebRTCCallPriority, by int) int {
+	hostCounts, ok := counts[host]
+	if !ok {
+		hostCounts = map[WebRTCCallPriority]int{}
+		counts[host] = hostCounts
+	}
+	hostCounts[priority] += by
+	count := hostCounts[priority]
+	if count <= 0 {
+		delete(hostCounts, priority)
+	}
+	if len(hostCounts) == 0 {
+		delete(counts, host)
+	}
+	return count
+}
+
+func copyWebRTCCallCounts(counts map[WebRTCCallPriority]int) map[WebRTCCallPriority]int {
+	copied := make(map[WebRTCCallPriority]int, len(counts))
+	for priority, count := range counts {
+		copied[priority] = count
+	}
+	return copied
+}
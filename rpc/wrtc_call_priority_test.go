@@ -0,0 +1,154 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils/testutils"
+)
+
+func TestParseWebRTCCallPriority(t *testing.T) {
+	for _, priority := range webrtcCallPriorities {
+		parsed, err := ParseWebRTCCallPriority(priority.String())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, parsed, test.ShouldEqual, priority)
+	}
+	_, err := ParseWebRTCCallPriority("urgent")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown")
+}
+
+func TestWebRTCCallAdmissionPriority(t *testing.T) {
+	withPriority := func(name string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(CallPriorityMetadataField, name))
+	}
+
+	admission := newWebRTCCallAdmission()
+	test.That(t, admission.priority(context.Background()), test.ShouldEqual, WebRTCCallPriorityNormal)
+	test.That(t, admission.priority(withPriority("low")), test.ShouldEqual, WebRTCCallPriorityLow)
+	test.That(t, admission.priority(withPriority("bogus")), test.ShouldEqual, WebRTCCallPriorityNormal)
+	// callers are not trusted to raise their own priority.
+	test.That(t, admission.priority(withPriority("high")), test.ShouldEqual, WebRTCCallPriorityNormal)
+
+	admission.setOptions(WebRTCCallAdmissionOptions{
+		Priority: func(ctx context.Context) WebRTCCallPriority {
+			if _, ok := metadata.FromIncomingContext(ctx); ok {
+				return WebRTCCallPriorityHigh + 5
+			}
+			return WebRTCCallPriorityLow
+		},
+	})
+	test.That(t, admission.priority(context.Background()), test.ShouldEqual, WebRTCCallPriorityLow)
+	test.That(t, admission.priority(withPriority("low")), test.ShouldEqual, WebRTCCallPriorityHigh)
+}
+
+func TestWebRTCCallAdmissionCaps(t *testing.T) {
+	admission := newWebRTCCallAdmission()
+	admission.setOptions(WebRTCCallAdmissionOptions{
+		MaxCallsPerHost: map[WebRTCCallPriority]int{WebRTCCallPriorityLow: 1},
+	})
+
+	answered1, release1, err := admission.admit("host1", WebRTCCallPriorityLow)
+	test.That(t, err, test.ShouldBeNil)
+	_, _, err = admission.admit("host1", WebRTCCallPriorityLow)
+	test.That(t, err, test.ShouldEqual, errTooManyConns)
+
+	// the cap is per host and per priority class.
+	_, release2, err := admission.admit("host2", WebRTCCallPriorityLow)
+	test.That(t, err, test.ShouldBeNil)
+	_, release3, err := admission.admit("host1", WebRTCCallPriorityNormal)
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, admission.stats(), test.ShouldResemble, WebRTCCallStats{
+		Waiting: map[string]map[WebRTCCallPriority]int{
+			"host1": {WebRTCCallPriorityLow: 1, WebRTCCallPriorityNormal: 1},
+			"host2": {WebRTCCallPriorityLow: 1},
+		},
+		InProgress: map[string]map[WebRTCCallPriority]int{
+			"host1": {WebRTCCallPriorityLow: 1, WebRTCCallPriorityNormal: 1},
+			"host2": {WebRTCCallPriorityLow: 1},
+		},
+		Rejected: map[WebRTCCallPriority]int64{WebRTCCallPriorityLow: 1},
+	})
+
+	answered1()
+	answered1()
+	stats := admission.stats()
+	test.That(t, stats.Waiting["host1"], test.ShouldResemble, map[WebRTCCallPriority]int{WebRTCCallPriorityNormal: 1})
+	test.That(t, stats.InProgress["host1"], test.ShouldResemble,
+		map[WebRTCCallPriority]int{WebRTCCallPriorityLow: 1, WebRTCCallPriorityNormal: 1})
+
+	release1()
+	_, release4, err := admission.admit("host1", WebRTCCallPriorityLow)
+	test.That(t, err, test.ShouldBeNil)
+
+	release2()
+	release3()
+	release4()
+	test.That(t, admission.stats(), test.ShouldResemble, WebRTCCallStats{
+		Waiting:    map[string]map[WebRTCCallPriority]int{},
+		InProgress: map[string]map[WebRTCCallPriority]int{},
+		Rejected:   map[WebRTCCallPriority]int64{WebRTCCallPriorityLow: 1},
+	})
+}
+
+func TestWebRTCCallAdmissionServer(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+			CallAdmission: WebRTCCallAdmissionOptions{
+				MaxCallsPerHost: map[WebRTCCallPriority]int{WebRTCCallPriorityLow: 0},
+			},
+		}),
+		WithUnauthenticated(),
+		WithDisableMulticastDNS(),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	_, err = DialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, logger,
+		WithInsecure(),
+		WithWebRTCOptions(DialWebRTCOptions{
+			SignalingInsecure: true,
+			CallPriority:      WebRTCCallPriorityLow,
+		}),
+	)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+
+	rtcConn, err := DialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, logger,
+		WithInsecure(),
+		WithWebRTCOptions(DialWebRTCOptions{
+			SignalingInsecure: true,
+		}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rtcConn.Close(), test.ShouldBeNil)
+
+	ss, ok := rpcServer.(*simpleServer)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, ss.signalingServer.CallStats().Rejected, test.ShouldResemble,
+		map[WebRTCCallPriority]int64{WebRTCCallPriorityLow: 1})
+}
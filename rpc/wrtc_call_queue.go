@@ -6,6 +6,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/viamrobotics/webrtc/v3"
 )
 
@@ -37,13 +38,23 @@ func setDefaultOfferDeadline(deafultOfferDeadline time.Duration) func() {
 	}
 }
 
+// DefaultWebRTCCallOfferDeadline returns how long a call offer has to live before it expires.
+// WebRTCCallQueue implementations should not hand out offers past this deadline.
+func DefaultWebRTCCallOfferDeadline() time.Duration {
+	return getDefaultOfferDeadline()
+}
+
 // A WebRTCCallQueue handles the transmission and reception of call offers. For every
 // sending of an offer done, it is expected that there is someone to receive that
 // offer and subsequently respond to it.
+//
+// Implementations can verify they behave like the ones in this package with the
+// go.viam.com/utils/rpc/callqueuetest package.
 type WebRTCCallQueue interface {
 	// SendOfferInit initializes an offer associated with the given SDP to the given host.
 	// It returns a UUID to track/authenticate the offer over time, a channel receive offer updates
-	// on over time, and a cancel func to inform the sender to stop.
+	// on over time, and a cancel func to inform the sender to stop. The offer has the priority
+	// class of the context (see ContextWebRTCCallPriority).
 	SendOfferInit(ctx context.Context, host, sdp string, disableTrickle bool) (
 		uuid string, respCh <-chan WebRTCCallAnswer, respDone <-chan struct{}, cancel func(), err error)
 
@@ -59,14 +70,69 @@ type WebRTCCallQueue interface {
 	// an error.
 	SendOfferError(ctx context.Context, host, uuid string, err error) error
 
-	// RecvOffer receives the next offer for the given hosts. It should respond with an answer
-	// once a decision is made.
+	// RecvOffer receives the next offer for the given hosts. Offers of a higher priority class
+	// are received first and, within a class, in the order they were sent. It should respond
+	// with an answer once a decision is made.
 	RecvOffer(ctx context.Context, hosts []string) (WebRTCCallOfferExchange, error)
 
 	// Close shuts down the queue.
 	Close() error
 }
 
+// A WebRTCHostDirectory is a WebRTCCallQueue that knows which hosts have answerers connected.
+// The signaling server serves ListOnlineHosts and GetHostStatus with it.
+type WebRTCHostDirectory interface {
+	// HostStatuses returns the status of each of the given hosts, or of every host with
+	// answerers connected if none are given.
+	HostStatuses(ctx context.Context, hosts ...string) ([]WebRTCHostStatus, error)
+}
+
+// WebRTCHostStatus is the presence of a host.
+type WebRTCHostStatus struct {
+	Host string
+
+	// Answerers is how many answerers are connected for the host.
+	Answerers int
+
+	// LastHeartbeat is the last time an answerer for the host was known to be reachable. It is
+	// zero if none has been seen.
+	LastHeartbeat time.Time
+
+	// AnswererVersion identifies the client software of the answerer seen most recently.
+	AnswererVersion string
+}
+
+// A WebRTCAnswererPresence describes an answerer waiting for calls. It is attached to the context
+// passed to RecvOffer (see ContextWebRTCAnswererPresence) so that a WebRTCHostDirectory can
+// report on it.
+type WebRTCAnswererPresence struct {
+	version       string
+	lastHeartbeat atomic.Int64
+}
+
+// NewWebRTCAnswererPresence returns the presence of an answerer running the given version that
+// was just heard from.
+func NewWebRTCAnswererPresence(version string) *WebRTCAnswererPresence {
+	presence := &WebRTCAnswererPresence{version: version}
+	presence.Heartbeat()
+	return presence
+}
+
+// Version identifies the client software of the answerer.
+func (p *WebRTCAnswererPresence) Version() string {
+	return p.version
+}
+
+// Heartbeat records that the answerer is reachable.
+func (p *WebRTCAnswererPresence) Heartbeat() {
+	p.lastHeartbeat.Store(time.Now().UnixNano())
+}
+
+// LastHeartbeat returns the last time the answerer was known to be reachable.
+func (p *WebRTCAnswererPresence) LastHeartbeat() time.Time {
+	return time.Unix(0, p.lastHeartbeat.Load())
+}
+
 // WebRTCCallOffer contains the information needed to offer to start a call.
 type WebRTCCallOffer interface {
 	// The UUID uniquely identifies this offer.
@@ -85,6 +151,17 @@ type WebRTCCallOffer interface {
 	Deadline() time.Time
 }
 
+// A WebRTCTracedCallOffer is a WebRTCCallOffer that knows the trace its caller is part of. Queues
+// capture it from the OpenTelemetry span in the context passed to SendOfferInit so that the
+// signaling server can hand it to the answerer along with the offer.
+type WebRTCTracedCallOffer interface {
+	WebRTCCallOffer
+
+	// TraceContext returns the W3C trace context (traceparent and tracestate) of the caller,
+	// or nil if it was not traced.
+	TraceContext() map[string]string
+}
+
 // A WebRTCCallOfferExchange is used by an answerer to respond to a call offer with an
 // answer.
 type WebRTCCallOfferExchange interface {
@@ -118,6 +195,16 @@ type WebRTCCallAnswer struct {
 
 const noActiveOfferStr = "no active offer"
 
+// ErrInactiveOffer is wrapped by errors returned from a WebRTCCallQueue when an offer
+// being updated is no longer active (or never was).
+var ErrInactiveOffer = errors.New(noActiveOfferStr)
+
+// NewInactiveOfferError returns an error wrapping ErrInactiveOffer for the offer
+// associated with the given UUID.
+func NewInactiveOfferError(uuid string) error {
+	return newInactiveOfferErr(uuid)
+}
+
 func newInactiveOfferErr(uuid string) inactiveOfferError {
 	return inactiveOfferError{uuid}
 }
@@ -129,3 +216,7 @@ type inactiveOfferError struct {
 func (e inactiveOfferError) Error() string {
 	return fmt.Sprintf("%s for %q", noActiveOfferStr, e.uuid)
 }
+
+func (e inactiveOfferError) Unwrap() error {
+	return ErrInactiveOffer
+}
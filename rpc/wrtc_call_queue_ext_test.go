@@ -0,0 +1,128 @@
+package rpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/google/uuid"
+	"go.viam.com/test"
+
+	"go.viam.com/utils/rpc"
+	"go.viam.com/utils/rpc/callqueuetest"
+	"go.viam.com/utils/testutils"
+)
+
+func TestMemoryWebRTCCallQueue(t *testing.T) {
+	callqueuetest.RunWebRTCCallQueueSuite(t, func(t *testing.T, offerDeadline time.Duration) callqueuetest.Setup {
+		t.Helper()
+		t.Cleanup(rpc.SetDefaultOfferDeadline(offerDeadline))
+		logger := golog.NewTestLogger(t)
+		callQueue := rpc.NewMemoryWebRTCCallQueue(logger)
+		return callqueuetest.Setup{
+			CallerQueue:   callQueue,
+			AnswererQueue: callQueue,
+			Teardown: func() {
+				test.That(t, callQueue.Close(), test.ShouldBeNil)
+			},
+		}
+	})
+}
+
+func TestMongoDBWebRTCCallQueue(t *testing.T) {
+	client := testutils.BackingMongoDBClient(t)
+	dropDB := func(t *testing.T) {
+		t.Helper()
+		test.That(t, client.Database(*rpc.MongoDBWebRTCCallQueueDBName).Drop(context.Background()), test.ShouldBeNil)
+	}
+
+	t.Run("single", func(t *testing.T) {
+		callqueuetest.RunWebRTCCallQueueSuite(t, func(t *testing.T, offerDeadline time.Duration) callqueuetest.Setup {
+			t.Helper()
+			t.Cleanup(rpc.SetDefaultOfferDeadline(offerDeadline))
+			dropDB(t)
+			logger := golog.NewTestLogger(t)
+			callQueue, err := rpc.NewMongoDBWebRTCCallQueue(context.Background(), uuid.NewString(), 50, client, logger,
+				func(hosts []string, atTime time.Time) {}, nil)
+			test.That(t, err, test.ShouldBeNil)
+			return callqueuetest.Setup{
+				CallerQueue:           callQueue,
+				AnswererQueue:         callQueue,
+				WaitForAnswererOnline: rpc.WaitForAnswererOnlineFunc(callQueue),
+				Teardown: func() {
+					test.That(t, callQueue.Close(), test.ShouldBeNil)
+				},
+			}
+		})
+	})
+
+	t.Run("multi", func(t *testing.T) {
+		callqueuetest.RunWebRTCCallQueueSuite(t, func(t *testing.T, offerDeadline time.Duration) callqueuetest.Setup {
+			t.Helper()
+			t.Cleanup(rpc.SetDefaultOfferDeadline(offerDeadline))
+			dropDB(t)
+			logger := golog.NewTestLogger(t)
+			callerQueue, err := rpc.NewMongoDBWebRTCCallQueue(context.Background(), uuid.NewString()+"-caller", 50, client, logger,
+				func(hosts []string, atTime time.Time) {}, nil)
+			test.That(t, err, test.ShouldBeNil)
+			answererQueue, err := rpc.NewMongoDBWebRTCCallQueue(context.Background(), uuid.NewString()+"-answerer", 50, client, logger,
+				func(hosts []string, atTime time.Time) {}, nil)
+			test.That(t, err, test.ShouldBeNil)
+			return callqueuetest.Setup{
+				CallerQueue:           callerQueue,
+				AnswererQueue:         answererQueue,
+				WaitForAnswererOnline: rpc.WaitForAnswererOnlineFunc(answererQueue),
+				Teardown: func() {
+					test.That(t, callerQueue.Close(), test.ShouldBeNil)
+					test.That(t, answererQueue.Close(), test.ShouldBeNil)
+				},
+			}
+		})
+	})
+}
+
+func TestRedisWebRTCCallQueue(t *testing.T) {
+	client := testutils.BackingRedisClient(t)
+
+	t.Run("single", func(t *testing.T) {
+		callqueuetest.RunWebRTCCallQueueSuite(t, func(t *testing.T, offerDeadline time.Duration) callqueuetest.Setup {
+			t.Helper()
+			t.Cleanup(rpc.SetDefaultOfferDeadline(offerDeadline))
+			test.That(t, client.FlushDB(context.Background()).Err(), test.ShouldBeNil)
+			logger := golog.NewTestLogger(t)
+			callQueue, err := rpc.NewRedisWebRTCCallQueue(context.Background(), uuid.NewString(), 50, client, logger)
+			test.That(t, err, test.ShouldBeNil)
+			return callqueuetest.Setup{
+				CallerQueue:           callQueue,
+				AnswererQueue:         callQueue,
+				WaitForAnswererOnline: rpc.WaitForAnswererOnlineFunc(callQueue),
+				Teardown: func() {
+					test.That(t, callQueue.Close(), test.ShouldBeNil)
+				},
+			}
+		})
+	})
+
+	t.Run("multi", func(t *testing.T) {
+		callqueuetest.RunWebRTCCallQueueSuite(t, func(t *testing.T, offerDeadline time.Duration) callqueuetest.Setup {
+			t.Helper()
+			t.Cleanup(rpc.SetDefaultOfferDeadline(offerDeadline))
+			test.That(t, client.FlushDB(context.Background()).Err(), test.ShouldBeNil)
+			logger := golog.NewTestLogger(t)
+			callerQueue, err := rpc.NewRedisWebRTCCallQueue(context.Background(), uuid.NewString()+"-caller", 50, client, logger)
+			test.That(t, err, test.ShouldBeNil)
+			answererQueue, err := rpc.NewRedisWebRTCCallQueue(context.Background(), uuid.NewString()+"-answerer", 50, client, logger)
+			test.That(t, err, test.ShouldBeNil)
+			return callqueuetest.Setup{
+				CallerQueue:           callerQueue,
+				AnswererQueue:         answererQueue,
+				WaitForAnswererOnline: rpc.WaitForAnswererOnlineFunc(answererQueue),
+				Teardown: func() {
+					test.That(t, callerQueue.Close(), test.ShouldBeNil)
+					test.That(t, answererQueue.Close(), test.ShouldBeNil)
+				},
+			}
+		})
+	})
+}
@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,6 +21,7 @@ type memoryWebRTCCallQueue struct {
 	mu                      sync.Mutex
 	activeBackgroundWorkers *utils.StoppableWorkers
 	hostQueues              map[string]*singleWebRTCHostQueue
+	hostPresences           map[string]*memoryHostPresence
 
 	uuidDeterministic        bool
 	uuidDeterministicCounter int64
@@ -40,6 +42,7 @@ func newMemoryWebRTCCallQueueTest(logger utils.ZapCompatibleLogger) *memoryWebRT
 func newMemoryWebRTCCallQueue(uuidDeterministic bool, logger utils.ZapCompatibleLogger) *memoryWebRTCCallQueue {
 	queue := &memoryWebRTCCallQueue{
 		hostQueues:        map[string]*singleWebRTCHostQueue{},
+		hostPresences:     map[string]*memoryHostPresence{},
 		uuidDeterministic: uuidDeterministic,
 		logger:            logger,
 	}
@@ -67,6 +70,8 @@ type memoryWebRTCCallOfferInit struct {
 	sdp                string
 	disableTrickle     bool
 	deadline           time.Time
+	priority           WebRTCCallPriority
+	traceContext       map[string]string
 	callerCandidates   chan webrtc.ICECandidateInit
 	answererResponses  chan<- WebRTCCallAnswer
 	answererDoneCtx    context.Context
@@ -98,6 +103,8 @@ func (queue *memoryWebRTCCallQueue) SendOfferInit(
 		sdp:                sdp,
 		disableTrickle:     disableTrickle,
 		deadline:           offerDeadline,
+		priority:           ContextWebRTCCallPriority(ctx),
+		traceContext:       callTraceContext(ctx),
 		callerCandidates:   make(chan webrtc.ICECandidateInit),
 		answererResponses:  answererResponses,
 		answererDoneCtx:    sendCtx,
@@ -112,14 +119,18 @@ func (queue *memoryWebRTCCallQueue) SendOfferInit(
 		callerDoneCancel: callerDoneCancel,
 	}
 	hostQueueForSend.activeOffers[offer.uuid] = exchange
+	hostQueueForSend.pending = append(hostQueueForSend.pending, exchange)
+	close(hostQueueForSend.pendingAdded)
+	hostQueueForSend.pendingAdded = make(chan struct{})
 	hostQueueForSend.mu.Unlock()
 
 	queue.activeBackgroundWorkers.Add(func(_ context.Context) {
 		select {
 		case <-sendCtx.Done():
 		case <-ctx.Done():
-		case hostQueueForSend.exchangeCh <- exchange:
 		}
+		// in case no answerer took it.
+		hostQueueForSend.removePending(exchange)
 	})
 	return newUUID, answererResponses, sendCtx.Done(), func() { sendCtxCancel() }, nil
 }
@@ -190,16 +201,100 @@ func (queue *memoryWebRTCCallQueue) SendOfferError(ctx context.Context, host, uu
 func (queue *memoryWebRTCCallQueue) RecvOffer(ctx context.Context, hosts []string) (WebRTCCallOfferExchange, error) {
 	hostQueue := queue.getOrMakeHostsQueue(hosts)
 
+	presence, ok := ContextWebRTCAnswererPresence(ctx)
+	if !ok {
+		presence = NewWebRTCAnswererPresence("")
+	}
+	queue.addAnswerer(hosts, presence)
+
 	recvCtx, recvCtxCancel := context.WithCancel(queue.activeBackgroundWorkers.Context())
 	defer recvCtxCancel()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-recvCtx.Done():
-		return nil, recvCtx.Err()
-	case exchange := <-hostQueue.exchangeCh:
-		return exchange, nil
+	for {
+		exchange, pendingAdded := hostQueue.takePending()
+		if exchange != nil {
+			// the answerer stays connected until the exchange is done or expires.
+			queue.activeBackgroundWorkers.Add(func(_ context.Context) {
+				<-exchange.offer.answererDoneCtx.Done()
+				queue.removeAnswerer(hosts, presence)
+			})
+			return exchange, nil
+		}
+		select {
+		case <-ctx.Done():
+			queue.removeAnswerer(hosts, presence)
+			return nil, ctx.Err()
+		case <-recvCtx.Done():
+			queue.removeAnswerer(hosts, presence)
+			return nil, recvCtx.Err()
+		case <-pendingAdded:
+		}
+	}
+}
+
+// HostStatuses returns the status of each of the given hosts, or of every host with answerers
+// connected if none are given. Hosts are remembered after their answerers leave.
+func (queue *memoryWebRTCCallQueue) HostStatuses(ctx context.Context, hosts ...string) ([]WebRTCHostStatus, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if len(hosts) == 0 {
+		for host, hostPresence := range queue.hostPresences {
+			if len(hostPresence.answerers) > 0 {
+				hosts = append(hosts, host)
+			}
+		}
+		slices.Sort(hosts)
+	}
+	statuses := make([]WebRTCHostStatus, 0, len(hosts))
+	for _, host := range hosts {
+		status := WebRTCHostStatus{Host: host}
+		if hostPresence, ok := queue.hostPresences[host]; ok {
+			status.Answerers = len(hostPresence.answerers)
+			status.LastHeartbeat = hostPresence.lastHeartbeat
+			status.AnswererVersion = hostPresence.version
+			for presence := range hostPresence.answerers {
+				if lastHeartbeat := presence.LastHeartbeat(); lastHeartbeat.After(status.LastHeartbeat) {
+					status.LastHeartbeat = lastHeartbeat
+					status.AnswererVersion = presence.Version()
+				}
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// memoryHostPresence is what a memoryWebRTCCallQueue knows about the answerers of a host.
+type memoryHostPresence struct {
+	answerers map[*WebRTCAnswererPresence]struct{}
+	// lastHeartbeat and version are of the answerer that left having been heard from last.
+	lastHeartbeat time.Time
+	version       string
+}
+
+func (queue *memoryWebRTCCallQueue) addAnswerer(hosts []string, presence *WebRTCAnswererPresence) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	for _, host := range hosts {
+		hostPresence, ok := queue.hostPresences[host]
+		if !ok {
+			hostPresence = &memoryHostPresence{answerers: map[*WebRTCAnswererPresence]struct{}{}}
+			queue.hostPresences[host] = hostPresence
+		}
+		hostPresence.answerers[presence] = struct{}{}
+	}
+}
+
+func (queue *memoryWebRTCCallQueue) removeAnswerer(hosts []string, presence *WebRTCAnswererPresence) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	for _, host := range hosts {
+		hostPresence := queue.hostPresences[host]
+		delete(hostPresence.answerers, presence)
+		if lastHeartbeat := presence.LastHeartbeat(); lastHeartbeat.After(hostPresence.lastHeartbeat) {
+			hostPresence.lastHeartbeat = lastHeartbeat
+			hostPresence.version = presence.Version()
+		}
 	}
 }
 
@@ -233,6 +328,10 @@ func (resp *memoryWebRTCCallOfferExchange) Deadline() time.Time {
 	return resp.offer.deadline
 }
 
+func (resp *memoryWebRTCCallOfferExchange) TraceContext() map[string]string {
+	return resp.offer.traceContext
+}
+
 func (resp *memoryWebRTCCallOfferExchange) CallerCandidates() <-chan webrtc.ICECandidateInit {
 	return resp.offer.callerCandidates
 }
@@ -273,11 +372,44 @@ func (resp *memoryWebRTCCallOfferExchange) AnswererDone(ctx context.Context) err
 }
 
 type singleWebRTCHostQueue struct {
-	mu           sync.RWMutex
-	exchangeCh   chan *memoryWebRTCCallOfferExchange
+	mu sync.RWMutex
+	// pending are the offers no answerer has taken yet, in the order they were sent.
+	pending []*memoryWebRTCCallOfferExchange
+	// pendingAdded is closed and replaced whenever an offer is added to pending.
+	pendingAdded chan struct{}
 	activeOffers map[string]*memoryWebRTCCallOfferExchange
 }
 
+// takePending takes the pending offer of the highest priority class that was sent first. If
+// there is none, it returns a channel that is closed once there may be.
+func (hostQueue *singleWebRTCHostQueue) takePending() (*memoryWebRTCCallOfferExchange, <-chan struct{}) {
+	hostQueue.mu.Lock()
+	defer hostQueue.mu.Unlock()
+	hostQueue.pending = slices.DeleteFunc(hostQueue.pending, func(exchange *memoryWebRTCCallOfferExchange) bool {
+		return exchange.offer.answererDoneCtx.Err() != nil
+	})
+	next := -1
+	for idx, exchange := range hostQueue.pending {
+		if next == -1 || exchange.offer.priority > hostQueue.pending[next].offer.priority {
+			next = idx
+		}
+	}
+	if next == -1 {
+		return nil, hostQueue.pendingAdded
+	}
+	exchange := hostQueue.pending[next]
+	hostQueue.pending = slices.Delete(hostQueue.pending, next, next+1)
+	return exchange, nil
+}
+
+func (hostQueue *singleWebRTCHostQueue) removePending(exchange *memoryWebRTCCallOfferExchange) {
+	hostQueue.mu.Lock()
+	defer hostQueue.mu.Unlock()
+	if idx := slices.Index(hostQueue.pending, exchange); idx != -1 {
+		hostQueue.pending = slices.Delete(hostQueue.pending, idx, idx+1)
+	}
+}
+
 func (queue *memoryWebRTCCallQueue) getOrMakeHostsQueue(hosts []string) *singleWebRTCHostQueue {
 	queue.mu.Lock()
 	defer queue.mu.Unlock()
@@ -293,7 +425,7 @@ func (queue *memoryWebRTCCallQueue) getOrMakeHostsQueue(hosts []string) *singleW
 	}
 	if sharedHostQueue == nil {
 		sharedHostQueue = &singleWebRTCHostQueue{
-			exchangeCh:   make(chan *memoryWebRTCCallOfferExchange),
+			pendingAdded: make(chan struct{}),
 			activeOffers: make(map[string]*memoryWebRTCCallOfferExchange),
 		}
 	}
@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,6 +31,7 @@ import (
 func init() {
 	mongoutils.MustRegisterNamespace(&mongodbWebRTCCallQueueDBName, &mongodbWebRTCCallQueueCallsCollName)
 	mongoutils.MustRegisterNamespace(&mongodbWebRTCCallQueueDBName, &mongodbWebRTCCallQueueOperatorsCollName)
+	mongoutils.MustRegisterNamespace(&mongodbWebRTCCallQueueDBName, &mongodbWebRTCCallQueueHostsCollName)
 }
 
 var (
@@ -183,8 +185,13 @@ type mongoDBWebRTCCallQueue struct {
 	activeBackgroundWorkers            sync.WaitGroup
 	callsColl                          *mongo.Collection
 	operatorsColl                      *mongo.Collection
+	hostsColl                          *mongo.Collection
 	logger                             utils.ZapCompatibleLogger
 
+	// persistedHeartbeats are the last heartbeats of each host written to the hosts collection. It
+	// is only used by the operatorLivenessLoop.
+	persistedHeartbeats map[string]time.Time
+
 	cancelCtx  context.Context
 	cancelFunc func()
 
@@ -226,6 +233,7 @@ var (
 	mongodbWebRTCCallQueueDBName             = "rpc"
 	mongodbWebRTCCallQueueCallsCollName      = "calls"
 	mongodbWebRTCCallQueueOperatorsCollName  = "operators"
+	mongodbWebRTCCallQueueHostsCollName      = "hosts"
 	mongodbWebRTCCallQueueRPCCallExpireName  = "rpc_call_expire"
 	mongodbWebRTCCallQueueOperatorExpireName = "operator_expire"
 )
@@ -262,6 +270,7 @@ func NewMongoDBWebRTCCallQueue(
 	}
 	callsColl := client.Database(mongodbWebRTCCallQueueDBName).Collection(mongodbWebRTCCallQueueCallsCollName)
 	operatorsColl := client.Database(mongodbWebRTCCallQueueDBName).Collection(mongodbWebRTCCallQueueOperatorsCollName)
+	hostsColl := client.Database(mongodbWebRTCCallQueueDBName).Collection(mongodbWebRTCCallQueueHostsCollName)
 
 	mongodbWebRTCCallQueueExpireAfter := int32(getDefaultOfferDeadline().Seconds())
 	mongodbWebRTCCallQueueCallsIndexes := []mongo.IndexModel{
@@ -335,11 +344,13 @@ func NewMongoDBWebRTCCallQueue(
 			{"answerer_size", bson.D{{"$gt", 0}}},
 		}}},
 
-		callsColl:     callsColl,
-		operatorsColl: operatorsColl,
-		cancelCtx:     cancelCtx,
-		cancelFunc:    cancelFunc,
-		logger:        utils.AddFieldsToLogger(logger, "operator_id", operatorID),
+		callsColl:           callsColl,
+		operatorsColl:       operatorsColl,
+		hostsColl:           hostsColl,
+		persistedHeartbeats: map[string]time.Time{},
+		cancelCtx:           cancelCtx,
+		cancelFunc:          cancelFunc,
+		logger:              utils.AddFieldsToLogger(logger, "operator_id", operatorID),
 
 		csStateUpdates:        make(chan changeStreamStateUpdate),
 		callExchangeSubs:      map[string]map[*mongodbCallExchange]struct{}{},
@@ -400,6 +411,8 @@ type mongodbWebRTCCall struct {
 	CallerDone         bool                  `bson:"caller_done"`
 	CallerError        string                `bson:"caller_error,omitempty"`
 	DisableTrickle     bool                  `bson:"disable_trickle"`
+	Priority           WebRTCCallPriority    `bson:"priority"`
+	TraceContext       map[string]string     `bson:"trace_context,omitempty"`
 	Answered           bool                  `bson:"answered"`
 	AnswererSDP        string                `bson:"answerer_sdp,omitempty"`
 	AnswererCandidates []mongodbICECandidate `bson:"answerer_candidates,omitempty"`
@@ -424,6 +437,8 @@ const (
 	webrtcCallAnswererDoneField       = "answerer_done"
 	webrtcCallAnswererErrorField      = "answerer_error"
 
+	webrtcCallPriorityField = "priority"
+
 	webrtcOperatorIDField                        = "_id"
 	webrtcOperatorHostsField                     = "hosts"
 	webrtcOperatorHostsHostField                 = "host"
@@ -433,11 +448,21 @@ const (
 	webrtcOperatorHostsHostCombinedField         = webrtcOperatorHostsField + "." + webrtcOperatorHostsHostField
 	webrtcOperatorHostsCallerSizeCombinedField   = webrtcOperatorHostsField + "." + webrtcOperatorHostsCallerSizeField
 	webrtcOperatorHostsAnswererSizeCombinedField = webrtcOperatorHostsField + "." + webrtcOperatorHostsAnswererSizeField
+
+	webrtcOperatorHostsLastHeartbeatField           = "last_heartbeat"
+	webrtcOperatorHostsAnswererVersionField         = "answerer_version"
+	webrtcOperatorHostsLastHeartbeatCombinedField   = webrtcOperatorHostsField + "." + webrtcOperatorHostsLastHeartbeatField
+	webrtcOperatorHostsAnswererVersionCombinedField = webrtcOperatorHostsField + "." + webrtcOperatorHostsAnswererVersionField
+
+	webrtcHostIDField              = "_id"
+	webrtcHostLastHeartbeatField   = "last_heartbeat"
+	webrtcHostAnswererVersionField = "answerer_version"
 )
 
 type mongodbNewCallEventHandler struct {
 	eventChan   chan<- mongodbCallEvent // expected buffered cap 1
 	receiveOnce sync.Once
+	presence    *WebRTCAnswererPresence
 }
 
 func (newCall *mongodbNewCallEventHandler) Send(event mongodbCallEvent, logger utils.ZapCompatibleLogger) bool {
@@ -487,12 +512,21 @@ func (queue *mongoDBWebRTCCallQueue) operatorLivenessLoop() {
 		type callerAnswererQueueSizes struct {
 			Caller   uint64
 			Answerer uint64
+			// of the waiting answerer heard from last.
+			LastHeartbeat   time.Time
+			AnswererVersion string
 		}
 		queue.csStateMu.RLock()
 		hosts := make(map[string]callerAnswererQueueSizes, len(queue.waitingForNewCallSubs)+len(queue.callExchangeSubs))
 		for host, waiting := range queue.waitingForNewCallSubs {
 			sizes := hosts[host]
 			sizes.Answerer += uint64(len(waiting))
+			for handler := range waiting {
+				if lastHeartbeat := handler.presence.LastHeartbeat(); lastHeartbeat.After(sizes.LastHeartbeat) {
+					sizes.LastHeartbeat = lastHeartbeat
+					sizes.AnswererVersion = handler.presence.Version()
+				}
+			}
 			hosts[host] = sizes
 		}
 		for _, exchanges := range queue.callExchangeSubs {
@@ -520,11 +554,18 @@ func (queue *mongoDBWebRTCCallQueue) operatorLivenessLoop() {
 				hostsWithAnswerers = append(hostsWithAnswerers, host)
 			}
 
-			hostSizes = append(hostSizes, bson.D{
+			hostSize := bson.D{
 				{webrtcOperatorHostsHostField, host},
 				{webrtcOperatorHostsCallerSizeField, sizes.Caller},
 				{webrtcOperatorHostsAnswererSizeField, sizes.Answerer},
-			})
+			}
+			if !sizes.LastHeartbeat.IsZero() {
+				hostSize = append(hostSize,
+					bson.E{webrtcOperatorHostsLastHeartbeatField, sizes.LastHeartbeat},
+					bson.E{webrtcOperatorHostsAnswererVersionField, sizes.AnswererVersion},
+				)
+			}
+			hostSizes = append(hostSizes, hostSize)
 		}
 		updateCtx, cancel := context.WithTimeout(queue.cancelCtx, operatorHeartbeatWindow/3)
 		start := time.Now()
@@ -583,12 +624,70 @@ func (queue *mongoDBWebRTCCallQueue) operatorLivenessLoop() {
 			)
 		}
 
+		heartbeats := make(map[string]hostHeartbeat, len(hosts))
+		for host, sizes := range hosts {
+			if !sizes.LastHeartbeat.IsZero() {
+				heartbeats[host] = hostHeartbeat{At: sizes.LastHeartbeat, AnswererVersion: sizes.AnswererVersion}
+			}
+		}
+		queue.persistHostHeartbeats(heartbeats)
+
 		if queue.onAnswererLiveness != nil {
 			queue.onAnswererLiveness(hostsWithAnswerers, time.Now())
 		}
 	}
 }
 
+type hostHeartbeat struct {
+	At              time.Time
+	AnswererVersion string
+}
+
+// persistHostHeartbeats writes the heartbeats of hosts that advanced since they were last written
+// to the hosts collection so that they are still known once the hosts' answerers leave, unlike
+// the operator documents. A heartbeat only replaces an older one, since the answerers of a host
+// may be connected to several operators.
+func (queue *mongoDBWebRTCCallQueue) persistHostHeartbeats(heartbeats map[string]hostHeartbeat) {
+	var models []mongo.WriteModel
+	for host, heartbeat := range heartbeats {
+		if !heartbeat.At.After(queue.persistedHeartbeats[host]) {
+			continue
+		}
+		isNewer := bson.D{{"$gt", bson.A{heartbeat.At, bson.D{{"$ifNull", bson.A{"$" + webrtcHostLastHeartbeatField, time.Time{}}}}}}}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{webrtcHostIDField, host}}).
+			SetUpdate(bson.A{bson.D{{"$set", bson.D{
+				{webrtcHostAnswererVersionField, bson.D{{"$cond", bson.A{
+					isNewer, heartbeat.AnswererVersion, "$" + webrtcHostAnswererVersionField,
+				}}}},
+				{webrtcHostLastHeartbeatField, bson.D{{"$cond", bson.A{
+					isNewer, heartbeat.At, "$" + webrtcHostLastHeartbeatField,
+				}}}},
+			}}}}).
+			SetUpsert(true))
+	}
+	for host := range queue.persistedHeartbeats {
+		if _, ok := heartbeats[host]; !ok {
+			delete(queue.persistedHeartbeats, host)
+		}
+	}
+	if len(models) == 0 {
+		return
+	}
+
+	updateCtx, cancel := context.WithTimeout(queue.cancelCtx, operatorHeartbeatWindow/3)
+	defer cancel()
+	if _, err := queue.hostsColl.BulkWrite(updateCtx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		if !errors.Is(err, context.Canceled) {
+			queue.logger.Infow("failed to update host heartbeats", "error", err)
+		}
+		return
+	}
+	for host, heartbeat := range heartbeats {
+		queue.persistedHeartbeats[host] = heartbeat.At
+	}
+}
+
 // The changeStreamManager is responsible for maintaining a change stream that is always updating
 // its query in response to new answerers making themselves available for calls. It helps
 // efficiently swap out new change streams while an old one may still be in use by the subscriptionManager.
@@ -966,8 +1065,13 @@ func (queue *mongoDBWebRTCCallQueue) subscribeForNewCallOnHosts(
 	queue.csStateMu.Lock()
 	subChan := make(chan mongodbCallEvent, 1)
 	ready := make(chan struct{})
+	presence, ok := ContextWebRTCAnswererPresence(ctx)
+	if !ok {
+		presence = NewWebRTCAnswererPresence("")
+	}
 	callEventHandler := &mongodbNewCallEventHandler{
 		eventChan: subChan,
+		presence:  presence,
 	}
 
 	var alreadyTrackedCount int
@@ -1133,6 +1237,96 @@ func (queue *mongoDBWebRTCCallQueue) checkHostOnline(ctx context.Context, hosts
 	return nil
 }
 
+// HostStatuses returns the status of each of the given hosts, or of every host with answerers
+// connected if none are given, by summing answerers across the operators that are alive. Hosts
+// whose answerers all left keep the LastHeartbeat last written to the hosts collection.
+func (queue *mongoDBWebRTCCallQueue) HostStatuses(ctx context.Context, hosts ...string) ([]WebRTCHostStatus, error) {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::HostStatuses")
+	defer span.End()
+
+	hostsMatch := bson.D{{"$match", bson.D{{webrtcOperatorHostsAnswererSizeCombinedField, bson.D{{"$gt", 0}}}}}}
+	if len(hosts) > 0 {
+		hostsMatch = bson.D{{"$match", bson.D{{webrtcOperatorHostsHostCombinedField, bson.D{{"$in", hosts}}}}}}
+	}
+	pipeline := []interface{}{
+		// operators that stopped updating are not removed right away.
+		bson.D{{"$match", bson.D{{webrtcOperatorExpireAtField, bson.D{{"$gt", time.Now()}}}}}},
+		hostsMatch,
+		projectStage,
+		unwindAggStage,
+		hostsMatch,
+		// so that the version is of the answerer heard from last.
+		bson.D{{"$sort", bson.D{{webrtcOperatorHostsLastHeartbeatCombinedField, -1}}}},
+		bson.D{{"$group", bson.D{
+			{"_id", "$" + webrtcOperatorHostsHostCombinedField},
+			{"answerer_size", bson.D{{"$sum", "$" + webrtcOperatorHostsAnswererSizeCombinedField}}},
+			{"last_heartbeat", bson.D{{"$max", "$" + webrtcOperatorHostsLastHeartbeatCombinedField}}},
+			{"answerer_version", bson.D{{"$first", "$" + webrtcOperatorHostsAnswererVersionCombinedField}}},
+		}}},
+		bson.D{{"$sort", bson.D{{"_id", 1}}}},
+	}
+	cursor, err := queue.operatorsColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Host            string    `bson:"_id"`
+		AnswererSize    int       `bson:"answerer_size"`
+		LastHeartbeat   time.Time `bson:"last_heartbeat"`
+		AnswererVersion string    `bson:"answerer_version"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]WebRTCHostStatus, 0, len(results))
+	for _, result := range results {
+		statuses = append(statuses, WebRTCHostStatus{
+			Host:            result.Host,
+			Answerers:       result.AnswererSize,
+			LastHeartbeat:   result.LastHeartbeat,
+			AnswererVersion: result.AnswererVersion,
+		})
+	}
+	if len(hosts) == 0 {
+		return statuses, nil
+	}
+
+	cursor, err = queue.hostsColl.Find(ctx, bson.D{{webrtcHostIDField, bson.D{{"$in", hosts}}}})
+	if err != nil {
+		return nil, err
+	}
+	var heartbeats []struct {
+		Host            string    `bson:"_id"`
+		LastHeartbeat   time.Time `bson:"last_heartbeat"`
+		AnswererVersion string    `bson:"answerer_version"`
+	}
+	if err := cursor.All(ctx, &heartbeats); err != nil {
+		return nil, err
+	}
+	lastHeartbeats := make(map[string]hostHeartbeat, len(heartbeats))
+	for _, heartbeat := range heartbeats {
+		lastHeartbeats[heartbeat.Host] = hostHeartbeat{At: heartbeat.LastHeartbeat, AnswererVersion: heartbeat.AnswererVersion}
+	}
+
+	// hosts without answerers have no results.
+	hostStatuses := make([]WebRTCHostStatus, 0, len(hosts))
+	for _, host := range hosts {
+		hostStatus := WebRTCHostStatus{Host: host}
+		if idx := slices.IndexFunc(statuses, func(status WebRTCHostStatus) bool {
+			return status.Host == host
+		}); idx != -1 {
+			hostStatus = statuses[idx]
+		}
+		if heartbeat := lastHeartbeats[host]; heartbeat.At.After(hostStatus.LastHeartbeat) {
+			hostStatus.LastHeartbeat = heartbeat.At
+			hostStatus.AnswererVersion = heartbeat.AnswererVersion
+		}
+		hostStatuses = append(hostStatuses, hostStatus)
+	}
+	return hostStatuses, nil
+}
+
 // Uses the passed in parameters to increment the connectionEstablishmentExpectedFailures
 // metric and add attributes to the passed in span.
 func (queue *mongoDBWebRTCCallQueue) incrementConnectionEstablishmentExpectedFailures(
@@ -1265,6 +1459,9 @@ func (queue *mongoDBWebRTCCallQueue) SendOfferInit(
 		CallerOperatorID: queue.operatorID,
 		Host:             host,
 		CallerSDP:        sdp,
+		DisableTrickle:   disableTrickle,
+		Priority:         ContextWebRTCCallPriority(ctx),
+		TraceContext:     callTraceContext(ctx),
 		SDKType:          sdkType,
 		OrganizationID:   organizationID,
 	}
@@ -1484,6 +1681,66 @@ func (queue *mongoDBWebRTCCallQueue) SendOfferError(ctx context.Context, host, u
 	return nil
 }
 
+// takeWaitingCall takes the waiting offer to one of the hosts of the highest priority class that
+// has been waiting the longest, returning mongo.ErrNoDocuments if there is none. Offers stored
+// before they had a priority class have no priority field and are taken as normal priority;
+// sorting on the field alone would put them behind low priority ones.
+func (queue *mongoDBWebRTCCallQueue) takeWaitingCall(
+	ctx context.Context,
+	hosts []string,
+	startedAtWindow time.Time,
+) (mongodbWebRTCCall, error) {
+	const effectivePriorityField = "effective_priority"
+	waitingFilter := bson.D{
+		{webrtcCallHostField, bson.D{{"$in", hosts}}},
+		{webrtcCallCallerErrorField, bson.D{{"$exists", false}}},
+		{webrtcCallAnsweredField, false},
+		{webrtcCallStartedAtField, bson.D{{"$gt", startedAtWindow}}},
+	}
+	pipeline := []interface{}{
+		bson.D{{"$match", waitingFilter}},
+		bson.D{{"$addFields", bson.D{
+			{effectivePriorityField, bson.D{{"$ifNull", bson.A{"$" + webrtcCallPriorityField, int(WebRTCCallPriorityNormal)}}}},
+		}}},
+		bson.D{{"$sort", bson.D{
+			{effectivePriorityField, -1},
+			{webrtcCallStartedAtField, 1},
+		}}},
+		bson.D{{"$limit", 1}},
+		bson.D{{"$project", bson.D{{webrtcCallIDField, 1}}}},
+	}
+	for {
+		cursor, err := queue.callsColl.Aggregate(ctx, pipeline)
+		if err != nil {
+			return mongodbWebRTCCall{}, err
+		}
+		var waiting []mongodbWebRTCCall
+		if err := cursor.All(ctx, &waiting); err != nil {
+			return mongodbWebRTCCall{}, err
+		}
+		if len(waiting) == 0 {
+			return mongodbWebRTCCall{}, mongo.ErrNoDocuments
+		}
+
+		result := queue.callsColl.FindOneAndUpdate(
+			ctx,
+			append(bson.D{{webrtcCallIDField, waiting[0].ID}}, waitingFilter...),
+			bson.D{
+				{"$set", bson.D{
+					{webrtcCallAnswererOperatorIDField, queue.operatorID},
+					{webrtcCallAnsweredField, true},
+				}},
+			})
+		var callReq mongodbWebRTCCall
+		err = result.Decode(&callReq)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// another answerer took it first; look for the next one.
+			continue
+		}
+		return callReq, err
+	}
+}
+
 // RecvOffer receives the next offer for the given host. It should respond with an answer
 // once a decision is made.
 func (queue *mongoDBWebRTCCallQueue) RecvOffer(ctx context.Context, hosts []string) (WebRTCCallOfferExchange, error) {
@@ -1534,22 +1791,7 @@ func (queue *mongoDBWebRTCCallQueue) RecvOffer(ctx context.Context, hosts []stri
 		// first we wait to see if there is a caller waiting for us in the Callers Collection
 		// If err != nil that means the doc doesn't exist yet or there is another error
 		// we care if the doc doesn't yet exist
-		result := queue.callsColl.FindOneAndUpdate(
-			recvOfferCtx,
-			bson.D{
-				{webrtcCallHostField, bson.D{{"$in", hosts}}},
-				{webrtcCallCallerErrorField, bson.D{{"$exists", false}}},
-				{webrtcCallAnsweredField, false},
-				{webrtcCallStartedAtField, bson.D{{"$gt", startedAtWindow}}},
-			},
-			bson.D{
-				{"$set", bson.D{
-					{webrtcCallAnswererOperatorIDField, queue.operatorID},
-					{webrtcCallAnsweredField, true},
-				}},
-			})
-		var callReq mongodbWebRTCCall
-		err = result.Decode(&callReq)
+		callReq, err := queue.takeWaitingCall(recvOfferCtx, hosts, startedAtWindow)
 		if err != nil {
 			if !errors.Is(err, mongo.ErrNoDocuments) {
 				return mongodbWebRTCCall{}, false, err
@@ -1875,6 +2117,10 @@ func (resp *mongoDBWebRTCCallOfferExchange) Deadline() time.Time {
 	return resp.deadline
 }
 
+func (resp *mongoDBWebRTCCallOfferExchange) TraceContext() map[string]string {
+	return resp.call.TraceContext
+}
+
 func (resp *mongoDBWebRTCCallOfferExchange) CallerCandidates() <-chan webrtc.ICECandidateInit {
 	return resp.callerCandidates
 }
@@ -19,22 +19,6 @@ import (
 
 var operatorID = uuid.NewString()
 
-func TestMongoDBWebRTCCallQueue(t *testing.T) {
-	client := testutils.BackingMongoDBClient(t)
-
-	testWebRTCCallQueue(t, func(t *testing.T) (WebRTCCallQueue, WebRTCCallQueue, func()) {
-		t.Helper()
-		test.That(t, client.Database(mongodbWebRTCCallQueueDBName).Drop(context.Background()), test.ShouldBeNil)
-		logger := golog.NewTestLogger(t)
-		callQueue, err := NewMongoDBWebRTCCallQueue(context.Background(), operatorID, 50, client, logger,
-			func(hosts []string, atTime time.Time) {}, nil)
-		test.That(t, err, test.ShouldBeNil)
-		return callQueue, callQueue, func() {
-			test.That(t, callQueue.Close(), test.ShouldBeNil)
-		}
-	})
-}
-
 func TestMongoDBWebRTCCallQueueMulti(t *testing.T) {
 	client := testutils.BackingMongoDBClient(t)
 
@@ -57,8 +41,6 @@ func TestMongoDBWebRTCCallQueueMulti(t *testing.T) {
 		}
 	}
 
-	testWebRTCCallQueue(t, setupQueues)
-
 	t.Run("max queue size", func(t *testing.T) {
 		undo := setDefaultOfferDeadline(time.Minute)
 		defer undo()
@@ -259,6 +241,88 @@ func TestMongoDBWebRTCCallQueueOperatorDocDeleted(t *testing.T) {
 	wg.Wait()
 }
 
+func TestMongoDBWebRTCCallQueueHostStatuses(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	client := testutils.BackingMongoDBClient(t)
+	test.That(t, client.Database(mongodbWebRTCCallQueueDBName).Drop(context.Background()), test.ShouldBeNil)
+	queue, err := NewMongoDBWebRTCCallQueue(context.Background(), uuid.NewString(), 1, client, logger, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, queue.Close(), test.ShouldBeNil)
+	}()
+	directory, ok := queue.(WebRTCHostDirectory)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	host := primitive.NewObjectID().Hex()
+	presence := NewWebRTCAnswererPresence("grpc-go/test")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = queue.RecvOffer(ContextWithWebRTCAnswererPresence(ctx, presence), []string{host})
+	}()
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		statuses, err := directory.HostStatuses(context.Background(), host)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, statuses, test.ShouldHaveLength, 1)
+		test.That(tb, statuses[0].Answerers, test.ShouldEqual, 1)
+		test.That(tb, statuses[0].AnswererVersion, test.ShouldEqual, "grpc-go/test")
+	})
+	cancel()
+	wg.Wait()
+
+	// the last heartbeat is kept once the answerer leaves.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		statuses, err := directory.HostStatuses(context.Background(), host)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, statuses, test.ShouldHaveLength, 1)
+		test.That(tb, statuses[0].Answerers, test.ShouldEqual, 0)
+		test.That(tb, statuses[0].LastHeartbeat, test.ShouldHappenWithin, time.Millisecond, presence.LastHeartbeat())
+		test.That(tb, statuses[0].AnswererVersion, test.ShouldEqual, "grpc-go/test")
+	})
+}
+
+// Offers used to be stored without disable_trickle, so answerers always trickled candidates to
+// callers that were not expecting them.
+func TestMongoDBWebRTCCallQueueDisableTrickle(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	client := testutils.BackingMongoDBClient(t)
+	test.That(t, client.Database(mongodbWebRTCCallQueueDBName).Drop(context.Background()), test.ShouldBeNil)
+	queue, err := NewMongoDBWebRTCCallQueue(context.Background(), uuid.NewString(), 1, client, logger, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, queue.Close(), test.ShouldBeNil)
+	}()
+
+	host := primitive.NewObjectID().Hex()
+	addFakeAnswererForHost(t, client, host)
+
+	for _, disableTrickle := range []bool{true, false} {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		callID, _, respDone, cancelOffer, err := queue.SendOfferInit(ctx, host, "somesdp", disableTrickle)
+		test.That(t, err, test.ShouldBeNil)
+
+		var call mongodbWebRTCCall
+		test.That(t, client.Database(mongodbWebRTCCallQueueDBName).Collection(mongodbWebRTCCallQueueCallsCollName).
+			FindOne(ctx, bson.M{"_id": callID}).Decode(&call), test.ShouldBeNil)
+		test.That(t, call.DisableTrickle, test.ShouldEqual, disableTrickle)
+
+		exchange, err := queue.RecvOffer(ctx, []string{host})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, exchange.UUID(), test.ShouldEqual, callID)
+		test.That(t, exchange.DisableTrickleICE(), test.ShouldEqual, disableTrickle)
+
+		cancelOffer()
+		<-respDone
+		test.That(t, exchange.AnswererDone(ctx), test.ShouldBeNil)
+		cancel()
+	}
+}
+
 func addFakeAnswererForHost(t *testing.T, client *mongo.Client, host string) {
 	t.Helper()
 	_, err := client.Database(mongodbWebRTCCallQueueDBName).Collection(mongodbWebRTCCallQueueOperatorsCollName).InsertOne(context.Background(),
@@ -282,3 +346,45 @@ func removeOperatorDocument(t *testing.T, client *mongo.Client, operatorID strin
 		bson.M{"_id": operatorID})
 	test.That(t, err, test.ShouldBeNil)
 }
+
+// Offers stored before they had a priority class have no priority field and must not wait
+// behind low priority offers.
+func TestMongoDBWebRTCCallQueueMissingPriority(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	client := testutils.BackingMongoDBClient(t)
+	test.That(t, client.Database(mongodbWebRTCCallQueueDBName).Drop(context.Background()), test.ShouldBeNil)
+	queue, err := NewMongoDBWebRTCCallQueue(context.Background(), uuid.NewString(), 2, client, logger, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, queue.Close(), test.ShouldBeNil)
+	}()
+
+	host := primitive.NewObjectID().Hex()
+	addFakeAnswererForHost(t, client, host)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	lowID, _, lowDone, cancelLow, err := queue.SendOfferInit(
+		ContextWithWebRTCCallPriority(ctx, WebRTCCallPriorityLow), host, "low", false)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		cancelLow()
+		<-lowDone
+	}()
+	legacyID, _, legacyDone, cancelLegacy, err := queue.SendOfferInit(ctx, host, "legacy", false)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		cancelLegacy()
+		<-legacyDone
+	}()
+	_, err = client.Database(mongodbWebRTCCallQueueDBName).Collection(mongodbWebRTCCallQueueCallsCollName).
+		UpdateOne(ctx, bson.M{"_id": legacyID}, bson.M{"$unset": bson.M{webrtcCallPriorityField: ""}})
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, expectedID := range []string{legacyID, lowID} {
+		exchange, err := queue.RecvOffer(ctx, []string{host})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, exchange.UUID(), test.ShouldEqual, expectedID)
+		test.That(t, exchange.AnswererDone(ctx), test.ShouldBeNil)
+	}
+}
@@ -0,0 +1,1194 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/viamrobotics/webrtc/v3"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/utils"
+)
+
+// A redisWebRTCCallQueue is a Redis implementation of a call queue designed to be used for
+// multi-node, distributed deployments that already run Redis.
+//
+// Calls are stored in a hash per call and each side of an exchange appends its updates to
+// its own per-call stream that the other side reads from the beginning. New offers are
+// appended to a per-host stream that all answerers for that host consume through a single
+// consumer group so that each offer is handed to at most one answerer. Since the streams of
+// different hosts may live in different slots of a Redis Cluster, answerers read them one
+// at a time and are told of new offers through a channel per host instead of blocking on all
+// of them at once. Operators publish how many callers and answerers they have per host in
+// order to keep track of eventually consistent queue maximums and host liveness, just like
+// the MongoDB queue.
+type redisWebRTCCallQueue struct {
+	operatorID              string
+	maxHostCallers          uint64
+	client                  redis.UniversalClient
+	activeBackgroundWorkers sync.WaitGroup
+	logger                  utils.ZapCompatibleLogger
+
+	cancelCtx  context.Context
+	cancelFunc func()
+
+	hostSizesMu   sync.Mutex
+	hostSizes     map[string]redisHostQueueSizes
+	hostPresences map[string]map[*WebRTCAnswererPresence]struct{}
+	stateChanged  chan struct{}
+	lastHostsSeen utils.StringSet
+	// persistedHeartbeats are the last heartbeats of each host written to Redis. It is only
+	// used by the operatorLivenessLoop.
+	persistedHeartbeats map[string]time.Time
+
+	// offerWaiters are woken up when an offer is sent to the host of the channel they wait on.
+	offerWaitersMu sync.Mutex
+	offerWaiters   map[string]map[chan struct{}]struct{}
+}
+
+type redisHostQueueSizes struct {
+	CallerSize   uint64 `json:"caller_size"`
+	AnswererSize uint64 `json:"answerer_size"`
+	ExpireAt     int64  `json:"expire_at"`
+}
+
+// Key prefix and consumer group used by the redisWebRTCCallQueue.
+var (
+	redisWebRTCCallQueueKeyPrefix = "rpc:webrtc"
+	redisWebRTCCallQueueOffersGrp = "answerers"
+)
+
+// how long an offer read by an answerer may go unacknowledged before another answerer takes it
+// over. An answerer only holds an offer for as long as it takes to claim it, unless it died.
+var redisOfferClaimMinIdle = 5 * time.Second
+
+// The hash tags (braces) keep all keys touched by a single script in the same slot
+// when running against a Redis Cluster.
+func redisCallKey(callID string) string {
+	return fmt.Sprintf("%s:call:{%s}", redisWebRTCCallQueueKeyPrefix, callID)
+}
+
+func redisCallCallerEventsKey(callID string) string {
+	return redisCallKey(callID) + ":caller"
+}
+
+func redisCallAnswererEventsKey(callID string) string {
+	return redisCallKey(callID) + ":answerer"
+}
+
+// redisHostOffersKey is the stream of offers of the given priority class to the host. Normal
+// offers keep the stream they had before there were priority classes.
+func redisHostOffersKey(host string, priority WebRTCCallPriority) string {
+	key := fmt.Sprintf("%s:host:{%s}:offers", redisWebRTCCallQueueKeyPrefix, host)
+	if priority == WebRTCCallPriorityNormal {
+		return key
+	}
+	return key + ":" + priority.String()
+}
+
+func redisHostOperatorsKey(host string) string {
+	return fmt.Sprintf("%s:host:{%s}:operators", redisWebRTCCallQueueKeyPrefix, host)
+}
+
+// redisHostHeartbeatKey keeps when an answerer for the host was last heard from, even after
+// they all left.
+func redisHostHeartbeatKey(host string) string {
+	return fmt.Sprintf("%s:host:{%s}:heartbeat", redisWebRTCCallQueueKeyPrefix, host)
+}
+
+// redisHostOffersChannel is where the answerers of the host are told that there is a new offer.
+func redisHostOffersChannel(host string) string {
+	return fmt.Sprintf("%s:host:{%s}:offered", redisWebRTCCallQueueKeyPrefix, host)
+}
+
+// redisOnlineHostsKey is a sorted set of the hosts with answerers scored by when the operators
+// that reported them last expire.
+func redisOnlineHostsKey() string {
+	return redisWebRTCCallQueueKeyPrefix + ":hosts"
+}
+
+const (
+	// how long a single blocking read waits before checking whether it should stop. Answerers
+	// also look for offers this often in case they were not told of one.
+	redisBlockInterval = time.Second
+
+	// an approximate bound on how many offers are kept in a host's offer stream. Offers
+	// in the stream that are older than the offer deadline are skipped by answerers.
+	redisHostOffersMaxLen = 1000
+
+	redisEventTypeField      = "type"
+	redisEventSDPField       = "sdp"
+	redisEventCandidateField = "candidate"
+	redisEventErrorField     = "error"
+	redisEventTypeSDP        = "sdp"
+	redisEventTypeCandidate  = "candidate"
+	redisEventTypeDone       = "done"
+	redisEventTypeError      = "error"
+	redisOfferCallIDField    = "id"
+
+	redisHostLastHeartbeatField   = "last_heartbeat"
+	redisHostAnswererVersionField = "answerer_version"
+)
+
+type redisWebRTCCall struct {
+	ID                 string `redis:"id"`
+	CallerOperatorID   string `redis:"caller_operator_id"`
+	AnswererOperatorID string `redis:"answerer_operator_id,omitempty"`
+	Host               string `redis:"host"`
+	StartedAt          int64  `redis:"started_at"`
+	ExpireAt           int64  `redis:"expire_at"`
+	CallerSDP          string `redis:"caller_sdp"`
+	CallerDone         bool   `redis:"caller_done"`
+	CallerError        string `redis:"caller_error,omitempty"`
+	DisableTrickle     bool   `redis:"disable_trickle"`
+	TraceParent        string `redis:"traceparent,omitempty"`
+	TraceState         string `redis:"tracestate,omitempty"`
+	Answered           bool   `redis:"answered"`
+	AnswererDone       bool   `redis:"answerer_done"`
+	AnswererError      string `redis:"answerer_error,omitempty"`
+}
+
+const (
+	redisCallCallerDoneField    = "caller_done"
+	redisCallCallerErrorField   = "caller_error"
+	redisCallAnswererDoneField  = "answerer_done"
+	redisCallAnswererErrorField = "answerer_error"
+)
+
+// redisAppendCallEventScript appends an event to one side of a call exchange. It only does
+// so if the call is still active and belongs to the given host (if any). If a guard field is
+// given and already set, the event is rejected. If a state field is given, it is set on the
+// call before the event is appended.
+//
+// KEYS[1] call key, KEYS[2] events stream key
+// ARGV[1] host, ARGV[2] state field, ARGV[3] state value, ARGV[4] guard field, ARGV[5:] event.
+var redisAppendCallEventScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if ARGV[1] ~= '' and redis.call('HGET', KEYS[1], 'host') ~= ARGV[1] then
+	return 0
+end
+if ARGV[4] ~= '' and redis.call('HGET', KEYS[1], ARGV[4]) == '1' then
+	return 0
+end
+if ARGV[2] ~= '' then
+	redis.call('HSET', KEYS[1], ARGV[2], ARGV[3])
+end
+local event = {}
+for i = 5, #ARGV do
+	event[#event + 1] = ARGV[i]
+end
+redis.call('XADD', KEYS[2], '*', unpack(event))
+redis.call('PEXPIREAT', KEYS[2], redis.call('HGET', KEYS[1], 'expire_at'))
+return 1
+`)
+
+// redisClaimCallScript atomically marks a call as answered by the given operator if no one
+// else has answered it, the caller has not errored, and it started after the given window.
+//
+// KEYS[1] call key
+// ARGV[1] operator ID, ARGV[2] started at window (unix milliseconds).
+var redisClaimCallScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if redis.call('HGET', KEYS[1], 'answered') == '1' then
+	return 0
+end
+if redis.call('HEXISTS', KEYS[1], 'caller_error') == 1 then
+	return 0
+end
+if tonumber(redis.call('HGET', KEYS[1], 'started_at')) <= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'answered', '1', 'answerer_operator_id', ARGV[1])
+return 1
+`)
+
+// redisSetHostHeartbeatScript records a heartbeat of an answerer of a host unless a later one
+// was already recorded, since the answerers of a host may be connected to several operators.
+//
+// KEYS[1] host heartbeat key
+// ARGV[1] last heartbeat (unix milliseconds), ARGV[2] answerer version.
+var redisSetHostHeartbeatScript = redis.NewScript(`
+if tonumber(ARGV[1]) > tonumber(redis.call('HGET', KEYS[1], 'last_heartbeat') or '0') then
+	redis.call('HSET', KEYS[1], 'last_heartbeat', ARGV[1], 'answerer_version', ARGV[2])
+end
+return 0
+`)
+
+// NewRedisWebRTCCallQueue returns a new Redis based call queue where calls are transferred
+// through the given client. The operator ID must be unique (e.g. a hostname, container ID, UUID, etc.).
+// The given max queue size specifies how many big a queue can be for a given host; the size is used
+// as an approximation and at times may exceed the max as a performance/consistency balance of being
+// a distributed queue.
+func NewRedisWebRTCCallQueue(
+	ctx context.Context,
+	operatorID string,
+	maxHostCallers uint64,
+	client redis.UniversalClient,
+	logger utils.ZapCompatibleLogger,
+) (WebRTCCallQueue, error) {
+	if operatorID == "" {
+		return nil, errors.New("expected non-empty operatorID")
+	}
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to reach redis")
+	}
+
+	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	queue := &redisWebRTCCallQueue{
+		operatorID:     operatorID,
+		maxHostCallers: maxHostCallers,
+		client:         client,
+		logger:         utils.AddFieldsToLogger(logger, "operator_id", operatorID),
+		cancelCtx:      cancelCtx,
+		cancelFunc:     cancelFunc,
+		hostSizes:      map[string]redisHostQueueSizes{},
+		hostPresences:  map[string]map[*WebRTCAnswererPresence]struct{}{},
+		stateChanged:   make(chan struct{}, 1),
+		lastHostsSeen:  utils.NewStringSet(),
+
+		persistedHeartbeats: map[string]time.Time{},
+		offerWaiters:        map[string]map[chan struct{}]struct{}{},
+	}
+
+	queue.activeBackgroundWorkers.Add(2)
+	utils.ManagedGo(queue.operatorLivenessLoop, queue.activeBackgroundWorkers.Done)
+	utils.ManagedGo(queue.offerNotificationLoop, queue.activeBackgroundWorkers.Done)
+
+	return queue, nil
+}
+
+// trackHosts adjusts how many callers or answerers this operator has for the given hosts
+// and returns a func to undo it.
+func (queue *redisWebRTCCallQueue) trackHosts(forCaller bool, hosts ...string) func() {
+	adjust := func(add bool) {
+		queue.hostSizesMu.Lock()
+		for _, host := range hosts {
+			sizes := queue.hostSizes[host]
+			size := &sizes.AnswererSize
+			if forCaller {
+				size = &sizes.CallerSize
+			}
+			if add {
+				*size++
+			} else {
+				*size--
+			}
+			if sizes.CallerSize == 0 && sizes.AnswererSize == 0 {
+				delete(queue.hostSizes, host)
+			} else {
+				queue.hostSizes[host] = sizes
+			}
+		}
+		queue.hostSizesMu.Unlock()
+		select {
+		case queue.stateChanged <- struct{}{}:
+		default:
+		}
+	}
+	adjust(true)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			adjust(false)
+		})
+	}
+}
+
+// trackPresence keeps the given answerer waiting for calls for the given hosts known to other
+// operators and returns a func to undo it.
+func (queue *redisWebRTCCallQueue) trackPresence(presence *WebRTCAnswererPresence, hosts ...string) func() {
+	queue.hostSizesMu.Lock()
+	for _, host := range hosts {
+		if queue.hostPresences[host] == nil {
+			queue.hostPresences[host] = map[*WebRTCAnswererPresence]struct{}{}
+		}
+		queue.hostPresences[host][presence] = struct{}{}
+	}
+	queue.hostSizesMu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			queue.hostSizesMu.Lock()
+			for _, host := range hosts {
+				delete(queue.hostPresences[host], presence)
+				if len(queue.hostPresences[host]) == 0 {
+					delete(queue.hostPresences, host)
+				}
+			}
+			queue.hostSizesMu.Unlock()
+		})
+	}
+}
+
+// The operatorLivenessLoop keeps the distributed queue aware of this operator's existence, in
+// addition to the hosts its listening to calls for, in order to keep track of eventually
+// consistent queue maximums.
+func (queue *redisWebRTCCallQueue) operatorLivenessLoop() {
+	ticker := time.NewTicker(operatorStateUpdateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-queue.cancelCtx.Done():
+			return
+		case <-ticker.C:
+		case <-queue.stateChanged:
+		}
+
+		queue.hostSizesMu.Lock()
+		hosts := make(map[string]redisHostQueueSizes, len(queue.hostSizes))
+		for host, sizes := range queue.hostSizes {
+			hosts[host] = sizes
+		}
+		heartbeats := make(map[string]hostHeartbeat, len(queue.hostPresences))
+		for host, presences := range queue.hostPresences {
+			var heartbeat hostHeartbeat
+			for presence := range presences {
+				if lastHeartbeat := presence.LastHeartbeat(); lastHeartbeat.After(heartbeat.At) {
+					heartbeat = hostHeartbeat{At: lastHeartbeat, AnswererVersion: presence.Version()}
+				}
+			}
+			if heartbeat.At.After(queue.persistedHeartbeats[host]) {
+				heartbeats[host] = heartbeat
+			}
+		}
+		for host := range queue.persistedHeartbeats {
+			if _, ok := queue.hostPresences[host]; !ok {
+				delete(queue.persistedHeartbeats, host)
+			}
+		}
+		queue.hostSizesMu.Unlock()
+
+		updateCtx, cancel := context.WithTimeout(queue.cancelCtx, operatorHeartbeatWindow/3)
+		expireAt := time.Now().Add(operatorHeartbeatWindow)
+		_, err := queue.client.Pipelined(updateCtx, func(pipe redis.Pipeliner) error {
+			for host, sizes := range hosts {
+				sizes.ExpireAt = expireAt.UnixMilli()
+				//nolint:errchkjson
+				encoded, _ := json.Marshal(sizes)
+				pipe.HSet(updateCtx, redisHostOperatorsKey(host), queue.operatorID, encoded)
+				pipe.PExpireAt(updateCtx, redisHostOperatorsKey(host), expireAt)
+				if sizes.AnswererSize > 0 {
+					pipe.ZAddGT(updateCtx, redisOnlineHostsKey(), redis.Z{Score: float64(expireAt.UnixMilli()), Member: host})
+				}
+			}
+			for host := range queue.lastHostsSeen {
+				if _, ok := hosts[host]; !ok {
+					pipe.HDel(updateCtx, redisHostOperatorsKey(host), queue.operatorID)
+				}
+			}
+			pipe.ZRemRangeByScore(updateCtx, redisOnlineHostsKey(), "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10))
+			for host, heartbeat := range heartbeats {
+				redisSetHostHeartbeatScript.Eval(updateCtx, pipe, []string{redisHostHeartbeatKey(host)},
+					heartbeat.At.UnixMilli(), heartbeat.AnswererVersion)
+			}
+			return nil
+		})
+		cancel()
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				queue.logger.Infow("failed to update operator state for self", "error", err)
+			}
+			continue
+		}
+		queue.lastHostsSeen = utils.NewStringSet()
+		for host := range hosts {
+			queue.lastHostsSeen.Add(host)
+		}
+		for host, heartbeat := range heartbeats {
+			queue.persistedHeartbeats[host] = heartbeat.At
+		}
+	}
+}
+
+// The offerNotificationLoop wakes up the answerers waiting on this operator for offers to the
+// hosts that were just sent one.
+func (queue *redisWebRTCCallQueue) offerNotificationLoop() {
+	pubsub := queue.client.PSubscribe(queue.cancelCtx, redisHostOffersChannel("*"))
+	defer func() {
+		utils.UncheckedError(pubsub.Close())
+	}()
+	msgs := pubsub.Channel()
+	for {
+		select {
+		case <-queue.cancelCtx.Done():
+			return
+		case msg, ok := <-msgs:
+			if !ok {
+				return
+			}
+			queue.offerWaitersMu.Lock()
+			for waiter := range queue.offerWaiters[msg.Channel] {
+				select {
+				case waiter <- struct{}{}:
+				default:
+				}
+			}
+			queue.offerWaitersMu.Unlock()
+		}
+	}
+}
+
+// waitForOffers returns a channel that receives when an offer is sent to any of the given hosts
+// and a func to stop waiting.
+func (queue *redisWebRTCCallQueue) waitForOffers(hosts []string) (<-chan struct{}, func()) {
+	waiter := make(chan struct{}, 1)
+	queue.offerWaitersMu.Lock()
+	defer queue.offerWaitersMu.Unlock()
+	for _, host := range hosts {
+		channel := redisHostOffersChannel(host)
+		if queue.offerWaiters[channel] == nil {
+			queue.offerWaiters[channel] = map[chan struct{}]struct{}{}
+		}
+		queue.offerWaiters[channel][waiter] = struct{}{}
+	}
+	return waiter, func() {
+		queue.offerWaitersMu.Lock()
+		defer queue.offerWaitersMu.Unlock()
+		for _, host := range hosts {
+			channel := redisHostOffersChannel(host)
+			delete(queue.offerWaiters[channel], waiter)
+			if len(queue.offerWaiters[channel]) == 0 {
+				delete(queue.offerWaiters, channel)
+			}
+		}
+	}
+}
+
+// hostQueueSizes sums the caller and answerer sizes across all live operators for the given host.
+func (queue *redisWebRTCCallQueue) hostQueueSizes(ctx context.Context, host string) (redisHostQueueSizes, error) {
+	operators, err := queue.client.HGetAll(ctx, redisHostOperatorsKey(host)).Result()
+	if err != nil {
+		return redisHostQueueSizes{}, err
+	}
+	return sumHostQueueSizes(operators)
+}
+
+// sumHostQueueSizes sums the caller and answerer sizes of a host across the given operators that
+// are still live.
+func sumHostQueueSizes(operators map[string]string) (redisHostQueueSizes, error) {
+	now := time.Now().UnixMilli()
+	var total redisHostQueueSizes
+	for _, encoded := range operators {
+		var sizes redisHostQueueSizes
+		if err := json.Unmarshal([]byte(encoded), &sizes); err != nil {
+			return redisHostQueueSizes{}, err
+		}
+		if sizes.ExpireAt < now {
+			continue
+		}
+		total.CallerSize += sizes.CallerSize
+		total.AnswererSize += sizes.AnswererSize
+	}
+	return total, nil
+}
+
+// checkHostQueueSize checks if the total number of callers to or answerers for a set of
+// hosts exceeds the configured maxima across all operators.
+func (queue *redisWebRTCCallQueue) checkHostQueueSize(ctx context.Context, forCaller bool, hosts ...string) error {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::checkHostQueueSize")
+	defer span.End()
+
+	for _, host := range hosts {
+		sizes, err := queue.hostQueueSizes(ctx, host)
+		if err != nil {
+			return err
+		}
+		if forCaller && sizes.CallerSize >= queue.maxHostCallers {
+			return errTooManyConns
+		}
+		// we use maxHostAnswerersSize * 2 to accommodate an answerer that
+		// immediately reconnects
+		if !forCaller && sizes.AnswererSize >= maxHostAnswerersSize*2 {
+			return errTooManyConns
+		}
+	}
+	return nil
+}
+
+// checkHostOnline will check if there is some operator for all the given hosts that
+// claims to have an answerer online for that host.
+func (queue *redisWebRTCCallQueue) checkHostOnline(ctx context.Context, hosts ...string) error {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::checkHostOnline")
+	defer span.End()
+
+	for _, host := range hosts {
+		sizes, err := queue.hostQueueSizes(ctx, host)
+		if err != nil {
+			return err
+		}
+		if sizes.AnswererSize == 0 {
+			return ErrHostOffline
+		}
+	}
+	return nil
+}
+
+// HostStatuses returns the status of each of the given hosts, or of every host with answerers
+// connected if none are given, by summing answerers across the operators that are alive. Hosts
+// whose answerers all left keep their last heartbeat.
+func (queue *redisWebRTCCallQueue) HostStatuses(ctx context.Context, hosts ...string) ([]WebRTCHostStatus, error) {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::HostStatuses")
+	defer span.End()
+
+	onlineOnly := len(hosts) == 0
+	if onlineOnly {
+		var err error
+		hosts, err = queue.client.ZRangeByScore(ctx, redisOnlineHostsKey(), &redis.ZRangeBy{
+			Min: strconv.FormatInt(time.Now().UnixMilli(), 10),
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		slices.Sort(hosts)
+	}
+	statuses := make([]WebRTCHostStatus, 0, len(hosts))
+	if len(hosts) == 0 {
+		return statuses, nil
+	}
+
+	operatorsCmds := make([]*redis.MapStringStringCmd, 0, len(hosts))
+	heartbeatCmds := make([]*redis.MapStringStringCmd, 0, len(hosts))
+	if _, err := queue.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, host := range hosts {
+			operatorsCmds = append(operatorsCmds, pipe.HGetAll(ctx, redisHostOperatorsKey(host)))
+			heartbeatCmds = append(heartbeatCmds, pipe.HGetAll(ctx, redisHostHeartbeatKey(host)))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for i, host := range hosts {
+		sizes, err := sumHostQueueSizes(operatorsCmds[i].Val())
+		if err != nil {
+			return nil, err
+		}
+		if onlineOnly && sizes.AnswererSize == 0 {
+			continue
+		}
+		status := WebRTCHostStatus{Host: host, Answerers: int(sizes.AnswererSize)}
+		heartbeat := heartbeatCmds[i].Val()
+		if lastHeartbeat, err := strconv.ParseInt(heartbeat[redisHostLastHeartbeatField], 10, 64); err == nil {
+			status.LastHeartbeat = time.UnixMilli(lastHeartbeat)
+			status.AnswererVersion = heartbeat[redisHostAnswererVersionField]
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// appendCallEvent appends an event to the given stream of the call. It returns an inactive
+// offer error if the call does not exist anymore or does not pass the host/guard checks.
+func (queue *redisWebRTCCallQueue) appendCallEvent(
+	ctx context.Context,
+	callID, streamKey, host, stateField, stateValue, guardField string,
+	event ...string,
+) error {
+	args := make([]interface{}, 0, 4+len(event))
+	args = append(args, host, stateField, stateValue, guardField)
+	for _, val := range event {
+		args = append(args, val)
+	}
+	appended, err := redisAppendCallEventScript.Run(ctx, queue.client, []string{redisCallKey(callID), streamKey}, args...).Int()
+	if err != nil {
+		return err
+	}
+	if appended == 0 {
+		return newInactiveOfferErr(callID)
+	}
+	return nil
+}
+
+// readCallEvents blocks for a short while waiting for new events on the given stream after
+// the last ID seen, which is updated. No events and no error are returned if none came in time.
+func (queue *redisWebRTCCallQueue) readCallEvents(ctx context.Context, streamKey string, lastID *string) ([]redis.XMessage, error) {
+	streams, err := queue.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{streamKey, *lastID},
+		Block:   redisBlockInterval,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	var msgs []redis.XMessage
+	for _, stream := range streams {
+		msgs = append(msgs, stream.Messages...)
+	}
+	if len(msgs) != 0 {
+		*lastID = msgs[len(msgs)-1].ID
+	}
+	return msgs, nil
+}
+
+// SendOfferInit initializes an offer associated with the given SDP to the given host.
+// It returns a UUID to track/authenticate the offer over time, the initial SDP for the
+// sender to start its peer connection with, as well as a channel to receive candidates on
+// over time.
+func (queue *redisWebRTCCallQueue) SendOfferInit(
+	ctx context.Context,
+	host, sdp string,
+	disableTrickle bool,
+) (string, <-chan WebRTCCallAnswer, <-chan struct{}, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::SendOfferInit")
+	defer span.End()
+
+	if err := queue.checkHostQueueSize(ctx, true, host); err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	if err := queue.checkHostOnline(ctx, host); err != nil {
+		// See the MongoDB queue for why we delay here instead of erroring instantly.
+		select {
+		case <-time.After(offlineHostRetryDelay):
+			return "", nil, nil, nil, err
+		case <-ctx.Done():
+			return "", nil, nil, nil, ctx.Err()
+		}
+	}
+
+	startedAt := time.Now()
+	offerDeadline := startedAt.Add(getDefaultOfferDeadline())
+	traceContext := callTraceContext(ctx)
+	call := redisWebRTCCall{
+		ID:               uuid.NewString(),
+		CallerOperatorID: queue.operatorID,
+		Host:             host,
+		StartedAt:        startedAt.UnixMilli(),
+		ExpireAt:         offerDeadline.UnixMilli(),
+		CallerSDP:        sdp,
+		DisableTrickle:   disableTrickle,
+		TraceParent:      traceContext["traceparent"],
+		TraceState:       traceContext["tracestate"],
+	}
+
+	sendCtx, sendCtxCancel := context.WithDeadline(ctx, offerDeadline)
+	sendAndQueueCtx, sendAndQueueCtxCancel := utils.MergeContext(sendCtx, queue.cancelCtx)
+	untrack := queue.trackHosts(true, host)
+
+	cleanup := func() {
+		sendAndQueueCtxCancel()
+		sendCtxCancel()
+		untrack()
+	}
+	var successful bool
+	defer func() {
+		if successful {
+			return
+		}
+		cleanup()
+	}()
+
+	callKey := redisCallKey(call.ID)
+	if _, err := queue.client.TxPipelined(sendAndQueueCtx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(sendAndQueueCtx, callKey, call)
+		pipe.PExpireAt(sendAndQueueCtx, callKey, offerDeadline)
+		return nil
+	}); err != nil {
+		return "", nil, nil, nil, err
+	}
+	if err := queue.client.XAdd(sendAndQueueCtx, &redis.XAddArgs{
+		Stream: redisHostOffersKey(host, clampWebRTCCallPriority(ContextWebRTCCallPriority(ctx))),
+		MaxLen: redisHostOffersMaxLen,
+		Approx: true,
+		Values: []string{redisOfferCallIDField, call.ID},
+	}).Err(); err != nil {
+		return "", nil, nil, nil, err
+	}
+	// answerers also look for offers on their own every so often, so there is no need to fail
+	// over this.
+	if err := queue.client.Publish(sendAndQueueCtx, redisHostOffersChannel(host), call.ID).Err(); err != nil {
+		queue.logger.Debugw("failed to announce offer", "host", host, "error", err)
+	}
+
+	answererResponses := make(chan WebRTCCallAnswer, 1)
+	sendAnswer := func(answer WebRTCCallAnswer) bool {
+		select {
+		case <-sendAndQueueCtx.Done():
+			// try once more
+			select {
+			case answererResponses <- answer:
+			default:
+			}
+			return false
+		case answererResponses <- answer:
+			return true
+		}
+	}
+	queue.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer queue.activeBackgroundWorkers.Done()
+		defer cleanup()
+		defer close(answererResponses)
+
+		eventsKey := redisCallAnswererEventsKey(call.ID)
+		lastID := "0"
+		for {
+			if sendAndQueueCtx.Err() != nil {
+				sendAnswer(WebRTCCallAnswer{Err: sendAndQueueCtx.Err()})
+				return
+			}
+			events, err := queue.readCallEvents(sendAndQueueCtx, eventsKey, &lastID)
+			if err != nil {
+				sendAnswer(WebRTCCallAnswer{Err: err})
+				return
+			}
+			for _, event := range events {
+				switch event.Values[redisEventTypeField] {
+				case redisEventTypeSDP:
+					answererSDP, _ := event.Values[redisEventSDPField].(string)
+					if !sendAnswer(WebRTCCallAnswer{InitialSDP: &answererSDP}) {
+						return
+					}
+				case redisEventTypeCandidate:
+					cand, err := iceCandidateFromRedis(event)
+					if err != nil {
+						sendAnswer(WebRTCCallAnswer{Err: err})
+						return
+					}
+					if !sendAnswer(WebRTCCallAnswer{Candidate: &cand}) {
+						return
+					}
+				case redisEventTypeError:
+					answererErr, _ := event.Values[redisEventErrorField].(string)
+					sendAnswer(WebRTCCallAnswer{Err: errors.New(answererErr)})
+					return
+				case redisEventTypeDone:
+					return
+				}
+			}
+		}
+	})
+	successful = true
+	return call.ID, answererResponses, sendAndQueueCtx.Done(), sendAndQueueCtxCancel, nil
+}
+
+// SendOfferUpdate updates the offer associated with the given UUID with a newly discovered
+// ICE candidate.
+func (queue *redisWebRTCCallQueue) SendOfferUpdate(ctx context.Context, host, uuid string, candidate webrtc.ICECandidateInit) error {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::SendOfferUpdate")
+	defer span.End()
+
+	encodedCand, err := json.Marshal(candidate)
+	if err != nil {
+		return err
+	}
+	return queue.appendCallEvent(ctx, uuid, redisCallCallerEventsKey(uuid), host, "", "", "",
+		redisEventTypeField, redisEventTypeCandidate, redisEventCandidateField, string(encodedCand))
+}
+
+// SendOfferDone informs the queue that the offer associated with the UUID is done sending any
+// more information.
+func (queue *redisWebRTCCallQueue) SendOfferDone(ctx context.Context, host, uuid string) error {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::SendOfferDone")
+	defer span.End()
+
+	return queue.appendCallEvent(ctx, uuid, redisCallCallerEventsKey(uuid), host, redisCallCallerDoneField, "1", "",
+		redisEventTypeField, redisEventTypeDone)
+}
+
+// SendOfferError informs the queue that the offer associated with the UUID has encountered
+// an error.
+func (queue *redisWebRTCCallQueue) SendOfferError(ctx context.Context, host, uuid string, err error) error {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::SendOfferError")
+	defer span.End()
+
+	return queue.appendCallEvent(ctx, uuid, redisCallCallerEventsKey(uuid), host, redisCallCallerErrorField, err.Error(),
+		redisCallCallerDoneField, redisEventTypeField, redisEventTypeError, redisEventErrorField, err.Error())
+}
+
+// ensureOfferGroups makes sure the consumer group answerers read offers through exists for
+// each priority class of each of the given hosts.
+func (queue *redisWebRTCCallQueue) ensureOfferGroups(ctx context.Context, hosts []string) error {
+	for _, host := range hosts {
+		for _, priority := range webrtcCallPriorities {
+			err := queue.client.XGroupCreateMkStream(ctx, redisHostOffersKey(host, priority), redisWebRTCCallQueueOffersGrp, "0").Err()
+			if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RecvOffer receives the next offer for the given host. It should respond with an answer
+// once a decision is made.
+func (queue *redisWebRTCCallQueue) RecvOffer(ctx context.Context, hosts []string) (WebRTCCallOfferExchange, error) {
+	ctx, span := trace.StartSpan(ctx, "CallQueue::RecvOffer")
+	defer span.End()
+
+	if len(hosts) > 0 {
+		span.AddAttributes(trace.StringAttribute("host", hosts[0]))
+	}
+
+	if err := queue.checkHostQueueSize(ctx, false, hosts...); err != nil {
+		return nil, err
+	}
+
+	recvOfferCtx, recvOfferCtxCancel := utils.MergeContext(ctx, queue.cancelCtx)
+	defer recvOfferCtxCancel()
+
+	untrack := queue.trackHosts(false, hosts...)
+	var successful bool
+	defer func() {
+		if successful {
+			return
+		}
+		untrack()
+	}()
+
+	presence, ok := ContextWebRTCAnswererPresence(ctx)
+	if !ok {
+		presence = NewWebRTCAnswererPresence("")
+	}
+	untrackPresence := queue.trackPresence(presence, hosts...)
+	defer untrackPresence()
+
+	if err := queue.ensureOfferGroups(recvOfferCtx, hosts); err != nil {
+		return nil, err
+	}
+	// start waiting before looking so that no offer sent in between is missed.
+	offered, stopWaiting := queue.waitForOffers(hosts)
+	defer stopWaiting()
+
+	var callReq redisWebRTCCall
+	for {
+		call, ok, err := queue.takeOffer(recvOfferCtx, hosts)
+		if err != nil {
+			if recvOfferCtx.Err() != nil {
+				return nil, recvOfferCtx.Err()
+			}
+			if !strings.HasPrefix(err.Error(), "NOGROUP") {
+				return nil, err
+			}
+			// the offers stream was removed out from under us; recreate it.
+			if err := queue.ensureOfferGroups(recvOfferCtx, hosts); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if ok {
+			callReq = call
+			break
+		}
+		select {
+		case <-recvOfferCtx.Done():
+			return nil, recvOfferCtx.Err()
+		case <-offered:
+		case <-time.After(redisBlockInterval):
+		}
+	}
+
+	offerDeadline := time.UnixMilli(callReq.StartedAt).Add(getDefaultOfferDeadline())
+	recvCtx, recvCtxCancel := utils.MergeContextWithDeadline(ctx, queue.cancelCtx, offerDeadline)
+
+	callerDoneCtx, callerDoneCancel := context.WithCancel(context.Background())
+	exchange := redisWebRTCCallOfferExchange{
+		call:             callReq,
+		queue:            queue,
+		callerCandidates: make(chan webrtc.ICECandidateInit),
+		callerDoneCtx:    callerDoneCtx,
+		deadline:         offerDeadline,
+	}
+	sendCandidate := func(cand webrtc.ICECandidateInit) bool {
+		select {
+		case <-recvCtx.Done():
+			// try once more
+			select {
+			case exchange.callerCandidates <- cand:
+			default:
+			}
+			return false
+		case exchange.callerCandidates <- cand:
+			return true
+		}
+	}
+	queue.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer queue.activeBackgroundWorkers.Done()
+		defer callerDoneCancel()
+		defer untrack()
+		defer recvCtxCancel()
+
+		eventsKey := redisCallCallerEventsKey(callReq.ID)
+		lastID := "0"
+		for {
+			if err := recvCtx.Err(); err != nil {
+				return
+			}
+			events, err := queue.readCallEvents(recvCtx, eventsKey, &lastID)
+			if err != nil {
+				if recvCtx.Err() == nil {
+					exchange.callerErr = err
+				}
+				return
+			}
+			for _, event := range events {
+				switch event.Values[redisEventTypeField] {
+				case redisEventTypeCandidate:
+					cand, err := iceCandidateFromRedis(event)
+					if err != nil {
+						exchange.callerErr = err
+						return
+					}
+					if !sendCandidate(cand) {
+						return
+					}
+				case redisEventTypeError:
+					callerErr, _ := event.Values[redisEventErrorField].(string)
+					exchange.callerErr = errors.New(callerErr)
+					return
+				case redisEventTypeDone:
+					return
+				}
+			}
+		}
+	})
+	successful = true
+	return &exchange, nil
+}
+
+// takeOffer takes the oldest offer of the highest priority class across the given hosts that can
+// still be answered, if there is one. Streams are read one at a time, since those of different
+// hosts may be in different slots of a Redis Cluster, and only one entry is read at a time so that
+// no entry read is left for someone else; entries stay pending until they are claimed or found to
+// be no longer answerable, and those of answerers that died in between are taken over.
+func (queue *redisWebRTCCallQueue) takeOffer(ctx context.Context, hosts []string) (redisWebRTCCall, bool, error) {
+	for i := len(webrtcCallPriorities) - 1; i >= 0; i-- {
+		for _, host := range hosts {
+			stream := redisHostOffersKey(host, webrtcCallPriorities[i])
+			for {
+				msg, ok, err := queue.readOffer(ctx, stream)
+				if err != nil {
+					return redisWebRTCCall{}, false, err
+				}
+				if !ok {
+					break
+				}
+				call, claimed, err := queue.claimOffer(ctx, stream, msg)
+				if err != nil {
+					return redisWebRTCCall{}, false, err
+				}
+				if claimed {
+					return call, true, nil
+				}
+			}
+		}
+	}
+	return redisWebRTCCall{}, false, nil
+}
+
+// readOffer reads the next entry of the offer stream, preferring those another answerer read but
+// never claimed.
+func (queue *redisWebRTCCallQueue) readOffer(ctx context.Context, stream string) (redis.XMessage, bool, error) {
+	abandoned, _, err := queue.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    redisWebRTCCallQueueOffersGrp,
+		MinIdle:  redisOfferClaimMinIdle,
+		Start:    "0-0",
+		Count:    1,
+		Consumer: queue.operatorID,
+	}).Result()
+	if err != nil {
+		return redis.XMessage{}, false, err
+	}
+	if len(abandoned) != 0 {
+		return abandoned[0], true, nil
+	}
+
+	results, err := queue.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    redisWebRTCCallQueueOffersGrp,
+		Consumer: queue.operatorID,
+		Streams:  []string{stream, ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return redis.XMessage{}, false, nil
+		}
+		return redis.XMessage{}, false, err
+	}
+	for _, result := range results {
+		if len(result.Messages) != 0 {
+			return result.Messages[0], true, nil
+		}
+	}
+	return redis.XMessage{}, false, nil
+}
+
+// claimOffer claims the call of the offer entry for this operator if it can still be answered. The
+// entry is acknowledged either way, unless claiming failed, in which case it stays pending for
+// this or another answerer to take over.
+func (queue *redisWebRTCCallQueue) claimOffer(ctx context.Context, stream string, msg redis.XMessage) (redisWebRTCCall, bool, error) {
+	// We would like to answer all calls within their deadline but there is some amount of time required to
+	// connect. That estimated time to connect is subtracted off the window so that we do not grab any
+	// offers that are about to expire. See the MongoDB queue for a diagram.
+	startedAtWindow := time.Now().Add(-getDefaultOfferDeadline()).Add(getDefaultOfferCloseToDeadline())
+	callID, _ := msg.Values[redisOfferCallIDField].(string)
+	claimed, err := redisClaimCallScript.Run(ctx, queue.client, []string{redisCallKey(callID)},
+		queue.operatorID, startedAtWindow.UnixMilli()).Int()
+	if err != nil {
+		return redisWebRTCCall{}, false, err
+	}
+	var call redisWebRTCCall
+	if claimed == 1 {
+		if err := queue.client.HGetAll(ctx, redisCallKey(callID)).Scan(&call); err != nil {
+			return redisWebRTCCall{}, false, err
+		}
+		call.ID = callID
+	}
+	// an entry left pending is taken over later on and then found to be claimed already.
+	if err := queue.client.XAck(ctx, stream, redisWebRTCCallQueueOffersGrp, msg.ID).Err(); err != nil {
+		queue.logger.Debugw("failed to acknowledge offer", "call_id", callID, "error", err)
+	}
+	// someone else took it or it is no longer valid if not claimed.
+	return call, claimed == 1, nil
+}
+
+func iceCandidateFromRedis(event redis.XMessage) (webrtc.ICECandidateInit, error) {
+	var cand webrtc.ICECandidateInit
+	encoded, _ := event.Values[redisEventCandidateField].(string)
+	if err := json.Unmarshal([]byte(encoded), &cand); err != nil {
+		return webrtc.ICECandidateInit{}, errors.Wrap(err, "failed to decode ICE candidate")
+	}
+	return cand, nil
+}
+
+// Close cancels all active offers and waits to cleanly close all background workers.
+func (queue *redisWebRTCCallQueue) Close() error {
+	queue.cancelFunc()
+	queue.activeBackgroundWorkers.Wait()
+	return nil
+}
+
+// waitForAnswererOnline blocks until there is at least one answerer online for all the given hosts.
+// Used in testing to synchronize callers and answerers so that call attempts don't immediately fail
+// due to answerers not yet registered as being online.
+func (queue *redisWebRTCCallQueue) waitForAnswererOnline(ctx context.Context, hosts []string) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timeoutCtx.Done():
+			return timeoutCtx.Err()
+		case <-ticker.C:
+		}
+
+		if err := queue.checkHostOnline(timeoutCtx, hosts...); err == nil {
+			return nil
+		} else if !errors.Is(err, ErrHostOffline) {
+			return err
+		}
+	}
+}
+
+type redisWebRTCCallOfferExchange struct {
+	call             redisWebRTCCall
+	queue            *redisWebRTCCallQueue
+	callerCandidates chan webrtc.ICECandidateInit
+	callerDoneCtx    context.Context
+	callerErr        error
+	deadline         time.Time
+}
+
+func (resp *redisWebRTCCallOfferExchange) UUID() string {
+	return resp.call.ID
+}
+
+func (resp *redisWebRTCCallOfferExchange) SDP() string {
+	return resp.call.CallerSDP
+}
+
+func (resp *redisWebRTCCallOfferExchange) DisableTrickleICE() bool {
+	return resp.call.DisableTrickle
+}
+
+func (resp *redisWebRTCCallOfferExchange) Deadline() time.Time {
+	return resp.deadline
+}
+
+func (resp *redisWebRTCCallOfferExchange) TraceContext() map[string]string {
+	if resp.call.TraceParent == "" {
+		return nil
+	}
+	traceContext := map[string]string{"traceparent": resp.call.TraceParent}
+	if resp.call.TraceState != "" {
+		traceContext["tracestate"] = resp.call.TraceState
+	}
+	return traceContext
+}
+
+func (resp *redisWebRTCCallOfferExchange) CallerCandidates() <-chan webrtc.ICECandidateInit {
+	return resp.callerCandidates
+}
+
+func (resp *redisWebRTCCallOfferExchange) CallerDone() <-chan struct{} {
+	return resp.callerDoneCtx.Done()
+}
+
+func (resp *redisWebRTCCallOfferExchange) CallerErr() error {
+	if resp.callerDoneCtx.Err() == nil {
+		return nil
+	}
+	if resp.callerErr != nil {
+		return resp.callerErr
+	}
+	if errors.Is(resp.callerDoneCtx.Err(), context.Canceled) {
+		return nil
+	}
+	return resp.callerDoneCtx.Err()
+}
+
+func (resp *redisWebRTCCallOfferExchange) AnswererRespond(ctx context.Context, ans WebRTCCallAnswer) error {
+	ctx, span := trace.StartSpan(ctx, "CallOfferExchange::AnswererRespond")
+	defer span.End()
+
+	eventsKey := redisCallAnswererEventsKey(resp.call.ID)
+	switch {
+	case ans.InitialSDP != nil:
+		return resp.queue.appendCallEvent(ctx, resp.call.ID, eventsKey, "", "", "", "",
+			redisEventTypeField, redisEventTypeSDP, redisEventSDPField, *ans.InitialSDP)
+	case ans.Candidate != nil:
+		encodedCand, err := json.Marshal(ans.Candidate)
+		if err != nil {
+			return err
+		}
+		return resp.queue.appendCallEvent(ctx, resp.call.ID, eventsKey, "", "", "", "",
+			redisEventTypeField, redisEventTypeCandidate, redisEventCandidateField, string(encodedCand))
+	case ans.Err != nil:
+		return resp.queue.appendCallEvent(ctx, resp.call.ID, eventsKey, "", redisCallAnswererErrorField, ans.Err.Error(), "",
+			redisEventTypeField, redisEventTypeError, redisEventErrorField, ans.Err.Error())
+	default:
+		return errors.New("expected either SDP, ICE candidate, or error to be set")
+	}
+}
+
+func (resp *redisWebRTCCallOfferExchange) AnswererDone(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "CallOfferExchange::AnswererDone")
+	defer span.End()
+
+	return resp.queue.appendCallEvent(ctx, resp.call.ID, redisCallAnswererEventsKey(resp.call.ID), resp.call.Host,
+		redisCallAnswererDoneField, "1", "", redisEventTypeField, redisEventTypeDone)
+}
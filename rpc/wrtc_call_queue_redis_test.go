@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.viam.com/test"
+
+	"go.viam.com/utils/testutils"
+)
+
+func TestRedisWebRTCCallQueueAbandonedOffers(t *testing.T) {
+	client := testutils.BackingRedisClient(t)
+	test.That(t, client.FlushDB(context.Background()).Err(), test.ShouldBeNil)
+	logger := golog.NewTestLogger(t)
+
+	prevMinIdle := redisOfferClaimMinIdle
+	redisOfferClaimMinIdle = 100 * time.Millisecond
+	defer func() {
+		redisOfferClaimMinIdle = prevMinIdle
+	}()
+
+	queue, err := NewRedisWebRTCCallQueue(context.Background(), uuid.NewString(), 50, client, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, queue.Close(), test.ShouldBeNil)
+	}()
+
+	// keep the host online without an answerer waiting on it.
+	host := uuid.NewString()
+	redisQueue := queue.(*redisWebRTCCallQueue)
+	test.That(t, redisQueue.ensureOfferGroups(context.Background(), []string{host}), test.ShouldBeNil)
+	untrack := redisQueue.trackHosts(false, host)
+	defer untrack()
+	test.That(t, redisQueue.waitForAnswererOnline(context.Background(), []string{host}), test.ShouldBeNil)
+
+	_, _, firstDone, firstCancel, err := queue.SendOfferInit(context.Background(), host, "first", false)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		firstCancel()
+		<-firstDone
+	}()
+
+	// an answerer reads the offer and dies before claiming it.
+	results, err := client.XReadGroup(context.Background(), &redis.XReadGroupArgs{
+		Group:    redisWebRTCCallQueueOffersGrp,
+		Consumer: "dead",
+		Streams:  []string{redisHostOffersKey(host, WebRTCCallPriorityNormal), ">"},
+		Count:    1,
+		Block:    -1,
+	}).Result()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, results, test.ShouldHaveLength, 1)
+	test.That(t, results[0].Messages, test.ShouldHaveLength, 1)
+
+	_, _, secondDone, secondCancel, err := queue.SendOfferInit(context.Background(), host, "second", false)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		secondCancel()
+		<-secondDone
+	}()
+
+	// the abandoned offer is taken over once idle long enough and still comes first.
+	time.Sleep(2 * redisOfferClaimMinIdle)
+	for _, sdp := range []string{"first", "second"} {
+		offer, err := queue.RecvOffer(context.Background(), []string{host})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, offer.SDP(), test.ShouldEqual, sdp)
+		test.That(t, offer.AnswererDone(context.Background()), test.ShouldBeNil)
+	}
+
+	pending, err := client.XPending(context.Background(), redisHostOffersKey(host, WebRTCCallPriorityNormal),
+		redisWebRTCCallQueueOffersGrp).Result()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pending.Count, test.ShouldEqual, 0)
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/trace"
+)
+
+// callTracePropagator carries the trace of a call between its caller, the signaling server,
+// the call queue and the answerer so that they all report to a single trace.
+var callTracePropagator = propagation.TraceContext{}
+
+// callTraceContext returns the trace context of the span in the given context for sending
+// along with a call, or nil if there is none.
+func callTraceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	callTracePropagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// contextWithCallTrace returns a context continuing the trace a call was sent with, if any.
+func contextWithCallTrace(ctx context.Context, traceContext map[string]string) context.Context {
+	if len(traceContext) == 0 {
+		return ctx
+	}
+	return callTracePropagator.Extract(ctx, propagation.MapCarrier(traceContext))
+}
+
+// offerTraceContext returns the trace context the caller of the offer sent it with, if the
+// queue it came from keeps it.
+func offerTraceContext(offer WebRTCCallOffer) map[string]string {
+	if traced, ok := offer.(WebRTCTracedCallOffer); ok {
+		return traced.TraceContext()
+	}
+	return nil
+}
+
+// addCallEvent records that a call reached a stage.
+func addCallEvent(span trace.Span, name string, attrs ...attribute.KeyValue) {
+	span.AddEvent(name, oteltrace.WithAttributes(attrs...))
+}
+
+// endCallSpan ends a span of a call, marking it as failed if the call failed.
+func endCallSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// candidateEventAttributes describes an ICE candidate on a span event.
+func candidateEventAttributes(candidate *webrtcpb.ICECandidate) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("candidate", candidate.GetCandidate())}
+}
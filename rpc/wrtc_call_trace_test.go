@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/edaniels/golog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.viam.com/test"
+
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+	"go.viam.com/utils/trace"
+)
+
+func TestWebRTCCallTracing(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	test.That(t, trace.SetProvider(context.Background()), test.ShouldBeNil)
+	exporter := tracetest.NewInMemoryExporter()
+	trace.AddExporters(exporter)
+	defer func() {
+		trace.RemoveExporters(exporter)
+		test.That(t, trace.Shutdown(context.Background()), test.ShouldBeNil)
+	}()
+
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+		}),
+		WithUnauthenticated(),
+		WithDisableMulticastDNS(),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&echopb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		echopb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	rtcConn, err := DialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, logger,
+		WithInsecure(),
+		WithWebRTCOptions(DialWebRTCOptions{
+			SignalingInsecure: true,
+		}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rtcConn.Close(), test.ShouldBeNil)
+	}()
+	_, err = echopb.NewEchoServiceClient(rtcConn).Echo(context.Background(), &echopb.EchoRequest{Message: "hello"})
+	test.That(t, err, test.ShouldBeNil)
+
+	// every stage of the call, on each side, reports to the trace of the dial.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		provider, ok := trace.GetProvider().(*sdktrace.TracerProvider)
+		test.That(tb, ok, test.ShouldBeTrue)
+		test.That(tb, provider.ForceFlush(context.Background()), test.ShouldBeNil)
+
+		spans := map[string]tracetest.SpanStub{}
+		spanCounts := map[string]int{}
+		for _, span := range exporter.GetSpans() {
+			spans[span.Name] = span
+			spanCounts[span.Name]++
+		}
+		// the signaling server starts a single span for each side of the call.
+		test.That(tb, spanCounts["SignalingServer::Call"], test.ShouldEqual, 1)
+		test.That(tb, spanCounts["SignalingServer::Answer"], test.ShouldEqual, 1)
+		for _, name := range []string{
+			"WebRTC::Dial",
+			"SignalingServer::Call",
+			"SignalingServer::CallUpdate",
+			"SignalingServer::Answer",
+			"Answerer::connect",
+		} {
+			test.That(tb, spans, test.ShouldContainKey, name)
+			test.That(tb, spans[name].SpanContext.TraceID(), test.ShouldEqual, spans["WebRTC::Dial"].SpanContext.TraceID())
+		}
+		test.That(tb, spans["SignalingServer::Call"].Parent.SpanID(), test.ShouldEqual,
+			spans["WebRTC::Dial"].SpanContext.SpanID())
+		test.That(tb, spans["SignalingServer::Answer"].Parent.SpanID(), test.ShouldEqual,
+			spans["SignalingServer::Call"].SpanContext.SpanID())
+		test.That(tb, spans["Answerer::connect"].Parent.SpanID(), test.ShouldEqual,
+			spans["SignalingServer::Answer"].SpanContext.SpanID())
+
+		var dialEvents []string
+		for _, event := range spans["WebRTC::Dial"].Events {
+			dialEvents = append(dialEvents, event.Name)
+		}
+		test.That(tb, dialEvents, test.ShouldContain, "offer sent")
+		test.That(tb, dialEvents, test.ShouldContain, "answer received")
+		test.That(tb, dialEvents, test.ShouldContain, "connected")
+		test.That(tb, dialEvents[len(dialEvents)-1], test.ShouldEqual, "first rpc")
+	})
+}
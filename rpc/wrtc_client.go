@@ -2,6 +2,8 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"slices"
 	"sync"
@@ -11,12 +13,12 @@ import (
 	"github.com/pion/stun"
 	"github.com/pkg/errors"
 	"github.com/viamrobotics/webrtc/v3"
-	"google.golang.org/grpc/codes"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 
 	"go.viam.com/utils"
 	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/trace"
 )
 
 // ErrNoWebRTCSignaler happens if a gRPC request is made on a server that does not support
@@ -35,6 +37,20 @@ type DialWebRTCOptions struct {
 	// contact on behalf of this client for WebRTC communications.
 	SignalingServerAddress string
 
+	// SignalingServerAddresses are more signaling servers to try when SignalingServerAddress,
+	// or the address being dialed if it is unset, cannot be reached. They are all expected to
+	// accept the same signaling credentials.
+	SignalingServerAddresses []string
+
+	// SignalingServerSRV is a DNS SRV name (e.g. _signaling._tcp.example.com) whose targets are
+	// tried after SignalingServerAddresses, in order of priority and weight.
+	SignalingServerSRV string
+
+	// SignalingHedgeDelay is how long to wait on a signaling server before also trying the next
+	// one when there are several. Servers that could not be reached recently are tried last.
+	// Zero waits a second.
+	SignalingHedgeDelay time.Duration
+
 	// SignalingAuthEntity is the entity to authenticate as to the signaler.
 	SignalingAuthEntity string
 
@@ -96,6 +112,48 @@ type DialWebRTCOptions struct {
 	// AllowAutoDetectAuthOptions allows authentication options to be automatically
 	// detected. Only use this if you trust the signaling server.
 	AllowAutoDetectAuthOptions bool
+
+	// DataChannelPoolSize is the maximum number of additional data channels, opened by servers
+	// with WebRTCServerOptions.DataChannelPoolSize set, to spread streams across. By default
+	// unary RPCs use the primary data channel and streaming RPCs are spread round-robin across
+	// the pool, so that one busy stream does not hold up others. Zero, or a server that opens
+	// no pool, sends everything over the primary data channel. A non-zero pool size takes over
+	// the OnDataChannel handler of the peer connection; replacing it through PeerConn stops
+	// pooled data channels from being accepted.
+	DataChannelPoolSize int
+
+	// DataChannelAssigner, if set, overrides how streams are assigned to pooled data channels.
+	DataChannelAssigner DataChannelAssigner
+
+	// InitialWindowSize is how many bytes of response messages a server may send on a stream
+	// before waiting for the client to read them. Zero uses a 1 MiB window.
+	InitialWindowSize int32
+
+	// InitialConnWindowSize is how many bytes of response messages a server may send across
+	// all streams of the connection before waiting for the client to read them. Zero uses a
+	// 4 MiB window.
+	InitialConnWindowSize int32
+
+	// KeepaliveInterval, when positive, is how often the client pings the server. When the
+	// server stops answering pings, the connection is closed rather than waiting for ICE to
+	// notice. Servers that do not support pings are never considered to have stopped answering.
+	KeepaliveInterval time.Duration
+
+	// KeepaliveMissedPings is how many pings in a row may go unanswered before the connection
+	// is closed. Zero allows 3.
+	KeepaliveMissedPings int
+
+	// ICERestartTimeout, when positive, keeps the connection open for this long after it
+	// disconnects while ICE is restarted with new candidates through the signaling server. The
+	// connection is closed if it has not recovered by then. The server must also set
+	// WebRTCServerOptions.ICERestartTimeout. By default disconnected connections are closed
+	// right away.
+	ICERestartTimeout time.Duration
+
+	// CallPriority is the priority class asked for the call to the host. Signaling servers
+	// decide the priority class of calls themselves and, by default, grant at most
+	// WebRTCCallPriorityNormal, the zero value.
+	CallPriority WebRTCCallPriority
 }
 
 // DialWebRTC connects to the signaling service at the given address and attempts to establish
@@ -124,48 +182,74 @@ func dialWebRTC(
 	host string,
 	dOpts dialOptions,
 	logger utils.ZapCompatibleLogger,
+) (*webrtcClientChannel, error) {
+	// The dial is traced until the first RPC over the connection finishes. The signaling server,
+	// call queue and answerer continue the trace so that it covers the whole exchange.
+	ctx, dialSpan := trace.StartSpan(ctx, "WebRTC::Dial")
+	dialSpan.SetAttributes(attribute.String("host", host))
+	clientCh, err := dialWebRTCTraced(ctx, dialSpan, signalingServer, host, dOpts, logger)
+	if err != nil {
+		endCallSpan(dialSpan, err)
+		return nil, err
+	}
+	clientCh.dialSpan = dialSpan
+	return clientCh, nil
+}
+
+func dialWebRTCTraced(
+	ctx context.Context,
+	dialSpan trace.Span,
+	signalingServer string,
+	host string,
+	dOpts dialOptions,
+	logger utils.ZapCompatibleLogger,
 ) (*webrtcClientChannel, error) {
 	dialStart := time.Now()
 
 	dialCtx, timeoutCancel := context.WithTimeout(ctx, getDefaultOfferDeadline())
 	defer timeoutCancel()
 
+	signalingServers := dOpts.webrtcOpts.signalingServers(dialCtx, signalingServer, logger)
 	logger.Debugw(
 		"connecting to signaling server",
-		"signaling_server", signalingServer,
+		"signaling_server", signalingServers,
 		"host", host,
 	)
 
-	conn, err := dialSignalingServer(dialCtx, signalingServer, host, logger, dOpts)
+	signalingConn, err := connectSignalingServers(dialCtx, signalingServers, host, logger, dOpts)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		// Ignore any errors closing the signaling server connection. That step has no bearing on
 		// whether the PeerConnection was successfully made.
-		utils.UncheckedError(conn.Close())
+		utils.UncheckedError(signalingConn.conn.Close())
 	}()
 
-	logger.Debugw("connected to signaling server", "signaling_server", signalingServer)
+	logger.Debugw("connected to signaling server", "signaling_server", signalingConn.server)
+	addCallEvent(dialSpan, "signaling server connected", attribute.String("signaling_server", signalingConn.server))
+	traceContext := callTraceContext(ctx)
 
 	md := metadata.New(map[string]string{RPCHostMetadataField: host})
+	if dOpts.webrtcOpts.CallPriority != WebRTCCallPriorityNormal {
+		md.Set(CallPriorityMetadataField, dOpts.webrtcOpts.CallPriority.String())
+	}
 	signalCtx := metadata.NewOutgoingContext(dialCtx, md)
 
-	signalingClient := webrtcpb.NewSignalingServiceClient(conn)
-	configResp, err := signalingClient.OptionalWebRTCConfig(signalCtx, &webrtcpb.OptionalWebRTCConfigRequest{})
-	if err != nil {
-		// this would be where we would hit an unimplemented signaler error first.
-		if s, ok := status.FromError(err); ok && (s.Code() == codes.Unimplemented ||
-			(s.Code() == codes.InvalidArgument && s.Message() == hostNotAllowedMsg)) {
-			return nil, ErrNoWebRTCSignaler
-		}
-		return nil, err
-	}
+	signalingClient := webrtcpb.NewSignalingServiceClient(signalingConn.conn)
+	configResp := signalingConn.config
 
 	config := DefaultWebRTCConfiguration
 	if dOpts.webrtcOpts.Config != nil {
 		config = *dOpts.webrtcOpts.Config
 	}
+	if dOpts.clientCert != nil {
+		cert, err := webrtcCertificate(*dOpts.clientCert)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []webrtc.Certificate{cert}
+	}
 
 	if dOpts.webrtcOpts.ForceRelay && dOpts.webrtcOpts.ForceP2P {
 		logger.Warnw("forceRelay and forceP2P are both set; forceP2P strips TURN servers that forceRelay requires so the connection will fail")
@@ -267,6 +351,15 @@ func dialWebRTC(
 		utils.Sublogger(logger, "client"),
 		dOpts.unaryInterceptor,
 		dOpts.streamInterceptor)
+	clientCh.compressor = dOpts.compressor
+	clientCh.configureFlowControl(dOpts.webrtcOpts.InitialWindowSize, dOpts.webrtcOpts.InitialConnWindowSize)
+	clientCh.startKeepalive(dOpts.webrtcOpts.KeepaliveInterval, dOpts.webrtcOpts.KeepaliveMissedPings, clientCh.writePing)
+	clientCh.enableICERestart(dOpts.webrtcOpts.ICERestartTimeout, func(ctx context.Context) error {
+		return restartICE(ctx, peerConn, signalingServer, host, dOpts, logger)
+	})
+	if dOpts.webrtcOpts.DataChannelPoolSize > 0 {
+		clientCh.acceptDataChannelPool(dOpts.webrtcOpts.DataChannelPoolSize, dOpts.webrtcOpts.DataChannelAssigner)
+	}
 
 	var successful bool
 	defer func() {
@@ -292,6 +385,7 @@ func dialWebRTC(
 				Update: &webrtcpb.CallUpdateRequest_Done{
 					Done: true,
 				},
+				TraceContext: traceContext,
 			}); err != nil {
 				logger.Warnw("Error sending CallUpdate", "err", err)
 			}
@@ -358,12 +452,14 @@ func dialWebRTC(
 				}
 
 				iProto := iceCandidateToProto(icecandidate)
+				addCallEvent(dialSpan, "caller candidate", candidateEventAttributes(iProto)...)
 				callUpdateStart := time.Now()
 				if _, err := signalingClient.CallUpdate(exchangeCtx, &webrtcpb.CallUpdateRequest{
 					Uuid: uuid,
 					Update: &webrtcpb.CallUpdateRequest_Candidate{
 						Candidate: iProto,
 					},
+					TraceContext: traceContext,
 				}); err != nil {
 					logger.Warnw("Error sending a CallUpdate", "err", err)
 					return
@@ -400,11 +496,12 @@ func dialWebRTC(
 		return nil, err
 	}
 
-	callClient, err := signalingClient.Call(signalCtx, &webrtcpb.CallRequest{Sdp: encodedSDP})
+	callClient, err := signalingClient.Call(signalCtx, &webrtcpb.CallRequest{Sdp: encodedSDP, TraceContext: traceContext})
 	if err != nil {
 		logger.Errorw("Error calling with initial SDP", "err", err)
 		return nil, err
 	}
+	addCallEvent(dialSpan, "offer sent")
 
 	// TODO(RSDK-245): do separate auth here
 	if dOpts.externalAuthAddr != "" { //nolint:revive
@@ -439,6 +536,7 @@ func dialWebRTC(
 				}
 				haveInit = true
 				uuid = callResp.GetUuid()
+				addCallEvent(dialSpan, "answer received", attribute.String("uuid", uuid))
 				answer := webrtc.SessionDescription{}
 				if err := DecodeSDP(s.Init.GetSdp(), &answer); err != nil {
 					return err
@@ -461,6 +559,7 @@ func dialWebRTC(
 				if callResp.GetUuid() != uuid {
 					return errors.Errorf("uuid mismatch; have=%q want=%q", callResp.GetUuid(), uuid)
 				}
+				addCallEvent(dialSpan, "answerer candidate", candidateEventAttributes(s.Update.GetCandidate())...)
 				cand := iceCandidateFromProto(s.Update.GetCandidate())
 				if err := peerConn.AddICECandidate(cand); err != nil {
 					// A PeerConnection only needs one valid candidate to succeed. It's unclear why
@@ -486,6 +585,7 @@ func dialWebRTC(
 		// Happy path
 		sendDone()
 		successful = true
+		addCallEvent(dialSpan, "connected")
 
 		// Ensure the exchange goroutine has exited.
 		exchangeCancel(nil)
@@ -498,6 +598,7 @@ func dialWebRTC(
 				Update: &webrtcpb.CallUpdateRequest_Error{
 					Error: ErrorToStatus(exchangeErr).Proto(),
 				},
+				TraceContext: traceContext,
 			}); err != nil {
 				logger.Debugw("Problem sending error to signaling server", "err", err)
 			}
@@ -556,3 +657,19 @@ func iceServerHasTURN(s webrtc.ICEServer) bool {
 	}
 	return false
 }
+
+// webrtcCertificate makes a DTLS certificate for a peer connection out of a TLS certificate.
+func webrtcCertificate(tlsCert tls.Certificate) (webrtc.Certificate, error) {
+	if len(tlsCert.Certificate) == 0 {
+		return webrtc.Certificate{}, errors.New("client certificate is empty")
+	}
+	leaf := tlsCert.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(tlsCert.Certificate[0])
+		if err != nil {
+			return webrtc.Certificate{}, errors.Wrap(err, "failed to parse client certificate")
+		}
+	}
+	return webrtc.CertificateFromX509(tlsCert.PrivateKey, leaf), nil
+}
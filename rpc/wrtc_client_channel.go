@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"math"
 	"path"
 	"sync"
 	"sync/atomic"
@@ -9,13 +10,18 @@ import (
 
 	grpc_logging "github.com/grpc-ecosystem/go-grpc-middleware/logging"
 	"github.com/viamrobotics/webrtc/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.viam.com/utils"
 	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/trace"
 )
 
 // A webrtcClientChannel reflects the client end of a gRPC connection serviced over
@@ -27,8 +33,47 @@ type webrtcClientChannel struct {
 	streams           map[uint64]activeWebRTCClientStream
 	unaryInterceptor  grpc.UnaryClientInterceptor
 	streamInterceptor grpc.StreamClientInterceptor
+
+	// poolChannelIDs are the SCTP stream identifiers of the open pooled data channels, in the
+	// order they were opened. It is guarded by mu.
+	poolChannelIDs      []uint32
+	poolAssigner        DataChannelAssigner
+	poolStreamingCursor uint64
+
+	// compressor names the compressor calls use unless overridden by grpc.UseCompressor.
+	compressor string
+	// serverCompressors are the compressors the server has said it can decompress requests
+	// with. It is guarded by mu.
+	serverCompressors map[string]struct{}
+
+	// streamWindowSize and connWindowSize are the flow control windows granted to the server
+	// for response messages, per stream and per connection.
+	streamWindowSize int64
+	connWindowSize   int64
+	connRecvWindow   *recvWindow
+	// serverStreamWindowSize and connSendWindow are the flow control windows the server grants
+	// for request messages. They are unset until the server has said it supports flow control
+	// and are guarded by mu.
+	serverStreamWindowSize int64
+	connSendWindow         *sendWindow
+
+	// goingAway is set once the server has said it is shutting down. Streams already started
+	// keep going but new ones are refused.
+	goingAway atomic.Bool
+
+	// dialSpan is the span of the dial that made the channel. It is ended once the first RPC
+	// finishes so that the trace of the call covers it.
+	dialSpan        trace.Span
+	endDialSpanOnce sync.Once
 }
 
+// A DataChannelAssigner picks the data channel a new stream is sent over. It is given the
+// full method name, whether the stream is a streaming (as opposed to unary) RPC, and the
+// number of pooled data channels currently open. It returns 0 for the primary data channel
+// or i in [1, poolSize] for the i-th pooled data channel; out of range values fall back to
+// the primary data channel.
+type DataChannelAssigner func(method string, streaming bool, poolSize int) int
+
 type activeWebRTCClientStream struct {
 	cs *webrtcClientStream
 }
@@ -57,10 +102,72 @@ func newWebRTCClientChannel(
 		unaryInterceptor:  unaryInterceptor,
 		streamInterceptor: streamInterceptor,
 	}
+	ch.configureFlowControl(0, 0)
 	dataChannel.OnMessage(ch.onChannelMessage)
 	return ch
 }
 
+// configureFlowControl sets the flow control windows granted to the server, where zero uses
+// the default. It must be called before any streams are made.
+func (ch *webrtcClientChannel) configureFlowControl(streamWindowSize, connWindowSize int32) {
+	ch.streamWindowSize = flowControlWindowSize(streamWindowSize, defaultStreamWindowSize)
+	ch.connWindowSize = flowControlWindowSize(connWindowSize, defaultConnWindowSize)
+	ch.connRecvWindow = newRecvWindow(ch.connWindowSize)
+}
+
+// acceptDataChannelPool accepts up to poolSize pooled data channels opened by the server and
+// spreads new streams across them using assign, or the default assignment when assign is nil.
+// It must be called before the offer is made so that no pooled data channel is missed. Pooled
+// data channels beyond poolSize are left unused.
+//
+// The pool takes ownership of the peer connection's OnDataChannel handler: pion keeps only one
+// and does not expose it, so there is no previous handler to chain to, and data channels opened
+// by the server with other labels are ignored. Setting another handler afterwards stops pooled
+// data channels from being accepted.
+func (ch *webrtcClientChannel) acceptDataChannelPool(poolSize int, assign DataChannelAssigner) {
+	ch.mu.Lock()
+	ch.poolAssigner = assign
+	ch.mu.Unlock()
+
+	var accepted int
+	ch.peerConn.OnDataChannel(func(dc *webrtc.DataChannel) {
+		if dc.Label() != dataChannelPoolLabel || dc.ID() == nil || accepted >= poolSize {
+			return
+		}
+		accepted++
+		id := ch.addPoolChannel(dc, ch.onChannelMessage)
+		dc.OnOpen(func() {
+			ch.mu.Lock()
+			ch.poolChannelIDs = append(ch.poolChannelIDs, id)
+			ch.mu.Unlock()
+		})
+	})
+}
+
+// assignDataChannel returns the SCTP stream identifier of the data channel a new stream for
+// the given method should be sent over. By default, unary RPCs use the primary data channel
+// and streaming RPCs are spread round-robin across the pooled data channels, so that a busy
+// stream cannot hold up unary calls.
+func (ch *webrtcClientChannel) assignDataChannel(method string, streaming bool) uint32 {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	poolSize := len(ch.poolChannelIDs)
+	if poolSize == 0 {
+		return 0
+	}
+	var idx int
+	if ch.poolAssigner != nil {
+		idx = ch.poolAssigner(method, streaming, poolSize)
+	} else if streaming {
+		idx = int(ch.poolStreamingCursor%uint64(poolSize)) + 1
+		ch.poolStreamingCursor++
+	}
+	if idx < 1 || idx > poolSize {
+		return 0
+	}
+	return ch.poolChannelIDs[idx-1]
+}
+
 func (ch *webrtcClientChannel) PeerConn() *webrtc.PeerConnection {
 	return ch.webrtcBaseChannel.peerConn
 }
@@ -86,6 +193,21 @@ func (ch *webrtcClientChannel) close() {
 		s.cs.Close()
 	}
 	ch.webrtcBaseChannel.Close()
+	ch.endDialSpan("", nil)
+}
+
+// endDialSpan ends the span of the dial that made the channel, if not already, with the
+// outcome of its first RPC.
+func (ch *webrtcClientChannel) endDialSpan(method string, err error) {
+	if ch.dialSpan == nil {
+		return
+	}
+	ch.endDialSpanOnce.Do(func() {
+		if method != "" {
+			addCallEvent(ch.dialSpan, "first rpc", attribute.String("method", method))
+		}
+		endCallSpan(ch.dialSpan, err)
+	})
 }
 
 // Invoke sends the RPC request on the wire and returns after response is
@@ -101,6 +223,7 @@ func (ch *webrtcClientChannel) Invoke(
 ) error {
 	startTime := time.Now()
 	err := ch.invokeWithInterceptor(ctx, method, args, reply, opts...)
+	ch.endDialSpan(method, err)
 	code := grpc_logging.DefaultErrorToCode(err)
 	loggerWithFields := utils.AddFieldsToLogger(ch.webrtcBaseChannel.logger, newClientLoggerFields(method)...)
 	utils.LogFinalLine(loggerWithFields, startTime, err, "finished client unary call", code)
@@ -130,7 +253,7 @@ func (ch *webrtcClientChannel) invoke(
 	args, reply interface{},
 	opts ...grpc.CallOption,
 ) error {
-	clientStream, err := ch.newStream(ctx, ch.nextStreamID())
+	clientStream, err := ch.newStream(ctx, ch.nextStreamID(method, false))
 	if err != nil {
 		return err
 	}
@@ -148,7 +271,7 @@ func (ch *webrtcClientChannel) invoke(
 				if clientStream.trailers != nil {
 					*optV.TrailerAddr = clientStream.trailers.Copy()
 				}
-			case grpc.StaticMethodCallOption:
+			case grpc.StaticMethodCallOption, grpc.CompressorCallOption:
 				// no-op handler to prevent unnecessary error logs for a known option
 			default:
 				clientStream.webrtcBaseStream.logger.Errorf("do not know how to handle call option %T", opt)
@@ -156,7 +279,12 @@ func (ch *webrtcClientChannel) invoke(
 		}
 	}()
 
-	if err := clientStream.writeHeaders(makeRequestHeaders(ctx, method)); err != nil {
+	headers := makeRequestHeaders(ctx, method)
+	if err := ch.negotiateCompression(clientStream, headers, opts); err != nil {
+		return err
+	}
+	ch.negotiateFlowControl(clientStream, headers)
+	if err := clientStream.writeHeaders(headers); err != nil {
 		return err
 	}
 
@@ -190,16 +318,22 @@ func (ch *webrtcClientChannel) NewStream(
 	opts ...grpc.CallOption,
 ) (grpc.ClientStream, error) {
 	startTime := time.Now()
-	clientStream, err := ch.streamWithInterceptor(ctx, method)
+	clientStream, err := ch.streamWithInterceptor(ctx, desc, method, opts...)
+	ch.endDialSpan(method, err)
 	code := grpc_logging.DefaultErrorToCode(err)
 	loggerWithFields := utils.AddFieldsToLogger(ch.webrtcBaseChannel.logger, newClientLoggerFields(method)...)
 	utils.LogFinalLine(loggerWithFields, startTime, err, "finished client streaming call", code)
 	return clientStream, err
 }
 
-func (ch *webrtcClientChannel) streamWithInterceptor(ctx context.Context, method string) (grpc.ClientStream, error) {
+func (ch *webrtcClientChannel) streamWithInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
 	if ch.streamInterceptor == nil {
-		return ch.newClientStream(ctx, method)
+		return ch.newClientStream(ctx, method, opts...)
 	}
 
 	// change signature of streamer to be compatible with grpc stream interceptor
@@ -210,29 +344,43 @@ func (ch *webrtcClientChannel) streamWithInterceptor(ctx context.Context, method
 		method string,
 		opts ...grpc.CallOption,
 	) (grpc.ClientStream, error) {
-		return ch.newClientStream(ctx, method)
+		return ch.newClientStream(ctx, method, opts...)
 	}
-	return ch.streamInterceptor(ctx, nil, nil, method, streamer)
+	return ch.streamInterceptor(ctx, desc, nil, method, streamer, opts...)
 }
 
-func (ch *webrtcClientChannel) newClientStream(ctx context.Context, method string) (grpc.ClientStream, error) {
-	clientStream, err := ch.newStream(ctx, ch.nextStreamID())
+func (ch *webrtcClientChannel) newClientStream(
+	ctx context.Context,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	clientStream, err := ch.newStream(ctx, ch.nextStreamID(method, true))
 	if err != nil {
 		return nil, err
 	}
 
-	if err := clientStream.writeHeaders(makeRequestHeaders(ctx, method)); err != nil {
+	headers := makeRequestHeaders(ctx, method)
+	if err := ch.negotiateCompression(clientStream, headers, opts); err != nil {
+		clientStream.Close()
+		return nil, err
+	}
+	ch.negotiateFlowControl(clientStream, headers)
+	if err := clientStream.writeHeaders(headers); err != nil {
 		return nil, err
 	}
 
 	return clientStream, nil
 }
 
+// makeRequestHeaders makes the headers of a call. Like the grpc-timeout header, the deadline of
+// the context is sent as the time left until it so that the server need not share our clock.
 func makeRequestHeaders(ctx context.Context, method string) *webrtcpb.RequestHeaders {
 	headersMD, _ := metadata.FromOutgoingContext(ctx)
 	var timeout time.Duration
 	if deadline, ok := ctx.Deadline(); ok {
-		timeout = time.Until(deadline)
+		// a zero timeout means there is no deadline, so a deadline that passes while the call
+		// starts is sent as the shortest timeout there is.
+		timeout = max(time.Until(deadline), time.Nanosecond)
 	}
 
 	return &webrtcpb.RequestHeaders{
@@ -242,9 +390,88 @@ func makeRequestHeaders(ctx context.Context, method string) *webrtcpb.RequestHea
 	}
 }
 
-func (ch *webrtcClientChannel) nextStreamID() *webrtcpb.Stream {
+// negotiateCompression fills in the compression fields of the headers for a new stream when
+// the call asks for compression, either through grpc.UseCompressor or the channel's default.
+// Responses may be compressed as soon as the server sees which compressors are accepted, but
+// requests are only compressed once the server has said it can decompress them, so that
+// servers without compression support keep working.
+func (ch *webrtcClientChannel) negotiateCompression(
+	clientStream *webrtcClientStream,
+	headers *webrtcpb.RequestHeaders,
+	opts []grpc.CallOption,
+) error {
+	name := ch.compressor
+	for _, opt := range opts {
+		if compressorOpt, ok := opt.(grpc.CompressorCallOption); ok {
+			name = compressorOpt.CompressorType
+		}
+	}
+	if name == "" || name == encoding.Identity {
+		return nil
+	}
+	compressor := encoding.GetCompressor(name)
+	if compressor == nil {
+		return status.Errorf(codes.Internal, "grpc: Compressor is not installed for requested grpc-encoding %q", name)
+	}
+	headers.AcceptedCompression = acceptedCompressors(name)
+
+	ch.mu.Lock()
+	_, serverAccepts := ch.serverCompressors[name]
+	ch.mu.Unlock()
+	if serverAccepts {
+		headers.Compression = name
+		clientStream.sendCompressor = compressor
+	}
+	return nil
+}
+
+// negotiateFlowControl fills in the flow control fields of the headers for a new stream. The
+// server is always offered windows for responses. Requests are only flow controlled once the
+// server has said it supports flow control, since servers without support never hand window
+// bytes back.
+func (ch *webrtcClientChannel) negotiateFlowControl(clientStream *webrtcClientStream, headers *webrtcpb.RequestHeaders) {
+	headers.WindowSize = uint32(ch.streamWindowSize)
+	headers.ConnectionWindowSize = uint32(ch.connWindowSize)
+	clientStream.writeWindowUpdate = ch.writeWindowUpdate
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.serverStreamWindowSize == 0 {
+		return
+	}
+	headers.FlowControlled = true
+	clientStream.sendWindow = newSendWindow(ch.serverStreamWindowSize)
+	clientStream.connSendWindow = ch.connSendWindow
+}
+
+// learnServerWindows records the flow control windows the server grants for requests. Only the
+// first connection window is used since the server only keeps track of one.
+func (ch *webrtcClientChannel) learnServerWindows(streamWindowSize, connWindowSize uint32) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.serverStreamWindowSize != 0 {
+		return
+	}
+	ch.serverStreamWindowSize = int64(streamWindowSize)
+	ch.connSendWindow = newSendWindow(flowControlWindowSize(int32(min(connWindowSize, math.MaxInt32)), defaultConnWindowSize))
+}
+
+// addServerCompressors records the compressors the server can decompress requests with.
+func (ch *webrtcClientChannel) addServerCompressors(names []string) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.serverCompressors == nil {
+		ch.serverCompressors = map[string]struct{}{}
+	}
+	for _, name := range names {
+		ch.serverCompressors[name] = struct{}{}
+	}
+}
+
+func (ch *webrtcClientChannel) nextStreamID(method string, streaming bool) *webrtcpb.Stream {
 	return &webrtcpb.Stream{
-		Id: atomic.AddUint64(&ch.streamIDCounter, 1),
+		Id:        atomic.AddUint64(&ch.streamIDCounter, 1),
+		ChannelId: ch.assignDataChannel(method, streaming),
 	}
 }
 
@@ -258,6 +485,14 @@ func (ch *webrtcClientChannel) newStream(
 	ctx context.Context,
 	stream *webrtcpb.Stream,
 ) (*webrtcClientStream, error) {
+	// Like gRPC, calls are not started once their context is done. This also keeps a deadline
+	// that has just passed from reaching the server as a zero timeout, which means none.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ch.goingAway.Load() {
+		return nil, ErrGoingAway
+	}
 	id := stream.GetId()
 	ch.mu.Lock()
 	defer ch.mu.Unlock()
@@ -294,11 +529,37 @@ func (ch *webrtcClientChannel) onChannelMessage(msg webrtc.DataChannelMessage) {
 	}
 
 	id := stream.GetId()
+	switch r := resp.GetType().(type) {
+	case *webrtcpb.Response_WindowUpdate:
+		if id != 0 {
+			break
+		}
+		ch.mu.Lock()
+		connSendWindow := ch.connSendWindow
+		ch.mu.Unlock()
+		if connSendWindow != nil {
+			connSendWindow.add(int64(r.WindowUpdate.GetIncrement()))
+		}
+		return
+	case *webrtcpb.Response_Ping:
+		if err := ch.writePong(r.Ping.GetNonce()); err != nil {
+			ch.webrtcBaseChannel.logger.Debugw("failed to answer ping", "error", err)
+		}
+		return
+	case *webrtcpb.Response_Pong:
+		ch.onPong(r.Pong.GetNonce())
+		return
+	case *webrtcpb.Response_GoAway:
+		ch.webrtcBaseChannel.logger.Debug("server is going away")
+		ch.goingAway.Store(true)
+		return
+	}
 	ch.mu.Lock()
 	activeStream, ok := ch.streams[id]
 	if !ok {
 		ch.webrtcBaseChannel.logger.Debugw("no stream for id; discarding", "id", id)
 		ch.mu.Unlock()
+		ch.handBackDiscarded(stream, resp.GetMessage().GetPacketMessage())
 		return
 	}
 	ch.mu.Unlock()
@@ -306,8 +567,29 @@ func (ch *webrtcClientChannel) onChannelMessage(msg webrtc.DataChannelMessage) {
 	activeStream.cs.onResponse(resp)
 }
 
+// handBackDiscarded hands the connection window bytes of a packet for a stream that is already
+// done back to the server so that they are not lost.
+func (ch *webrtcClientChannel) handBackDiscarded(stream *webrtcpb.Stream, msg *webrtcpb.PacketMessage) {
+	if msg == nil {
+		return
+	}
+	ch.mu.Lock()
+	serverFlowControlled := ch.serverStreamWindowSize > 0
+	ch.mu.Unlock()
+	if !serverFlowControlled {
+		return
+	}
+	cost := packetCost(len(msg.GetData()))
+	ch.connRecvWindow.receive(cost)
+	if increment := ch.connRecvWindow.consume(cost); increment > 0 {
+		if err := ch.writeWindowUpdate(&webrtcpb.Stream{ChannelId: stream.GetChannelId()}, increment); err != nil {
+			ch.webrtcBaseChannel.logger.Debugw("failed to write connection window update", "error", err)
+		}
+	}
+}
+
 func (ch *webrtcClientChannel) writeHeaders(stream *webrtcpb.Stream, headers *webrtcpb.RequestHeaders) error {
-	return ch.webrtcBaseChannel.write(&webrtcpb.Request{
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Request{
 		Stream: stream,
 		Type: &webrtcpb.Request_Headers{
 			Headers: headers,
@@ -316,7 +598,7 @@ func (ch *webrtcClientChannel) writeHeaders(stream *webrtcpb.Stream, headers *we
 }
 
 func (ch *webrtcClientChannel) writeMessage(stream *webrtcpb.Stream, msg *webrtcpb.RequestMessage) error {
-	return ch.webrtcBaseChannel.write(&webrtcpb.Request{
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Request{
 		Stream: stream,
 		Type: &webrtcpb.Request_Message{
 			Message: msg,
@@ -324,8 +606,35 @@ func (ch *webrtcClientChannel) writeMessage(stream *webrtcpb.Stream, msg *webrtc
 	})
 }
 
-func (ch *webrtcClientChannel) writeReset(stream *webrtcpb.Stream) error {
+func (ch *webrtcClientChannel) writeWindowUpdate(stream *webrtcpb.Stream, increment uint32) error {
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Request{
+		Stream: stream,
+		Type: &webrtcpb.Request_WindowUpdate{
+			WindowUpdate: &webrtcpb.WindowUpdate{Increment: increment},
+		},
+	})
+}
+
+func (ch *webrtcClientChannel) writePing(nonce uint64) error {
 	return ch.webrtcBaseChannel.write(&webrtcpb.Request{
+		Stream: &webrtcpb.Stream{},
+		Type: &webrtcpb.Request_Ping{
+			Ping: &webrtcpb.Ping{Nonce: nonce},
+		},
+	})
+}
+
+func (ch *webrtcClientChannel) writePong(nonce uint64) error {
+	return ch.webrtcBaseChannel.write(&webrtcpb.Request{
+		Stream: &webrtcpb.Stream{},
+		Type: &webrtcpb.Request_Pong{
+			Pong: &webrtcpb.Pong{Nonce: nonce},
+		},
+	})
+}
+
+func (ch *webrtcClientChannel) writeReset(stream *webrtcpb.Stream) error {
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Request{
 		Stream: stream,
 		Type: &webrtcpb.Request_RstStream{
 			RstStream: true,
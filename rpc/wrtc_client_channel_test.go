@@ -57,8 +57,10 @@ func TestWebRTCClientChannel(t *testing.T) {
 			Stream: &webrtcpb.Stream{Id: 1},
 			Type: &webrtcpb.Request_Headers{
 				Headers: &webrtcpb.RequestHeaders{
-					Method:  "thing",
-					Timeout: durationpb.New(0),
+					Method:               "thing",
+					Timeout:              durationpb.New(0),
+					WindowSize:           defaultStreamWindowSize,
+					ConnectionWindowSize: defaultConnWindowSize,
 				},
 			},
 		},
@@ -181,7 +183,9 @@ func TestWebRTCClientChannel(t *testing.T) {
 			Stream: &webrtcpb.Stream{Id: 2},
 			Type: &webrtcpb.Request_Headers{
 				Headers: &webrtcpb.RequestHeaders{
-					Method: "thing",
+					Method:               "thing",
+					WindowSize:           defaultStreamWindowSize,
+					ConnectionWindowSize: defaultConnWindowSize,
 				},
 			},
 		},
@@ -215,8 +219,10 @@ func TestWebRTCClientChannel(t *testing.T) {
 			Stream: &webrtcpb.Stream{Id: 3},
 			Type: &webrtcpb.Request_Headers{
 				Headers: &webrtcpb.RequestHeaders{
-					Method:  "thing",
-					Timeout: durationpb.New(0),
+					Method:               "thing",
+					Timeout:              durationpb.New(0),
+					WindowSize:           defaultStreamWindowSize,
+					ConnectionWindowSize: defaultConnWindowSize,
 				},
 			},
 		},
@@ -248,8 +254,10 @@ func TestWebRTCClientChannel(t *testing.T) {
 			Stream: &webrtcpb.Stream{Id: 4},
 			Type: &webrtcpb.Request_Headers{
 				Headers: &webrtcpb.RequestHeaders{
-					Method:  "thing",
-					Timeout: durationpb.New(0),
+					Method:               "thing",
+					Timeout:              durationpb.New(0),
+					WindowSize:           defaultStreamWindowSize,
+					ConnectionWindowSize: defaultConnWindowSize,
 				},
 			},
 		},
@@ -282,8 +290,10 @@ func TestWebRTCClientChannel(t *testing.T) {
 			Stream: &webrtcpb.Stream{Id: 5},
 			Type: &webrtcpb.Request_Headers{
 				Headers: &webrtcpb.RequestHeaders{
-					Method:  "thing",
-					Timeout: durationpb.New(0),
+					Method:               "thing",
+					Timeout:              durationpb.New(0),
+					WindowSize:           defaultStreamWindowSize,
+					ConnectionWindowSize: defaultConnWindowSize,
 				},
 			},
 		},
@@ -363,8 +373,10 @@ func TestWebRTCClientChannelResetStream(t *testing.T) {
 			Stream: &webrtcpb.Stream{Id: 1},
 			Type: &webrtcpb.Request_Headers{
 				Headers: &webrtcpb.RequestHeaders{
-					Method:  "thing",
-					Timeout: durationpb.New(0),
+					Method:               "thing",
+					Timeout:              durationpb.New(0),
+					WindowSize:           defaultStreamWindowSize,
+					ConnectionWindowSize: defaultConnWindowSize,
 				},
 			},
 		},
@@ -0,0 +1,253 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+)
+
+const (
+	// defaultSignalingHedgeDelay is how long a dial waits on a signaling server before also
+	// trying the next one.
+	defaultSignalingHedgeDelay = time.Second
+
+	// signalingFailureMemory is how long a signaling server that could not be reached is tried
+	// after those that could.
+	signalingFailureMemory = time.Minute
+)
+
+// lookupSRV resolves DNS SRV records. It can be replaced in tests.
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// signalingServers returns the signaling servers to try, in order: the given one, then the
+// ones in SignalingServerAddresses and then the ones SignalingServerSRV resolves to.
+func (opts DialWebRTCOptions) signalingServers(
+	ctx context.Context,
+	signalingServer string,
+	logger utils.ZapCompatibleLogger,
+) []string {
+	servers := []string{signalingServer}
+	for _, server := range opts.SignalingServerAddresses {
+		if !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+	if opts.SignalingServerSRV == "" {
+		return servers
+	}
+	// records are sorted by priority and randomized by weight.
+	_, records, err := lookupSRV(ctx, "", "", opts.SignalingServerSRV)
+	if err != nil {
+		// the other servers may still be reachable.
+		logger.Warnw("failed to look up signaling servers", "name", opts.SignalingServerSRV, "error", err)
+		return servers
+	}
+	for _, record := range records {
+		server := net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+		if !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// signalingServerHealth remembers how the last attempts to reach signaling servers went so
+// that dials try the ones that can be reached first.
+type signalingServerHealth struct {
+	mu      sync.Mutex
+	servers map[string]signalingServerRecord
+}
+
+type signalingServerRecord struct {
+	consecutiveFailures int
+	lastFailure         time.Time
+}
+
+var signalingHealth = &signalingServerHealth{servers: map[string]signalingServerRecord{}}
+
+func (h *signalingServerHealth) recordSuccess(server string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.servers, server)
+}
+
+func (h *signalingServerHealth) recordFailure(server string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	record := h.servers[server]
+	record.consecutiveFailures++
+	record.lastFailure = time.Now()
+	h.servers[server] = record
+}
+
+// order returns the servers with the ones that failed recently moved to the back, those that
+// failed the most times in a row last. The order is otherwise kept.
+func (h *signalingServerHealth) order(servers []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	failures := func(server string) int {
+		record, ok := h.servers[server]
+		if !ok || time.Since(record.lastFailure) > signalingFailureMemory {
+			return 0
+		}
+		return record.consecutiveFailures
+	}
+	ordered := slices.Clone(servers)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return failures(a) - failures(b)
+	})
+	return ordered
+}
+
+// A signalingConnection is a connection to a signaling server that has answered for a host.
+type signalingConnection struct {
+	server string
+	conn   ClientConn
+	config *webrtcpb.OptionalWebRTCConfigResponse
+}
+
+// connectSignalingServer connects to a signaling server and asks it for the WebRTC
+// configuration of the host, which also checks that it can signal for it.
+func connectSignalingServer(
+	ctx context.Context,
+	signalingServer string,
+	host string,
+	logger utils.ZapCompatibleLogger,
+	dOpts dialOptions,
+) (*signalingConnection, error) {
+	conn, err := dialSignalingServer(ctx, signalingServer, host, logger, dOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.New(map[string]string{RPCHostMetadataField: host})
+	signalCtx := metadata.NewOutgoingContext(ctx, md)
+	configResp, err := webrtcpb.NewSignalingServiceClient(conn).OptionalWebRTCConfig(
+		signalCtx, &webrtcpb.OptionalWebRTCConfigRequest{})
+	if err != nil {
+		utils.UncheckedError(conn.Close())
+		// this would be where we would hit an unimplemented signaler error first.
+		if s, ok := status.FromError(err); ok && (s.Code() == codes.Unimplemented ||
+			(s.Code() == codes.InvalidArgument && s.Message() == hostNotAllowedMsg)) {
+			return nil, ErrNoWebRTCSignaler
+		}
+		return nil, err
+	}
+	return &signalingConnection{server: signalingServer, conn: conn, config: configResp}, nil
+}
+
+// connectSignalingServers connects to the first of the signaling servers to answer for the
+// host. They are tried in order, healthy ones first, and an attempt on the next one is started
+// whenever the last one fails or has not answered within the hedge delay.
+func connectSignalingServers(
+	ctx context.Context,
+	servers []string,
+	host string,
+	logger utils.ZapCompatibleLogger,
+	dOpts dialOptions,
+) (*signalingConnection, error) {
+	if len(servers) == 1 {
+		return connectSignalingServer(ctx, servers[0], host, logger, dOpts)
+	}
+	servers = signalingHealth.order(servers)
+	hedgeDelay := dOpts.webrtcOpts.SignalingHedgeDelay
+	if hedgeDelay <= 0 {
+		hedgeDelay = defaultSignalingHedgeDelay
+	}
+
+	type attemptResult struct {
+		server string
+		conn   *signalingConnection
+		err    error
+	}
+	attemptCtx, attemptCancel := context.WithCancel(ctx)
+	defer attemptCancel()
+	results := make(chan attemptResult, len(servers))
+	var started, pending int
+	pendingServers := map[string]bool{}
+	startNext := func() {
+		server := servers[started]
+		started++
+		pending++
+		pendingServers[server] = true
+		if dOpts.debug {
+			logger.Debugw("trying signaling server", "signaling_server", server, "attempt", started)
+		}
+		go func() {
+			conn, err := connectSignalingServer(attemptCtx, server, host, logger, dOpts)
+			results <- attemptResult{server: server, conn: conn, err: err}
+		}()
+	}
+	startNext()
+
+	hedgeTimer := time.NewTimer(hedgeDelay)
+	defer hedgeTimer.Stop()
+	var errs error
+	for pending > 0 {
+		select {
+		case <-hedgeTimer.C:
+			if started < len(servers) {
+				startNext()
+				hedgeTimer.Reset(hedgeDelay)
+			}
+		case res := <-results:
+			pending--
+			delete(pendingServers, res.server)
+			if res.err == nil {
+				signalingHealth.recordSuccess(res.server)
+				// servers tried earlier that have yet to answer are as good as down.
+				for _, server := range servers[:slices.Index(servers, res.server)] {
+					if pendingServers[server] {
+						signalingHealth.recordFailure(server)
+					}
+				}
+				if dOpts.debug {
+					logger.Debugw("signaling server won",
+						"signaling_server", res.server,
+						"attempts", started,
+						"signaling_servers", servers,
+					)
+				}
+				attemptCancel()
+				// close the connections of attempts that finish anyway.
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if res := <-results; res.conn != nil {
+							utils.UncheckedError(res.conn.conn.Close())
+						}
+					}
+				}(pending)
+				return res.conn, nil
+			}
+			if ctx.Err() != nil {
+				continue
+			}
+			signalingHealth.recordFailure(res.server)
+			if dOpts.debug {
+				logger.Debugw("signaling server failed", "signaling_server", res.server, "error", res.err)
+			}
+			errs = multierr.Combine(errs, errors.Wrap(res.err, res.server))
+			if started < len(servers) {
+				startNext()
+				hedgeTimer.Reset(hedgeDelay)
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, errs
+}
@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestSignalingServerHealthOrder(t *testing.T) {
+	health := &signalingServerHealth{servers: map[string]signalingServerRecord{}}
+	servers := []string{"a", "b", "c", "d"}
+	test.That(t, health.order(servers), test.ShouldResemble, servers)
+
+	health.recordFailure("a")
+	health.recordFailure("a")
+	health.recordFailure("b")
+	test.That(t, health.order(servers), test.ShouldResemble, []string{"c", "d", "b", "a"})
+	test.That(t, servers, test.ShouldResemble, []string{"a", "b", "c", "d"})
+
+	health.recordSuccess("a")
+	test.That(t, health.order(servers), test.ShouldResemble, []string{"a", "c", "d", "b"})
+
+	// old failures are forgotten.
+	health.servers["b"] = signalingServerRecord{consecutiveFailures: 1, lastFailure: time.Now().Add(-2 * signalingFailureMemory)}
+	test.That(t, health.order(servers), test.ShouldResemble, servers)
+}
+
+func TestDialWebRTCSignalingServers(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger, observedLogs := golog.NewObservedTestLogger(t)
+
+	rpcServer, err := NewServer(
+		logger,
+		WithWebRTCServerOptions(WebRTCServerOptions{Enable: true}),
+		WithUnauthenticated(),
+		// only signaling can find the server.
+		WithDisableMulticastDNS(),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&echopb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		echopb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+	liveAddr := listener.Addr().String()
+
+	// nothing listens here anymore.
+	deadListener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	deadAddr := deadListener.Addr().String()
+	test.That(t, deadListener.Close(), test.ShouldBeNil)
+
+	dialEcho := func(t *testing.T, webrtcOpts DialWebRTCOptions) {
+		t.Helper()
+		webrtcOpts.SignalingInsecure = true
+		webrtcOpts.SignalingHedgeDelay = 100 * time.Millisecond
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		observedLogs.TakeAll()
+		rtcConn, err := DialWebRTC(ctx, deadAddr, rpcServer.InstanceNames()[0], logger,
+			WithInsecure(), WithDialDebug(), WithWebRTCOptions(webrtcOpts))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		resp, err := echopb.NewEchoServiceClient(rtcConn).Echo(context.Background(), &echopb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+
+		winners := observedLogs.FilterMessage("signaling server won").All()
+		test.That(t, winners, test.ShouldHaveLength, 1)
+		test.That(t, winners[0].ContextMap()["signaling_server"], test.ShouldEqual, liveAddr)
+	}
+
+	t.Run("addresses", func(t *testing.T) {
+		dialEcho(t, DialWebRTCOptions{SignalingServerAddresses: []string{deadAddr, liveAddr}})
+		// the server that could not be reached is tried last from now on.
+		test.That(t, signalingHealth.order([]string{deadAddr, liveAddr}), test.ShouldResemble, []string{liveAddr, deadAddr})
+	})
+
+	t.Run("srv", func(t *testing.T) {
+		prevLookupSRV := lookupSRV
+		defer func() {
+			lookupSRV = prevLookupSRV
+		}()
+		host, portStr, err := net.SplitHostPort(liveAddr)
+		test.That(t, err, test.ShouldBeNil)
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		test.That(t, err, test.ShouldBeNil)
+		lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			test.That(t, name, test.ShouldEqual, "_signaling._tcp.example.com")
+			return name, []*net.SRV{{Target: host + ".", Port: uint16(port)}}, nil
+		}
+
+		dialEcho(t, DialWebRTCOptions{SignalingServerSRV: "_signaling._tcp.example.com"})
+	})
+}
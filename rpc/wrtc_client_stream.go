@@ -6,8 +6,9 @@ import (
 	"io"
 	"math"
 
-	protov1 "github.com/golang/protobuf/proto" //nolint:staticcheck
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -21,6 +22,9 @@ var (
 	// ErrDisconnected indicates that the channel underlying the client stream
 	// has been closed, and the client is therefore disconnected.
 	ErrDisconnected = errors.New("client disconnected; underlying channel closed")
+	// ErrGoingAway indicates that the server has said it is shutting down, and the client
+	// should connect again to make new calls.
+	ErrGoingAway = errors.New("server is going away; connect again for new calls")
 )
 
 // A webrtcClientStream is the high level gRPC streaming interface used for both
@@ -179,6 +183,18 @@ func (s *webrtcClientStream) resetStream() (err error) {
 	return s.ch.writeReset(s.webrtcBaseStream.stream)
 }
 
+// resetStreamWithError resets the stream on the server and fails it with the given error.
+func (s *webrtcClientStream) resetStreamWithError(err error) {
+	s.webrtcBaseStream.mu.Lock()
+	defer s.webrtcBaseStream.mu.Unlock()
+
+	s.sendClosed = true
+	if writeErr := s.ch.writeReset(s.webrtcBaseStream.stream); writeErr != nil {
+		s.webrtcBaseStream.logger.Debugw("failed to reset stream", "error", writeErr)
+	}
+	s.webrtcBaseStream.closeWithError(err, false)
+}
+
 func (s *webrtcClientStream) Close() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -209,14 +225,16 @@ var maxRequestMessagePacketDataSize int
 func init() {
 	md, err := proto.Marshal(&webrtcpb.Request{
 		Stream: &webrtcpb.Stream{
-			Id: math.MaxUint64,
+			Id:        math.MaxUint64,
+			ChannelId: math.MaxUint16,
 		},
 		Type: &webrtcpb.Request_Message{
 			Message: &webrtcpb.RequestMessage{
 				HasMessage: true,
 				PacketMessage: &webrtcpb.PacketMessage{
-					Data: []byte{0x0},
-					Eom:  true,
+					Data:       []byte{0x0},
+					Eom:        true,
+					Compressed: true,
 				},
 				Eos: true,
 			},
@@ -236,6 +254,7 @@ func (s *webrtcClientStream) writeMessage(m interface{}, eos bool) (err error) {
 		return io.ErrClosedPipe
 	}
 
+	unlock, lock := s.webrtcBaseStream.mu.RUnlock, s.webrtcBaseStream.mu.RLock
 	if eos {
 		s.webrtcBaseStream.mu.RUnlock()
 		s.webrtcBaseStream.mu.Lock()
@@ -244,10 +263,9 @@ func (s *webrtcClientStream) writeMessage(m interface{}, eos bool) (err error) {
 			return io.ErrClosedPipe
 		}
 		s.sendClosed = true
-		defer s.webrtcBaseStream.mu.Unlock()
-	} else {
-		defer s.webrtcBaseStream.mu.RUnlock()
+		unlock, lock = s.webrtcBaseStream.mu.Unlock, s.webrtcBaseStream.mu.Lock
 	}
+	defer unlock()
 
 	defer func() {
 		if err := checkWriteErrForStreamClose(err); err != nil {
@@ -256,17 +274,20 @@ func (s *webrtcClientStream) writeMessage(m interface{}, eos bool) (err error) {
 	}()
 
 	var data []byte
+	var compressed bool
 	if m != nil {
-		if v1Msg, ok := m.(protov1.Message); ok {
-			m = protov1.MessageV2(v1Msg)
-		}
-		data, err = proto.Marshal(m.(proto.Message))
+		data, compressed, err = s.webrtcBaseStream.encodeMessage(m)
 		if err != nil {
 			return err
 		}
 	}
 
 	if len(data) == 0 {
+		if m != nil {
+			if err := s.webrtcBaseStream.waitForSendWindow("client", 0, unlock, lock); err != nil {
+				return err
+			}
+		}
 		return s.ch.writeMessage(s.webrtcBaseStream.stream, &webrtcpb.RequestMessage{
 			HasMessage: m != nil, // maybe no data but a non-nil message
 			PacketMessage: &webrtcpb.PacketMessage{
@@ -282,12 +303,16 @@ func (s *webrtcClientStream) writeMessage(m interface{}, eos bool) (err error) {
 			amountToSend = len(data)
 		}
 		packet := &webrtcpb.PacketMessage{
-			Data: data[:amountToSend],
+			Data:       data[:amountToSend],
+			Compressed: compressed,
 		}
 		data = data[amountToSend:]
 		if len(data) == 0 {
 			packet.Eom = true
 		}
+		if err := s.webrtcBaseStream.waitForSendWindow("client", amountToSend, unlock, lock); err != nil {
+			return err
+		}
 		if err := s.ch.writeMessage(s.webrtcBaseStream.stream, &webrtcpb.RequestMessage{
 			HasMessage:    m != nil, // maybe no data but a non-nil message
 			PacketMessage: packet,
@@ -301,6 +326,10 @@ func (s *webrtcClientStream) writeMessage(m interface{}, eos bool) (err error) {
 
 func (s *webrtcClientStream) onResponse(resp *webrtcpb.Response) {
 	switch r := resp.GetType().(type) {
+	case *webrtcpb.Response_WindowUpdate:
+		if s.sendWindow != nil {
+			s.sendWindow.add(int64(r.WindowUpdate.GetIncrement()))
+		}
 	case *webrtcpb.Response_Headers:
 		select {
 		case <-s.headersReceived:
@@ -333,7 +362,25 @@ func (s *webrtcClientStream) onResponse(resp *webrtcpb.Response) {
 }
 
 func (s *webrtcClientStream) processHeaders(headers *webrtcpb.ResponseHeaders) {
+	if accepted := headers.GetAcceptedCompression(); len(accepted) > 0 {
+		s.ch.addServerCompressors(accepted)
+	}
+	if windowSize := headers.GetWindowSize(); windowSize > 0 {
+		s.ch.learnServerWindows(windowSize, headers.GetConnectionWindowSize())
+		s.recvWindow = newRecvWindow(s.ch.streamWindowSize)
+		s.connRecvWindow = s.ch.connRecvWindow
+	}
 	s.webrtcBaseStream.mu.Lock()
+	if name := headers.GetCompression(); name != "" {
+		compressor := encoding.GetCompressor(name)
+		if compressor == nil {
+			s.webrtcBaseStream.closeWithError(
+				status.Errorf(codes.Internal, "grpc: Decompressor is not installed for grpc-encoding %q", name), false)
+			s.webrtcBaseStream.mu.Unlock()
+			return
+		}
+		s.recvCompressor = compressor
+	}
 	s.headers = metadataFromProto(headers.GetMetadata())
 	s.userCtx = metadata.NewIncomingContext(s.ctx, s.headers)
 	s.webrtcBaseStream.mu.Unlock()
@@ -345,29 +392,23 @@ func (s *webrtcClientStream) processMessage(msg *webrtcpb.ResponseMessage) {
 		s.webrtcBaseStream.logger.Error("message received after trailers")
 		return
 	}
-	data, eop := s.webrtcBaseStream.processMessage(msg.GetPacketMessage())
-	if !eop {
+	data, eop, err := s.webrtcBaseStream.processMessage(msg.GetPacketMessage())
+	if err != nil {
+		s.resetStreamWithError(err)
 		return
 	}
-	s.webrtcBaseStream.mu.Lock()
-	if s.webrtcBaseStream.recvClosed.Load() {
-		s.webrtcBaseStream.mu.Unlock()
+	if !eop {
 		return
 	}
-	msgCh := s.webrtcBaseStream.msgCh
-	s.webrtcBaseStream.activeSenders.Add(1)
-	s.webrtcBaseStream.mu.Unlock()
-
-	func() {
-		defer s.webrtcBaseStream.activeSenders.Done()
-		select {
-		case msgCh <- data:
-		case <-s.ctx.Done():
-		}
-	}()
+	s.webrtcBaseStream.enqueueMessage(data)
 }
 
 func (s *webrtcClientStream) processTrailers(trailers *webrtcpb.ResponseTrailers) {
+	if s.sendWindow != nil {
+		// the server will not hand back any more window, so wake up a waiting SendMsg which
+		// holds the lock needed below.
+		s.sendWindow.close()
+	}
 	s.webrtcBaseStream.mu.Lock()
 	defer s.webrtcBaseStream.mu.Unlock()
 	s.trailersReceived = true
@@ -0,0 +1,208 @@
+package rpc
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/encoding"
+	// register the gzip compressor for use by both gRPC and gRPC over WebRTC.
+	_ "google.golang.org/grpc/encoding/gzip"
+
+	"go.viam.com/utils"
+)
+
+// webrtcCompressors are the compressors, in order of preference, that a WebRTC client offers
+// to accept responses in when compression is requested. Any compressor registered with
+// google.golang.org/grpc/encoding may be requested as well.
+var webrtcCompressors = []string{zstdCompressorName, "gzip", snappyCompressorName}
+
+// minCompressedMessageSize is the size below which messages are sent uncompressed since
+// compressing them rarely saves anything.
+const minCompressedMessageSize = 512
+
+const (
+	zstdCompressorName   = "zstd"
+	snappyCompressorName = "snappy"
+)
+
+func init() {
+	// Do not replace compressors of the same name that were registered elsewhere.
+	if encoding.GetCompressor(zstdCompressorName) == nil {
+		encoding.RegisterCompressor(&zstdCompressor{})
+	}
+	if encoding.GetCompressor(snappyCompressorName) == nil {
+		encoding.RegisterCompressor(snappyCompressor{})
+	}
+}
+
+// zstdCompressor is an encoding.Compressor for zstd that reuses encoders and decoders.
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+func (c *zstdCompressor) Name() string {
+	return zstdCompressorName
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if enc, ok := c.encoders.Get().(*zstd.Encoder); ok {
+		enc.Reset(w)
+		return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+	}
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if dec, ok := c.decoders.Get().(*zstd.Decoder); ok {
+		if err := dec.Reset(r); err != nil {
+			dec.Close()
+			return nil, err
+		}
+		return &zstdReader{dec: dec, pool: &c.decoders}, nil
+	}
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(MaxMessageSize)))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdReader{dec: dec, pool: &c.decoders}, nil
+}
+
+// zstdWriter returns its encoder to the pool once closed.
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w.Encoder)
+	return err
+}
+
+// zstdReader returns its decoder to the pool once the end of the input is reached, reading
+// fails, or it is closed, whichever happens first.
+type zstdReader struct {
+	dec  *zstd.Decoder
+	pool *sync.Pool
+	// err is returned by reads once the decoder is released.
+	err error
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	if r.dec == nil {
+		return 0, r.err
+	}
+	n, err := r.dec.Read(p)
+	if err != nil {
+		r.release(err)
+	}
+	return n, err
+}
+
+// Close releases the decoder if the input was not read to the end.
+func (r *zstdReader) Close() error {
+	r.release(io.ErrClosedPipe)
+	return nil
+}
+
+func (r *zstdReader) release(err error) {
+	if r.dec == nil {
+		return
+	}
+	r.err = err
+	// resetting drops the decoder's hold on the input before it is pooled.
+	if resetErr := r.dec.Reset(nil); resetErr != nil {
+		r.dec.Close()
+	} else {
+		r.pool.Put(r.dec)
+	}
+	r.dec = nil
+}
+
+// snappyCompressor is an encoding.Compressor for the snappy framing format.
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string {
+	return snappyCompressorName
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+// acceptedCompressors returns the compressors a client that prefers the given compressor
+// offers to accept responses in.
+func acceptedCompressors(preferred string) []string {
+	accepted := []string{preferred}
+	for _, name := range webrtcCompressors {
+		if name != preferred && encoding.GetCompressor(name) != nil {
+			accepted = append(accepted, name)
+		}
+	}
+	return accepted
+}
+
+// compressMessage compresses data with the given compressor. It reports false, and returns data
+// untouched, when there is no compressor, data is too small to be worth compressing, or
+// compressing it does not make it smaller.
+func compressMessage(compressor encoding.Compressor, data []byte) ([]byte, bool, error) {
+	if compressor == nil || len(data) < minCompressedMessageSize {
+		return data, false, nil
+	}
+	var buf bytes.Buffer
+	w, err := compressor.Compress(&buf)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, false, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, false, err
+	}
+	if buf.Len() >= len(data) {
+		return data, false, nil
+	}
+	return buf.Bytes(), true, nil
+}
+
+// decompressMessage decompresses data with the given compressor, refusing to produce more than
+// MaxMessageSize bytes.
+func decompressMessage(compressor encoding.Compressor, data []byte) ([]byte, error) {
+	if compressor == nil {
+		return nil, errors.New("received a compressed message but no compression was negotiated")
+	}
+	r, err := compressor.Decompress(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if zr, ok := r.(*zstdReader); ok {
+		// release the decoder if it is not read to the end, such as when the output is too
+		// large. Other readers, like gRPC's gzip one, pool themselves once read to the end and
+		// must not be closed after that.
+		defer func() {
+			utils.UncheckedError(zr.Close())
+		}()
+	}
+	decompressed, err := io.ReadAll(io.LimitReader(r, int64(MaxMessageSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > MaxMessageSize {
+		return nil, errors.Errorf("decompressed message size larger than max %d", MaxMessageSize)
+	}
+	return decompressed, nil
+}
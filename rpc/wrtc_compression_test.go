@@ -0,0 +1,187 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils"
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestCompressMessage(t *testing.T) {
+	large := bytes.Repeat([]byte("point cloud "), 1000)
+
+	for _, name := range webrtcCompressors {
+		t.Run(name, func(t *testing.T) {
+			compressor := encoding.GetCompressor(name)
+			test.That(t, compressor, test.ShouldNotBeNil)
+
+			// large, repetitive messages shrink and round trip.
+			compressed, ok, err := compressMessage(compressor, large)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, len(compressed), test.ShouldBeLessThan, len(large))
+			decompressed, err := decompressMessage(compressor, compressed)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, decompressed, test.ShouldResemble, large)
+
+			// compressors are reusable.
+			compressed2, ok, err := compressMessage(compressor, large)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, compressed2, test.ShouldResemble, compressed)
+
+			// small messages are left alone.
+			small := []byte("hello")
+			data, ok, err := compressMessage(compressor, small)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ok, test.ShouldBeFalse)
+			test.That(t, data, test.ShouldResemble, small)
+
+			// decompressing cannot exceed the max message size.
+			huge, ok, err := compressMessage(compressor, make([]byte, MaxMessageSize+1))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ok, test.ShouldBeTrue)
+			_, err = decompressMessage(compressor, huge)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, "larger than max")
+		})
+	}
+
+	t.Run("zstd decoders are released when not read to the end", func(t *testing.T) {
+		compressor := &zstdCompressor{}
+		compressed, ok, err := compressMessage(compressor, large)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		r, err := compressor.Decompress(bytes.NewReader(compressed))
+		test.That(t, err, test.ShouldBeNil)
+		zr, ok := r.(*zstdReader)
+		test.That(t, ok, test.ShouldBeTrue)
+		_, err = zr.Read(make([]byte, 10))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, zr.Close(), test.ShouldBeNil)
+		test.That(t, zr.dec, test.ShouldBeNil)
+		_, err = zr.Read(make([]byte, 10))
+		test.That(t, err, test.ShouldBeError, io.ErrClosedPipe)
+		test.That(t, zr.Close(), test.ShouldBeNil)
+
+		// decoders from the pool work like new ones.
+		for i := 0; i < 3; i++ {
+			decompressed, err := decompressMessage(compressor, compressed)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, decompressed, test.ShouldResemble, large)
+		}
+	})
+
+	t.Run("no compressor", func(t *testing.T) {
+		data, ok, err := compressMessage(nil, large)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, data, test.ShouldResemble, large)
+
+		_, err = decompressMessage(nil, large)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	test.That(t, acceptedCompressors("gzip"), test.ShouldResemble, []string{"gzip", "zstd", "snappy"})
+}
+
+func TestWebRTCCompression(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+	pc1, pc2, dc1, dc2 := setupWebRTCPeers(t)
+	defer pc1.GracefulClose()
+	defer pc2.GracefulClose()
+
+	clientCh := newWebRTCClientChannel(pc1, dc1, nil, utils.Sublogger(logger, "client"), nil, nil)
+	defer func() {
+		test.That(t, clientCh.Close(), test.ShouldBeNil)
+	}()
+
+	server := newWebRTCServer(logger)
+	defer server.Stop()
+	server.RegisterService(&echopb.EchoService_ServiceDesc, &echoserver.Server{})
+
+	serverCh := newWebRTCServerChannel(server, pc2, dc2, nil, logger)
+	defer serverCh.Close()
+
+	<-clientCh.Ready()
+	<-serverCh.Ready()
+
+	client := echopb.NewEchoServiceClient(clientCh)
+	large := strings.Repeat("point cloud ", 1000)
+
+	// without compression nothing is learned about the server.
+	resp, err := client.Echo(context.Background(), &echopb.EchoRequest{Message: large})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetMessage(), test.ShouldEqual, large)
+	test.That(t, clientCh.serverCompressors, test.ShouldBeEmpty)
+
+	for _, name := range webrtcCompressors {
+		t.Run(name, func(t *testing.T) {
+			// the first call only compresses responses; subsequent calls compress requests too.
+			for i := 0; i < 2; i++ {
+				resp, err := client.Echo(context.Background(), &echopb.EchoRequest{Message: large}, grpc.UseCompressor(name))
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, resp.GetMessage(), test.ShouldEqual, large)
+				clientCh.mu.Lock()
+				test.That(t, clientCh.serverCompressors, test.ShouldContainKey, name)
+				clientCh.mu.Unlock()
+			}
+
+			clientCh.compressor = name
+			defer func() {
+				clientCh.compressor = ""
+			}()
+			stream, err := client.EchoBiDi(context.Background())
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, stream.Send(&echopb.EchoBiDiRequest{Message: large}), test.ShouldBeNil)
+			var received string
+			for len(received) < len(large) {
+				resp, err := stream.Recv()
+				test.That(t, err, test.ShouldBeNil)
+				received += resp.GetMessage()
+			}
+			test.That(t, received, test.ShouldEqual, large)
+			test.That(t, stream.CloseSend(), test.ShouldBeNil)
+		})
+	}
+
+	_, err = client.Echo(context.Background(), &echopb.EchoRequest{Message: large}, grpc.UseCompressor("nope"))
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Internal)
+
+	t.Run("messages that cannot be decompressed fail the call", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stream, err := clientCh.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true},
+			"/proto.rpc.examples.echo.v1.EchoService/EchoBiDi", grpc.UseCompressor("gzip"))
+		test.That(t, err, test.ShouldBeNil)
+		cs, ok := stream.(*webrtcClientStream)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, cs.sendCompressor, test.ShouldNotBeNil)
+		test.That(t, clientCh.writeMessage(cs.webrtcBaseStream.stream, &webrtcpb.RequestMessage{
+			HasMessage:    true,
+			PacketMessage: &webrtcpb.PacketMessage{Data: []byte("not gzip"), Eom: true, Compressed: true},
+		}), test.ShouldBeNil)
+
+		var resp echopb.EchoBiDiResponse
+		err = stream.RecvMsg(&resp)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Internal)
+		test.That(t, status.Convert(err).Message(), test.ShouldContainSubstring, "failed to decompress")
+	})
+}
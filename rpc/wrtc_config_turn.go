@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pion/stun"
+	"github.com/pkg/errors"
+
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+)
+
+// defaultTURNCredentialsTTL is how long TURN credentials are valid for by default.
+const defaultTURNCredentialsTTL = 12 * time.Hour
+
+// TURNRESTOptions configure a TURNRESTConfigProvider.
+type TURNRESTOptions struct {
+	// URLs are the TURN servers to hand out (e.g. "turn:turn.example.com:3478?transport=udp").
+	// They must all share SharedSecret.
+	URLs []string
+
+	// SharedSecret is the secret the TURN servers verify credentials with (static-auth-secret
+	// in coturn).
+	SharedSecret string
+
+	// TTL is how long credentials are valid for. Zero uses 12 hours. Configurations expire
+	// halfway through so that calls set up with a cached configuration still have time to use
+	// the credentials.
+	TTL time.Duration
+
+	// User, if set, is appended to usernames (as "expiry:user") so that TURN servers can tell
+	// who uses them.
+	User string
+
+	// ServersPerConfig, if positive, limits how many of the URLs each configuration has. The
+	// URLs are rotated across configurations either way so that clients spread out over them.
+	ServersPerConfig int
+}
+
+// A TURNRESTConfigProvider is a WebRTCConfigProvider handing out TURN servers along with
+// time-limited credentials minted from a secret shared with them, as described by the TURN
+// REST API (https://datatracker.ietf.org/doc/html/draft-uberti-behave-turn-rest-00) and
+// supported by coturn (use-auth-secret) and pion/turn (turn.NewLongTermAuthHandler).
+type TURNRESTConfigProvider struct {
+	opts TURNRESTOptions
+
+	mu   sync.Mutex
+	next int
+}
+
+// NewTURNRESTConfigProvider returns a TURNRESTConfigProvider for the given options.
+func NewTURNRESTConfigProvider(opts TURNRESTOptions) (*TURNRESTConfigProvider, error) {
+	if len(opts.URLs) == 0 {
+		return nil, errors.New("expected at least one TURN URL")
+	}
+	if opts.SharedSecret == "" {
+		return nil, errors.New("expected a shared secret")
+	}
+	for _, url := range opts.URLs {
+		uri, err := stun.ParseURI(url)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid TURN URL %q", url)
+		}
+		if !slices.Contains(validTurnSchemes, uri.Scheme) {
+			return nil, errors.Errorf("expected a turn or turns URL but got %q", url)
+		}
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = defaultTURNCredentialsTTL
+	}
+	opts.URLs = slices.Clone(opts.URLs)
+	return &TURNRESTConfigProvider{opts: opts}, nil
+}
+
+// Config returns the next rotation of TURN servers with credentials that outlive it by half
+// their TTL.
+func (p *TURNRESTConfigProvider) Config(ctx context.Context) (WebRTCConfig, error) {
+	now := time.Now()
+	credentialsExpire := now.Add(p.opts.TTL).Truncate(time.Second)
+	username, password := TURNRESTCredentials(p.opts.SharedSecret, p.opts.User, credentialsExpire)
+
+	p.mu.Lock()
+	start := p.next
+	p.next = (p.next + 1) % len(p.opts.URLs)
+	p.mu.Unlock()
+	urls := append(slices.Clone(p.opts.URLs[start:]), p.opts.URLs[:start]...)
+	if p.opts.ServersPerConfig > 0 && p.opts.ServersPerConfig < len(urls) {
+		urls = urls[:p.opts.ServersPerConfig]
+	}
+
+	return WebRTCConfig{
+		ICEServers: []*webrtcpb.ICEServer{{
+			Urls:       urls,
+			Username:   username,
+			Credential: password,
+		}},
+		Expires: now.Add(p.opts.TTL / 2),
+	}, nil
+}
+
+// TURNRESTCredentials returns TURN REST API credentials valid until expires: a username made
+// of the expiry time, in seconds since the Unix epoch, and the optional user, and a password
+// that is the base64 encoded HMAC-SHA1 of the username keyed with the shared secret.
+func TURNRESTCredentials(sharedSecret, user string, expires time.Time) (username, password string) {
+	username = strconv.FormatInt(expires.Unix(), 10)
+	if user != "" {
+		username += ":" + user
+	}
+	mac := hmac.New(sha1.New, []byte(sharedSecret))
+	mac.Write([]byte(username))
+	return username, base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
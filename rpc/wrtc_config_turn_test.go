@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pion/turn/v2"
+	"go.viam.com/test"
+)
+
+func TestTURNRESTConfigProvider(t *testing.T) {
+	t.Run("options", func(t *testing.T) {
+		_, err := NewTURNRESTConfigProvider(TURNRESTOptions{SharedSecret: "secret"})
+		test.That(t, err, test.ShouldBeError, "expected at least one TURN URL")
+		_, err = NewTURNRESTConfigProvider(TURNRESTOptions{URLs: []string{"turn:example.com"}})
+		test.That(t, err, test.ShouldBeError, "expected a shared secret")
+		_, err = NewTURNRESTConfigProvider(TURNRESTOptions{URLs: []string{"stun:example.com"}, SharedSecret: "secret"})
+		test.That(t, err, test.ShouldBeError, `expected a turn or turns URL but got "stun:example.com"`)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		provider, err := NewTURNRESTConfigProvider(TURNRESTOptions{
+			URLs:             []string{"turn:a.example.com", "turn:b.example.com", "turns:c.example.com"},
+			SharedSecret:     "secret",
+			TTL:              time.Hour,
+			User:             "alice",
+			ServersPerConfig: 2,
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		for _, expected := range [][]string{
+			{"turn:a.example.com", "turn:b.example.com"},
+			{"turn:b.example.com", "turns:c.example.com"},
+			{"turns:c.example.com", "turn:a.example.com"},
+			{"turn:a.example.com", "turn:b.example.com"},
+		} {
+			before := time.Now()
+			config, err := provider.Config(context.Background())
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, config.ICEServers, test.ShouldHaveLength, 1)
+			test.That(t, config.ICEServers[0].GetUrls(), test.ShouldResemble, expected)
+
+			// the config expires halfway through the credentials' lifetime.
+			test.That(t, config.Expires, test.ShouldHappenOnOrBetween, before.Add(30*time.Minute), time.Now().Add(30*time.Minute))
+			credentialsExpire := config.Expires.Add(30 * time.Minute).Truncate(time.Second)
+			username, password := TURNRESTCredentials("secret", "alice", credentialsExpire)
+			test.That(t, config.ICEServers[0].GetUsername(), test.ShouldEqual, username)
+			test.That(t, config.ICEServers[0].GetUsername(), test.ShouldEqual,
+				strconv.FormatInt(credentialsExpire.Unix(), 10)+":alice")
+			test.That(t, config.ICEServers[0].GetCredential(), test.ShouldEqual, password)
+		}
+	})
+
+	t.Run("turn server", func(t *testing.T) {
+		sharedSecret := "shh"
+		serverConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		test.That(t, err, test.ShouldBeNil)
+		turnServer, err := turn.NewServer(turn.ServerConfig{
+			Realm:       "viam",
+			AuthHandler: turn.NewLongTermAuthHandler(sharedSecret, nil),
+			PacketConnConfigs: []turn.PacketConnConfig{{
+				PacketConn: serverConn,
+				RelayAddressGenerator: &turn.RelayAddressGeneratorStatic{
+					RelayAddress: net.ParseIP("127.0.0.1"),
+					Address:      "127.0.0.1",
+				},
+			}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, turnServer.Close(), test.ShouldBeNil)
+		}()
+
+		allocate := func(t *testing.T, username, password string) error {
+			t.Helper()
+			clientConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+			test.That(t, err, test.ShouldBeNil)
+			defer func() {
+				test.That(t, clientConn.Close(), test.ShouldBeNil)
+			}()
+			client, err := turn.NewClient(&turn.ClientConfig{
+				TURNServerAddr: serverConn.LocalAddr().String(),
+				Username:       username,
+				Password:       password,
+				Conn:           clientConn,
+			})
+			test.That(t, err, test.ShouldBeNil)
+			defer client.Close()
+			test.That(t, client.Listen(), test.ShouldBeNil)
+			relayConn, err := client.Allocate()
+			if err != nil {
+				return err
+			}
+			return relayConn.Close()
+		}
+
+		provider, err := NewTURNRESTConfigProvider(TURNRESTOptions{
+			URLs:         []string{"turn:" + serverConn.LocalAddr().String()},
+			SharedSecret: sharedSecret,
+			TTL:          time.Minute,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		config, err := provider.Config(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		iceServer := config.ICEServers[0]
+		test.That(t, allocate(t, iceServer.GetUsername(), iceServer.GetCredential()), test.ShouldBeNil)
+
+		username, password := TURNRESTCredentials("wrong", "", config.Expires)
+		test.That(t, allocate(t, username, password), test.ShouldNotBeNil)
+
+		username, password = TURNRESTCredentials(sharedSecret, "", time.Now().Add(-time.Minute))
+		test.That(t, allocate(t, username, password), test.ShouldNotBeNil)
+	})
+}
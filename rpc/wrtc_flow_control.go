@@ -0,0 +1,287 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils/perf/statz"
+	"go.viam.com/utils/perf/statz/units"
+)
+
+// Flow control of gRPC over WebRTC works much like HTTP/2 flow control. Each receiver of
+// messages grants the sender a window of bytes, per stream and per connection, that the sender
+// may have in flight. The receiver hands bytes back with WindowUpdate frames once the messages
+// holding them are read. Peers that do not advertise windows are not flow controlled, in which
+// case a receiver holds at most one unread message per stream by blocking the data channel.
+// Streams of peers that send more than a packet past their windows are reset with
+// RESOURCE_EXHAUSTED.
+
+const (
+	defaultStreamWindowSize = 1 << 20
+	defaultConnWindowSize   = 4 << 20
+
+	// flowControlPacketOverhead is added to the data length of every packet counted against a
+	// window so that empty messages use up windows as well.
+	flowControlPacketOverhead = 16
+
+	// flowControlPacketSlack is how far past its window a receiver lets a sender go. Senders may
+	// send while their window is open at all, so the packet that closes a window overshoots it
+	// by up to the cost of the largest packet. Packets of a connection sent over different data
+	// channels may also arrive in a different order than they were sent, which only changes
+	// which packet is counted as the overshooting one.
+	flowControlPacketSlack = maxDataChannelSize + flowControlPacketOverhead
+
+	// flowControlMaxQueuedMessages caps how many unread messages a flow controlled stream holds.
+	// Windows bound their bytes but many small messages still fit in one, so once the cap is
+	// reached the data channel is blocked as it is for peers that are not flow controlled.
+	flowControlMaxQueuedMessages = 1024
+)
+
+// errFlowControlWindowExceeded fails streams whose peer sent more than its windows allow.
+var errFlowControlWindowExceeded = status.Error(codes.ResourceExhausted, "peer exceeded its flow control window")
+
+var (
+	flowControlStalls = statz.NewCounter2[string, string]("webrtc/flow_control_stalls", statz.MetricConfig{
+		Description: "The number of times sending a message waited for the peer to open its flow control window.",
+		Unit:        units.Dimensionless,
+		Labels: []statz.Label{
+			{Name: "side", Description: "The side of the connection that stalled ('client' or 'server')."},
+			{Name: "window", Description: "The window that was exhausted ('stream' or 'connection')."},
+		},
+	})
+
+	flowControlStallDuration = statz.NewDistribution1[string]("webrtc/flow_control_stall_duration", statz.MetricConfig{
+		Description: "How long sending a message waited for the peer to open its flow control window.",
+		Unit:        units.Milliseconds,
+		Labels: []statz.Label{
+			{Name: "side", Description: "The side of the connection that stalled ('client' or 'server')."},
+		},
+	}, statz.LatencyDistribution)
+
+	flowControlStalledStreams = statz.NewGauge1[string]("webrtc/flow_control_stalled_streams", statz.MetricConfig{
+		Description: "The number of streams currently waiting for the peer to open its flow control window.",
+		Unit:        units.Dimensionless,
+		Labels: []statz.Label{
+			{Name: "side", Description: "The side of the connection that is stalled ('client' or 'server')."},
+		},
+	})
+
+	stalledClientStreams atomic.Int64
+	stalledServerStreams atomic.Int64
+)
+
+// flowControlWindowSize returns the configured window size or the default when unset.
+func flowControlWindowSize(configured int32, defaultSize int64) int64 {
+	if configured <= 0 {
+		return defaultSize
+	}
+	return int64(configured)
+}
+
+// packetCost returns how many bytes of a window a packet with the given data length uses.
+func packetCost(dataLen int) int64 {
+	return int64(dataLen) + flowControlPacketOverhead
+}
+
+// A sendWindow is the number of bytes a sender may still send before it must wait for the
+// receiver to hand some back.
+type sendWindow struct {
+	mu        sync.Mutex
+	available int64
+	closed    bool
+	// changed is closed and replaced whenever available or closed changes.
+	changed chan struct{}
+}
+
+func newSendWindow(size int64) *sendWindow {
+	return &sendWindow{available: size, changed: make(chan struct{})}
+}
+
+// add hands n bytes back to the window.
+func (w *sendWindow) add(n int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.available += n
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// close wakes up and fails anyone waiting on the window, now and in the future.
+func (w *sendWindow) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+func (w *sendWindow) lock() {
+	if w != nil {
+		w.mu.Lock()
+	}
+}
+
+func (w *sendWindow) unlock() {
+	if w != nil {
+		w.mu.Unlock()
+	}
+}
+
+// checkLocked reports whether the window is open and returns a channel that is closed the next
+// time the window changes. w.mu must be held.
+func (w *sendWindow) checkLocked() (bool, <-chan struct{}, error) {
+	if w == nil {
+		return true, nil, nil
+	}
+	if w.closed {
+		return false, nil, io.ErrClosedPipe
+	}
+	return w.available > 0, w.changed, nil
+}
+
+// takeLocked takes n bytes from the window. w.mu must be held.
+func (w *sendWindow) takeLocked(n int64) {
+	if w != nil {
+		w.available -= n
+	}
+}
+
+// tryTakeSendWindows takes n bytes from both windows if both are open. Both windows are locked,
+// the stream window first, for the check and the take so that concurrent streams cannot all
+// find the connection window open and overshoot it together. Otherwise it returns channels that
+// are closed the next time each window changes.
+func tryTakeSendWindows(stream, conn *sendWindow, n int64) (
+	streamOpen, connOpen bool,
+	streamChanged, connChanged <-chan struct{},
+	err error,
+) {
+	stream.lock()
+	defer stream.unlock()
+	conn.lock()
+	defer conn.unlock()
+	streamOpen, streamChanged, err = stream.checkLocked()
+	if err != nil {
+		return false, false, nil, nil, err
+	}
+	connOpen, connChanged, err = conn.checkLocked()
+	if err != nil {
+		return false, false, nil, nil, err
+	}
+	if streamOpen && connOpen {
+		stream.takeLocked(n)
+		conn.takeLocked(n)
+	}
+	return streamOpen, connOpen, streamChanged, connChanged, nil
+}
+
+// waitForSendWindows waits until both the stream and the connection window are open and then
+// takes n bytes from each. Either window may be nil when that direction is not flow controlled.
+// Stalls are reported as metrics for the given side.
+func waitForSendWindows(ctx context.Context, side string, stream, conn *sendWindow, n int64) error {
+	if stream == nil && conn == nil {
+		return nil
+	}
+	var stalledAt time.Time
+	defer func() {
+		if stalledAt.IsZero() {
+			return
+		}
+		flowControlStallDuration.Observe(float64(time.Since(stalledAt).Milliseconds()), side)
+		flowControlStalledStreams.Set(side, stalledStreams(side).Add(-1))
+	}()
+	var streamStalled, connStalled bool
+	for {
+		// both windows are waited on together so that closing the stream also wakes up a
+		// sender waiting for the connection window.
+		streamOpen, connOpen, streamChanged, connChanged, err := tryTakeSendWindows(stream, conn, n)
+		if err != nil {
+			return err
+		}
+		if streamOpen && connOpen {
+			return nil
+		}
+		if stalledAt.IsZero() {
+			stalledAt = time.Now()
+			flowControlStalledStreams.Set(side, stalledStreams(side).Add(1))
+		}
+		if !streamOpen && !streamStalled {
+			streamStalled = true
+			flowControlStalls.Inc(side, "stream")
+		}
+		if !connOpen && !connStalled {
+			connStalled = true
+			flowControlStalls.Inc(side, "connection")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-streamChanged:
+		case <-connChanged:
+		}
+	}
+}
+
+func stalledStreams(side string) *atomic.Int64 {
+	if side == "server" {
+		return &stalledServerStreams
+	}
+	return &stalledClientStreams
+}
+
+// A recvWindow collects bytes read by the receiver until there are enough of them to be worth
+// handing back to the sender in a WindowUpdate. It also keeps track of how much of the window
+// the sender has used so that senders that do not honor it are caught.
+type recvWindow struct {
+	mu      sync.Mutex
+	size    int64
+	pending int64
+	// outstanding is how many received bytes have not been handed back to the sender yet.
+	outstanding int64
+}
+
+func newRecvWindow(size int64) *recvWindow {
+	return &recvWindow{size: size}
+}
+
+// receive records n bytes received from the sender. It reports false if the sender went further
+// past its window than flowControlPacketSlack allows.
+func (w *recvWindow) receive(n int64) bool {
+	if w == nil {
+		return true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.outstanding+n > w.size+flowControlPacketSlack {
+		return false
+	}
+	w.outstanding += n
+	return true
+}
+
+// consume records n read bytes and returns how many bytes to hand back, if any. Bytes are
+// handed back once a quarter of the window has been read.
+func (w *recvWindow) consume(n int64) uint32 {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending += n
+	if w.pending < w.size/4 {
+		return 0
+	}
+	increment := min(w.pending, math.MaxUint32)
+	w.pending -= increment
+	w.outstanding = max(w.outstanding-increment, 0)
+	return uint32(increment)
+}
@@ -0,0 +1,362 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	pbstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/utils"
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestSendWindow(t *testing.T) {
+	w := newSendWindow(10)
+	test.That(t, waitForSendWindows(context.Background(), "client", w, nil, 15), test.ShouldBeNil)
+
+	// the window is exhausted until bytes are handed back.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	test.That(t, waitForSendWindows(ctx, "client", w, nil, 1), test.ShouldBeError, context.DeadlineExceeded)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- waitForSendWindows(context.Background(), "client", w, nil, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	test.That(t, stalledClientStreams.Load(), test.ShouldEqual, 1)
+	w.add(5)
+	select {
+	case <-errCh:
+		t.Fatal("wait should not return while the window is exhausted")
+	case <-time.After(10 * time.Millisecond):
+	}
+	w.add(1)
+	test.That(t, <-errCh, test.ShouldBeNil)
+	test.That(t, stalledClientStreams.Load(), test.ShouldEqual, 0)
+	w.mu.Lock()
+	test.That(t, w.available, test.ShouldEqual, 0)
+	w.mu.Unlock()
+
+	// the connection window is waited on as well.
+	conn := newSendWindow(0)
+	go func() {
+		errCh <- waitForSendWindows(context.Background(), "server", newSendWindow(10), conn, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	conn.add(1)
+	test.That(t, <-errCh, test.ShouldBeNil)
+
+	// closing fails waiters.
+	go func() {
+		errCh <- waitForSendWindows(context.Background(), "client", w, nil, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	w.close()
+	test.That(t, <-errCh, test.ShouldBeError, io.ErrClosedPipe)
+
+	test.That(t, waitForSendWindows(context.Background(), "client", nil, nil, 100), test.ShouldBeNil)
+}
+
+func TestRecvWindow(t *testing.T) {
+	w := newRecvWindow(100)
+	test.That(t, w.consume(10), test.ShouldEqual, 0)
+	test.That(t, w.consume(10), test.ShouldEqual, 0)
+	test.That(t, w.consume(5), test.ShouldEqual, 25)
+	test.That(t, w.consume(24), test.ShouldEqual, 0)
+	test.That(t, w.consume(100), test.ShouldEqual, 124)
+
+	var nilWindow *recvWindow
+	test.That(t, nilWindow.consume(1000), test.ShouldEqual, 0)
+	test.That(t, nilWindow.receive(1000), test.ShouldBeTrue)
+
+	// senders may overshoot the window by up to a packet but no further.
+	w = newRecvWindow(100)
+	test.That(t, w.receive(60), test.ShouldBeTrue)
+	test.That(t, w.receive(flowControlPacketSlack), test.ShouldBeTrue)
+	test.That(t, w.receive(41), test.ShouldBeFalse)
+	test.That(t, w.receive(40), test.ShouldBeTrue)
+	test.That(t, w.receive(1), test.ShouldBeFalse)
+	test.That(t, w.consume(60), test.ShouldEqual, 60)
+	test.That(t, w.receive(60), test.ShouldBeTrue)
+	test.That(t, w.receive(1), test.ShouldBeFalse)
+}
+
+func TestSendWindowsConcurrentStreams(t *testing.T) {
+	// streams racing for the last bytes of the connection window overshoot it by one packet at
+	// most.
+	const streams = 500
+	conn := newSendWindow(100)
+	var wg sync.WaitGroup
+	var sent atomic.Int64
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := make(chan struct{})
+	for i := 0; i < streams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if waitForSendWindows(ctx, "client", newSendWindow(1000), conn, 60) == nil {
+				sent.Add(60)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	test.That(t, sent.Load(), test.ShouldEqual, 120)
+
+	recv := newRecvWindow(100)
+	for sent := int64(0); sent < 120; sent += 60 {
+		test.That(t, recv.receive(60), test.ShouldBeTrue)
+	}
+}
+
+func TestMessageQueue(t *testing.T) {
+	q := newMessageQueue()
+	test.That(t, q.empty(), test.ShouldBeTrue)
+	test.That(t, q.push(context.Background(), queuedMessage{data: []byte("1")}, 0), test.ShouldBeTrue)
+	test.That(t, q.push(context.Background(), queuedMessage{data: []byte("2")}, 0), test.ShouldBeTrue)
+	test.That(t, q.empty(), test.ShouldBeFalse)
+
+	// a limited push waits for room.
+	pushed := make(chan bool, 1)
+	go func() {
+		pushed <- q.push(context.Background(), queuedMessage{data: []byte("3")}, 2)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push should have waited")
+	case <-time.After(10 * time.Millisecond):
+	}
+	msg, ok, closed, _ := q.pop()
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, closed, test.ShouldBeFalse)
+	test.That(t, string(msg.data), test.ShouldEqual, "1")
+	test.That(t, <-pushed, test.ShouldBeTrue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	test.That(t, q.push(ctx, queuedMessage{data: []byte("4")}, 2), test.ShouldBeFalse)
+
+	// queued messages can be read after closing.
+	q.close()
+	test.That(t, q.push(context.Background(), queuedMessage{data: []byte("5")}, 0), test.ShouldBeFalse)
+	for _, expected := range []string{"2", "3"} {
+		msg, ok, closed, _ := q.pop()
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, closed, test.ShouldBeTrue)
+		test.That(t, string(msg.data), test.ShouldEqual, expected)
+	}
+	_, ok, closed, _ = q.pop()
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, closed, test.ShouldBeTrue)
+}
+
+func TestWebRTCFlowControl(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+	pc1, pc2, dc1, dc2 := setupWebRTCPeers(t)
+	defer pc1.GracefulClose()
+	defer pc2.GracefulClose()
+
+	const windowSize = 64 * 1024
+	const numMessages = 100
+	message := &pbstatus.Status{Message: string(make([]byte, 10*1024))}
+
+	clientCh := newWebRTCClientChannel(pc1, dc1, nil, utils.Sublogger(logger, "client"), nil, nil)
+	defer func() {
+		test.That(t, clientCh.Close(), test.ShouldBeNil)
+	}()
+	clientCh.configureFlowControl(windowSize, 0)
+
+	server := newWebRTCServer(logger)
+	server.streamWindowSize = windowSize
+	defer server.Stop()
+	server.RegisterService(&echopb.EchoService_ServiceDesc, &echoserver.Server{})
+
+	releaseServer := make(chan struct{})
+	var serverReceived atomic.Int64
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "service_name",
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName: "send",
+				Handler: grpc.StreamHandler(func(srv any, stream grpc.ServerStream) error {
+					for i := 0; i < numMessages; i++ {
+						if err := stream.SendMsg(message); err != nil {
+							return err
+						}
+					}
+					return nil
+				}),
+				ServerStreams: true,
+			},
+			{
+				StreamName: "hold",
+				Handler: grpc.StreamHandler(func(srv any, stream grpc.ServerStream) error {
+					<-stream.Context().Done()
+					return stream.Context().Err()
+				}),
+				ClientStreams: true,
+			},
+			{
+				StreamName: "receive",
+				Handler: grpc.StreamHandler(func(srv any, stream grpc.ServerStream) error {
+					<-releaseServer
+					for {
+						var msg pbstatus.Status
+						if err := stream.RecvMsg(&msg); err != nil {
+							if errors.Is(err, io.EOF) {
+								return stream.SendMsg(&pbstatus.Status{})
+							}
+							return err
+						}
+						serverReceived.Add(1)
+					}
+				}),
+				ClientStreams: true,
+			},
+		},
+	}, nil)
+
+	serverCh := newWebRTCServerChannel(server, pc2, dc2, nil, logger)
+	defer serverCh.Close()
+
+	<-clientCh.Ready()
+	<-serverCh.Ready()
+
+	echoClient := echopb.NewEchoServiceClient(clientCh)
+
+	t.Run("a client not reading does not block other calls", func(t *testing.T) {
+		clientStream, err := clientCh.NewStream(
+			context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/service_name/send")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, clientStream.SendMsg(&pbstatus.Status{}), test.ShouldBeNil)
+		test.That(t, clientStream.CloseSend(), test.ShouldBeNil)
+
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, stalledServerStreams.Load(), test.ShouldEqual, 1)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := echoClient.Echo(ctx, &echopb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+
+		for i := 0; i < numMessages; i++ {
+			var msg pbstatus.Status
+			test.That(t, clientStream.RecvMsg(&msg), test.ShouldBeNil)
+			test.That(t, msg.GetMessage(), test.ShouldHaveLength, len(message.GetMessage()))
+		}
+		var msg pbstatus.Status
+		test.That(t, clientStream.RecvMsg(&msg), test.ShouldEqual, io.EOF)
+		test.That(t, stalledServerStreams.Load(), test.ShouldEqual, 0)
+	})
+
+	t.Run("a server not reading pushes back on the client", func(t *testing.T) {
+		// the server has said it supports flow control in the previous calls.
+		clientCh.mu.Lock()
+		test.That(t, clientCh.serverStreamWindowSize, test.ShouldEqual, windowSize)
+		clientCh.mu.Unlock()
+
+		clientStream, err := clientCh.NewStream(
+			context.Background(), &grpc.StreamDesc{ClientStreams: true}, "/service_name/receive")
+		test.That(t, err, test.ShouldBeNil)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < numMessages; i++ {
+				test.That(t, clientStream.SendMsg(message), test.ShouldBeNil)
+			}
+			test.That(t, clientStream.CloseSend(), test.ShouldBeNil)
+		}()
+
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, stalledClientStreams.Load(), test.ShouldEqual, 1)
+		})
+		close(releaseServer)
+		wg.Wait()
+
+		var msg pbstatus.Status
+		test.That(t, clientStream.RecvMsg(&msg), test.ShouldBeNil)
+		test.That(t, serverReceived.Load(), test.ShouldEqual, numMessages)
+		test.That(t, stalledClientStreams.Load(), test.ShouldEqual, 0)
+	})
+
+	t.Run("errors stop waiting senders", func(t *testing.T) {
+		clientStream, err := clientCh.NewStream(
+			context.Background(), &grpc.StreamDesc{ClientStreams: true}, "/service_name/unknown")
+		test.That(t, err, test.ShouldBeNil)
+		var sendErr error
+		for i := 0; i < numMessages && sendErr == nil; i++ {
+			sendErr = clientStream.SendMsg(message)
+		}
+		var msg pbstatus.Status
+		err = clientStream.RecvMsg(&msg)
+		test.That(t, status.Convert(err).Message(), test.ShouldEqual, "Unimplemented")
+		test.That(t, stalledClientStreams.Load(), test.ShouldEqual, 0)
+	})
+
+	t.Run("a client ignoring its window is reset", func(t *testing.T) {
+		clientStream, err := clientCh.NewStream(
+			context.Background(), &grpc.StreamDesc{ClientStreams: true}, "/service_name/hold")
+		test.That(t, err, test.ShouldBeNil)
+		// stop honoring the windows the server granted.
+		cs, ok := clientStream.(*webrtcClientStream)
+		test.That(t, ok, test.ShouldBeTrue)
+		cs.sendWindow = nil
+		cs.connSendWindow = nil
+
+		for i := 0; i < numMessages; i++ {
+			if err := clientStream.SendMsg(message); err != nil {
+				break
+			}
+		}
+		var msg pbstatus.Status
+		err = clientStream.RecvMsg(&msg)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.ResourceExhausted)
+
+		// the connection is still usable.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := echoClient.Echo(ctx, &echopb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+	})
+
+	t.Run("windows are handed back for abandoned streams", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			clientStream, err := clientCh.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/service_name/send")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, clientStream.SendMsg(&pbstatus.Status{}), test.ShouldBeNil)
+			var msg pbstatus.Status
+			test.That(t, clientStream.RecvMsg(&msg), test.ShouldBeNil)
+			cancel()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := echoClient.Echo(ctx, &echopb.EchoRequest{Message: fmt.Sprint(numMessages)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, fmt.Sprint(numMessages))
+	})
+}
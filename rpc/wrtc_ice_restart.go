@@ -0,0 +1,294 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/pion/sdp/v3"
+	"github.com/pkg/errors"
+	"github.com/viamrobotics/webrtc/v3"
+	"google.golang.org/grpc/metadata"
+
+	"go.viam.com/utils"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+)
+
+// iceRestartSDPAttribute marks the offers of ICE restarts so that answerers renegotiate the peer
+// connection the offer belongs to rather than make a new one. Answerers that predate ICE
+// restarts ignore it and answer with a new peer connection, which never connects.
+const iceRestartSDPAttribute = "x-viam-ice-restart"
+
+// iceRestartRetryWait is how long to wait between ICE restart attempts that do not reach the
+// peer.
+var iceRestartRetryWait = time.Second
+
+var (
+	errICERestartsDisabled       = errors.New("ICE restarts are not enabled")
+	errNoPeerConnectionToRestart = errors.New("no peer connection to restart ICE for")
+)
+
+// enableICERestart keeps the channel open for timeout after its connection disconnects, calling
+// restart, if set, until the connection recovers. It is a no-op when timeout is not positive.
+func (ch *webrtcBaseChannel) enableICERestart(timeout time.Duration, restart func(ctx context.Context) error) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.iceRestartTimeout = timeout
+	ch.restartICE = restart
+}
+
+// awaitICERestart starts waiting for a disconnected connection to recover and reports whether
+// it did. It does not when ICE restarts are not enabled or the channel is closed.
+func (ch *webrtcBaseChannel) awaitICERestart(connID string) bool {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.iceRestartTimeout <= 0 || ch.closed.Load() {
+		return false
+	}
+	if ch.iceRestartTimer == nil {
+		timeout := ch.iceRestartTimeout
+		ch.iceRestartTimer = time.AfterFunc(timeout, func() {
+			ch.logger.Infow("connection did not recover; closing", "conn_id", connID, "timeout", timeout)
+			ch.Close()
+		})
+	}
+	if ch.restartICE != nil && !ch.iceRestarting {
+		ch.iceRestarting = true
+		ch.activeBackgroundWorkers.Add(1)
+		go func() {
+			defer ch.activeBackgroundWorkers.Done()
+			ch.runICERestart(connID)
+		}()
+	}
+	return true
+}
+
+// runICERestart calls restartICE until an attempt is answered, the connection recovers on its
+// own or the channel is closed.
+func (ch *webrtcBaseChannel) runICERestart(connID string) {
+	defer func() {
+		ch.mu.Lock()
+		ch.iceRestarting = false
+		ch.mu.Unlock()
+	}()
+	for {
+		ch.mu.Lock()
+		recovered := ch.iceRestartTimer == nil
+		ch.mu.Unlock()
+		if recovered {
+			return
+		}
+
+		err := ch.restartICE(ch.ctx)
+		if err == nil {
+			ch.logger.Debugw("restarted ICE", "conn_id", connID)
+			return
+		}
+		ch.logger.Infow("failed to restart ICE; retrying", "conn_id", connID, "error", err)
+		if !utils.SelectContextOrWait(ch.ctx, iceRestartRetryWait) {
+			return
+		}
+	}
+}
+
+// stopICERestartTimer stops waiting for the connection to recover, as it has.
+func (ch *webrtcBaseChannel) stopICERestartTimer() {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.iceRestartTimer == nil {
+		return
+	}
+	ch.iceRestartTimer.Stop()
+	ch.iceRestartTimer = nil
+	ch.logger.Info("connection recovered")
+}
+
+// restartICE renegotiates ICE for the peer connection of a client with new candidates. The
+// offer cannot go over the peer connection itself, so it goes through the signaling server
+// like the offer that made the connection did, with every candidate in it.
+func restartICE(
+	ctx context.Context,
+	peerConn *webrtc.PeerConnection,
+	signalingServer string,
+	host string,
+	dOpts dialOptions,
+	logger utils.ZapCompatibleLogger,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, getDefaultOfferDeadline())
+	defer cancel()
+
+	signalingServers := dOpts.webrtcOpts.signalingServers(ctx, signalingServer, logger)
+	signalingConn, err := connectSignalingServers(ctx, signalingServers, host, logger, dOpts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		utils.UncheckedError(signalingConn.conn.Close())
+	}()
+
+	offer, err := peerConn.CreateOffer(&webrtc.OfferOptions{ICERestart: true})
+	if err != nil {
+		return err
+	}
+	gatherComplete := webrtc.GatheringCompletePromise(peerConn)
+	if err := peerConn.SetLocalDescription(offer); err != nil {
+		return err
+	}
+	select {
+	case <-gatherComplete:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	encodedSDP, err := encodeICERestartOffer(peerConn.LocalDescription())
+	if err != nil {
+		return err
+	}
+
+	md := metadata.New(map[string]string{RPCHostMetadataField: host})
+	if dOpts.webrtcOpts.CallPriority != WebRTCCallPriorityNormal {
+		md.Set(CallPriorityMetadataField, dOpts.webrtcOpts.CallPriority.String())
+	}
+	signalCtx := metadata.NewOutgoingContext(ctx, md)
+	signalingClient := webrtcpb.NewSignalingServiceClient(signalingConn.conn)
+	callClient, err := signalingClient.Call(signalCtx, &webrtcpb.CallRequest{Sdp: encodedSDP, DisableTrickle: true})
+	if err != nil {
+		return err
+	}
+	callResp, err := callClient.Recv()
+	if err != nil {
+		return err
+	}
+	initStage, ok := callResp.GetStage().(*webrtcpb.CallResponse_Init)
+	if !ok {
+		return errors.Errorf("unexpected stage %T", callResp.GetStage())
+	}
+	var answer webrtc.SessionDescription
+	if err := DecodeSDP(initStage.Init.GetSdp(), &answer); err != nil {
+		return err
+	}
+	if err := peerConn.SetRemoteDescription(answer); err != nil {
+		return err
+	}
+
+	if _, err := signalingClient.CallUpdate(signalCtx, &webrtcpb.CallUpdateRequest{
+		Uuid:   callResp.GetUuid(),
+		Update: &webrtcpb.CallUpdateRequest_Done{Done: true},
+	}); err != nil {
+		logger.Warnw("Error sending CallUpdate", "err", err)
+	}
+	return nil
+}
+
+// encodeICERestartOffer encodes the offer of an ICE restart marked with iceRestartSDPAttribute.
+func encodeICERestartOffer(offer *webrtc.SessionDescription) (string, error) {
+	var parsed sdp.SessionDescription
+	if err := parsed.UnmarshalString(offer.SDP); err != nil {
+		return "", err
+	}
+	marked, err := parsed.WithPropertyAttribute(iceRestartSDPAttribute).Marshal()
+	if err != nil {
+		return "", err
+	}
+	return EncodeSDP(&webrtc.SessionDescription{Type: offer.Type, SDP: string(marked)})
+}
+
+// peerConnToRestart returns the peer connection the given offer restarts ICE for along with the
+// decoded offer, or a nil peer connection if the offer is not for an ICE restart. Offers belong
+// to the peer connection whose remote description has the same SDP session and DTLS
+// fingerprint; both stay the same across renegotiations of a peer connection.
+func (srv *webrtcServer) peerConnToRestart(encodedOffer string) (*webrtc.PeerConnection, webrtc.SessionDescription, error) {
+	var offer webrtc.SessionDescription
+	if err := DecodeSDP(encodedOffer, &offer); err != nil {
+		return nil, offer, err
+	}
+	var parsed sdp.SessionDescription
+	if err := parsed.UnmarshalString(offer.SDP); err != nil {
+		return nil, offer, err
+	}
+	if _, ok := parsed.Attribute(iceRestartSDPAttribute); !ok {
+		return nil, offer, nil
+	}
+	if srv.iceRestartTimeout <= 0 {
+		return nil, offer, errICERestartsDisabled
+	}
+	fingerprint := sdpFingerprint(&parsed)
+	if fingerprint == "" {
+		return nil, offer, errNoPeerConnectionToRestart
+	}
+
+	srv.peerConnsMu.Lock()
+	peerConns := make(map[*webrtc.PeerConnection]*webrtcServerChannel, len(srv.peerConns))
+	for pc, ch := range srv.peerConns {
+		peerConns[pc] = ch
+	}
+	srv.peerConnsMu.Unlock()
+	for pc, ch := range peerConns {
+		remote := pc.RemoteDescription()
+		if ch.Closed() || remote == nil {
+			continue
+		}
+		var remoteParsed sdp.SessionDescription
+		if err := remoteParsed.UnmarshalString(remote.SDP); err != nil {
+			continue
+		}
+		if remoteParsed.Origin.SessionID == parsed.Origin.SessionID && sdpFingerprint(&remoteParsed) == fingerprint {
+			return pc, offer, nil
+		}
+	}
+	return nil, offer, errNoPeerConnectionToRestart
+}
+
+// sdpFingerprint returns the first DTLS fingerprint of the session description.
+func sdpFingerprint(desc *sdp.SessionDescription) string {
+	if fingerprint, ok := desc.Attribute("fingerprint"); ok {
+		return fingerprint
+	}
+	for _, media := range desc.MediaDescriptions {
+		if fingerprint, ok := media.Attribute("fingerprint"); ok {
+			return fingerprint
+		}
+	}
+	return ""
+}
+
+// restartICE answers an ICE restart offer for a peer connection that is already established,
+// with every candidate in the answer.
+func (aa *answerAttempt) restartICE(ctx context.Context, pc *webrtc.PeerConnection, offer webrtc.SessionDescription) error {
+	if err := pc.SetRemoteDescription(offer); err != nil {
+		aa.sendError(err)
+		return err
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		aa.sendError(err)
+		return err
+	}
+	gatherComplete := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		aa.sendError(err)
+		return err
+	}
+	select {
+	case <-gatherComplete:
+	case <-ctx.Done():
+		aa.sendError(ctx.Err())
+		return ctx.Err()
+	}
+
+	encodedSDP, err := EncodeSDP(pc.LocalDescription())
+	if err != nil {
+		aa.sendError(err)
+		return err
+	}
+	if err := aa.client.Send(&webrtcpb.AnswerResponse{
+		Uuid: aa.uuid,
+		Stage: &webrtcpb.AnswerResponse_Init{
+			Init: &webrtcpb.AnswerResponseInitStage{
+				Sdp: encodedSDP,
+			},
+		},
+	}); err != nil {
+		return err
+	}
+	aa.sendDone()
+	return nil
+}
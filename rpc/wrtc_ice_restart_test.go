@@ -0,0 +1,176 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"github.com/viamrobotics/webrtc/v3"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestWebRTCICERestartWindow(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+
+	t.Run("disabled", func(t *testing.T) {
+		pc1, pc2, ch, _ := setupWebRTCBaseChannels(t)
+		defer pc1.GracefulClose()
+		defer pc2.GracefulClose()
+		defer ch.Close()
+
+		test.That(t, ch.awaitICERestart("conn"), test.ShouldBeFalse)
+	})
+
+	t.Run("closes once the window is over", func(t *testing.T) {
+		pc1, pc2, ch, _ := setupWebRTCBaseChannels(t)
+		defer pc1.GracefulClose()
+		defer pc2.GracefulClose()
+		defer ch.Close()
+
+		var attempts atomic.Int64
+		ch.enableICERestart(200*time.Millisecond, func(ctx context.Context) error {
+			attempts.Add(1)
+			return errors.New("signaling server unreachable")
+		})
+		test.That(t, ch.awaitICERestart("conn"), test.ShouldBeTrue)
+		// a failed state following a disconnected one keeps the same window.
+		test.That(t, ch.awaitICERestart("conn"), test.ShouldBeTrue)
+		test.That(t, ch.Closed(), test.ShouldBeFalse)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, ch.Closed(), test.ShouldBeTrue)
+		})
+		test.That(t, attempts.Load(), test.ShouldEqual, 1)
+	})
+
+	t.Run("stays open once recovered", func(t *testing.T) {
+		pc1, pc2, ch, _ := setupWebRTCBaseChannels(t)
+		defer pc1.GracefulClose()
+		defer pc2.GracefulClose()
+		defer ch.Close()
+
+		restarted := make(chan struct{})
+		ch.enableICERestart(200*time.Millisecond, func(ctx context.Context) error {
+			close(restarted)
+			return nil
+		})
+		test.That(t, ch.awaitICERestart("conn"), test.ShouldBeTrue)
+		<-restarted
+		ch.stopICERestartTimer()
+		time.Sleep(400 * time.Millisecond)
+		test.That(t, ch.Closed(), test.ShouldBeFalse)
+	})
+}
+
+func TestWebRTCICERestart(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	// serve starts a signaling server with a server answering for host behind it.
+	serve := func(t *testing.T, host string, webrtcOpts WebRTCServerOptions) (string, *webrtcServer) {
+		t.Helper()
+		signalingCallQueue := NewMemoryWebRTCCallQueue(logger)
+		signalingServer := NewWebRTCSignalingServer(signalingCallQueue, nil, logger, defaultHeartbeatInterval)
+		grpcListener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		grpcServer := grpc.NewServer()
+		grpcServer.RegisterService(&webrtcpb.SignalingService_ServiceDesc, signalingServer)
+		serveDone := make(chan error)
+		go func() {
+			serveDone <- grpcServer.Serve(grpcListener)
+		}()
+
+		server := newWebRTCServerWithOptions(logger, nil, nil, nil, nil, webrtcOpts)
+		server.RegisterService(&echopb.EchoService_ServiceDesc, &echoserver.Server{})
+		answerer := newWebRTCSignalingAnswerer(
+			grpcListener.Addr().String(),
+			[]string{host},
+			server,
+			[]DialOption{WithInsecure()},
+			webrtc.Configuration{},
+			logger,
+		)
+		answerer.Start()
+		waitForAnswererOnline(context.Background(), t, []string{host}, signalingCallQueue)
+
+		t.Cleanup(func() {
+			server.Stop()
+			answerer.Stop()
+			grpcServer.Stop()
+			test.That(t, <-serveDone, test.ShouldBeNil)
+			signalingServer.Close()
+			test.That(t, signalingCallQueue.Close(), test.ShouldBeNil)
+		})
+		return grpcListener.Addr().String(), server
+	}
+
+	dial := func(t *testing.T, address, host string) (*webrtcClientChannel, dialOptions) {
+		t.Helper()
+		dOpts := dialOptions{webrtcOpts: DialWebRTCOptions{
+			SignalingInsecure: true,
+			ICERestartTimeout: time.Minute,
+		}}
+		clientCh, err := dialWebRTC(context.Background(), address, host, dOpts, logger)
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() {
+			test.That(t, clientCh.Close(), test.ShouldBeNil)
+		})
+		return clientCh, dOpts
+	}
+
+	echo := func(t *testing.T, clientCh *webrtcClientChannel) {
+		t.Helper()
+		resp, err := echopb.NewEchoServiceClient(clientCh).Echo(context.Background(), &echopb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+	}
+
+	t.Run("renegotiates the same peer connection", func(t *testing.T) {
+		address, server := serve(t, "yeehaw", WebRTCServerOptions{ICERestartTimeout: time.Minute})
+		clientCh, dOpts := dial(t, address, "yeehaw")
+		echo(t, clientCh)
+
+		server.peerConnsMu.Lock()
+		test.That(t, server.peerConns, test.ShouldHaveLength, 1)
+		var serverPC *webrtc.PeerConnection
+		for pc := range server.peerConns {
+			serverPC = pc
+		}
+		server.peerConnsMu.Unlock()
+		remoteBefore := serverPC.RemoteDescription().SDP
+
+		err := restartICE(context.Background(), clientCh.peerConn, address, "yeehaw", dOpts, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, serverPC.RemoteDescription().SDP, test.ShouldNotEqual, remoteBefore)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, clientCh.peerConn.ICEConnectionState(), test.ShouldEqual, webrtc.ICEConnectionStateConnected)
+		})
+		echo(t, clientCh)
+
+		stats := server.Stats()
+		test.That(t, stats.PeersActive, test.ShouldEqual, 1)
+		test.That(t, stats.PeerConnectionSuccesses, test.ShouldEqual, 1)
+		test.That(t, clientCh.Closed(), test.ShouldBeFalse)
+	})
+
+	t.Run("refused unless the server enables it", func(t *testing.T) {
+		address, server := serve(t, "woahthere", WebRTCServerOptions{})
+		clientCh, dOpts := dial(t, address, "woahthere")
+
+		err := restartICE(context.Background(), clientCh.peerConn, address, "woahthere", dOpts, logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, errICERestartsDisabled.Error())
+		test.That(t, server.Stats().PeersActive, test.ShouldEqual, 1)
+	})
+}
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultKeepaliveMissedPings is how many pings in a row may go unanswered before a channel is
+// closed when no other number is configured.
+const defaultKeepaliveMissedPings = 3
+
+// A pinger periodically pings the peer of a channel. It closes the channel once too many
+// pings in a row go unanswered and keeps a smoothed round trip time from the ones that are.
+//
+// Peers that predate pings drop them, so misses are only counted once the peer has answered at
+// least one ping.
+type pinger struct {
+	ch        *webrtcBaseChannel
+	interval  time.Duration
+	maxMissed int
+	sendPing  func(nonce uint64) error
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+	nonce   uint64
+	// sentAt is when the ping with the current nonce was sent. It is zero once answered.
+	sentAt   time.Time
+	missed   int
+	answered bool
+	// rtt and jitter are smoothed like TCP's SRTT and RTTVAR (RFC 6298).
+	rtt    time.Duration
+	jitter time.Duration
+}
+
+// startKeepalive pings the peer every interval using sendPing. The channel is closed after
+// maxMissed pings in a row go unanswered, where zero uses defaultKeepaliveMissedPings. It is a
+// no-op when interval is not positive.
+func (ch *webrtcBaseChannel) startKeepalive(interval time.Duration, maxMissed int, sendPing func(nonce uint64) error) {
+	if interval <= 0 {
+		return
+	}
+	if maxMissed <= 0 {
+		maxMissed = defaultKeepaliveMissedPings
+	}
+	p := &pinger{
+		ch:        ch,
+		interval:  interval,
+		maxMissed: maxMissed,
+		sendPing:  sendPing,
+	}
+	p.mu.Lock()
+	p.timer = time.AfterFunc(interval, p.tick)
+	p.mu.Unlock()
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.closed.Load() {
+		p.stop()
+		return
+	}
+	ch.pinger = p
+}
+
+// onPong records the round trip time of the ping the given pong answers.
+func (ch *webrtcBaseChannel) onPong(nonce uint64) {
+	ch.mu.Lock()
+	p := ch.pinger
+	ch.mu.Unlock()
+	p.onPong(nonce)
+}
+
+// roundTripTime returns the smoothed round trip time to the peer and its variation. It reports
+// false if no ping has been answered yet.
+func (ch *webrtcBaseChannel) roundTripTime() (rtt, jitter time.Duration, ok bool) {
+	ch.mu.Lock()
+	p := ch.pinger
+	ch.mu.Unlock()
+	return p.roundTripTime()
+}
+
+func (p *pinger) tick() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	select {
+	case <-p.ch.ready:
+	default:
+		// nothing can be sent until the data channel opens.
+		p.timer = time.AfterFunc(p.interval, p.tick)
+		p.mu.Unlock()
+		return
+	}
+	if !p.sentAt.IsZero() && p.answered {
+		p.missed++
+	}
+	if p.missed >= p.maxMissed {
+		missed := p.missed
+		p.stopped = true
+		p.mu.Unlock()
+
+		p.ch.logger.Warnw("peer stopped answering pings; closing channel", "missed", missed, "interval", p.interval)
+		if p.ch.wrtcServer != nil {
+			p.ch.wrtcServer.counters.KeepaliveTimeouts.Add(1)
+		}
+		p.ch.Close()
+		return
+	}
+	p.nonce++
+	nonce := p.nonce
+	p.sentAt = time.Now()
+	p.timer = time.AfterFunc(p.interval, p.tick)
+	p.mu.Unlock()
+
+	if err := p.sendPing(nonce); err != nil {
+		p.ch.logger.Debugw("failed to send ping", "error", err)
+	}
+}
+
+func (p *pinger) onPong(nonce uint64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sentAt.IsZero() || nonce != p.nonce {
+		// a late answer to a ping that was already counted as missed.
+		return
+	}
+	sample := time.Since(p.sentAt)
+	p.sentAt = time.Time{}
+	p.missed = 0
+	if !p.answered {
+		p.answered = true
+		p.rtt = sample
+		p.jitter = sample / 2
+		return
+	}
+	diff := p.rtt - sample
+	if diff < 0 {
+		diff = -diff
+	}
+	p.jitter = (3*p.jitter + diff) / 4
+	p.rtt = (7*p.rtt + sample) / 8
+}
+
+func (p *pinger) roundTripTime() (time.Duration, time.Duration, bool) {
+	if p == nil {
+		return 0, 0, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rtt, p.jitter, p.answered
+}
+
+func (p *pinger) stop() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	p.timer.Stop()
+}
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/viamrobotics/webrtc/v3"
+	"go.viam.com/test"
+	pbstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/utils"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/testutils"
+)
+
+func TestPingerRoundTripTime(t *testing.T) {
+	var p *pinger
+	_, _, ok := p.roundTripTime()
+	test.That(t, ok, test.ShouldBeFalse)
+
+	p = &pinger{}
+	answer := func(nonce uint64, rtt time.Duration) {
+		p.mu.Lock()
+		p.nonce = nonce
+		p.sentAt = time.Now().Add(-rtt)
+		p.mu.Unlock()
+		p.onPong(nonce)
+	}
+
+	answer(1, 100*time.Millisecond)
+	rtt, jitter, ok := p.roundTripTime()
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, rtt, test.ShouldAlmostEqual, 100*time.Millisecond, float64(5*time.Millisecond))
+	test.That(t, jitter, test.ShouldAlmostEqual, 50*time.Millisecond, float64(5*time.Millisecond))
+
+	answer(2, 20*time.Millisecond)
+	rtt, jitter, _ = p.roundTripTime()
+	test.That(t, rtt, test.ShouldAlmostEqual, 90*time.Millisecond, float64(5*time.Millisecond))
+	test.That(t, jitter, test.ShouldAlmostEqual, 57500*time.Microsecond, float64(5*time.Millisecond))
+
+	// answers to earlier pings are ignored.
+	p.onPong(1)
+	rtt2, _, _ := p.roundTripTime()
+	test.That(t, rtt2, test.ShouldEqual, rtt)
+}
+
+func TestPingerStartsWhileTicking(t *testing.T) {
+	// The first tick may run before startKeepalive returns, so the pinger's timer must be set
+	// under its lock.
+	for i := 0; i < 10; i++ {
+		ch := &webrtcBaseChannel{ready: make(chan struct{})}
+		ch.startKeepalive(time.Nanosecond, 0, func(nonce uint64) error { return nil })
+		time.Sleep(time.Millisecond)
+		ch.pinger.stop()
+	}
+}
+
+func TestWebRTCKeepalive(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+	pc1, pc2, dc1, dc2 := setupWebRTCPeers(t)
+	defer pc1.GracefulClose()
+	defer pc2.GracefulClose()
+
+	clientCh := newWebRTCClientChannel(pc1, dc1, nil, utils.Sublogger(logger, "client"), nil, nil)
+	defer func() {
+		test.That(t, clientCh.Close(), test.ShouldBeNil)
+	}()
+	clientCh.startKeepalive(20*time.Millisecond, 0, clientCh.writePing)
+
+	server := newWebRTCServerWithOptions(logger, nil, nil, nil, nil, WebRTCServerOptions{
+		KeepaliveInterval: 20 * time.Millisecond,
+	})
+	defer server.Stop()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "service_name",
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName: "info",
+				Handler: grpc.StreamHandler(func(srv any, stream grpc.ServerStream) error {
+					info := PeerConnectionInfoFromContext(stream.Context())
+					return stream.SendMsg(&pbstatus.Status{Message: info.RoundTripTime.String()})
+				}),
+				ServerStreams: true,
+			},
+		},
+	}, nil)
+
+	serverCh := server.NewChannel(pc2, dc2, nil)
+	defer serverCh.Close()
+
+	<-clientCh.Ready()
+	<-serverCh.Ready()
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		rtt, _, ok := clientCh.roundTripTime()
+		test.That(tb, ok, test.ShouldBeTrue)
+		test.That(tb, rtt, test.ShouldBeGreaterThan, 0)
+		test.That(tb, server.Stats().AverageRTTMillis, test.ShouldBeGreaterThan, 0)
+	})
+
+	clientStream, err := clientCh.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/service_name/info")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, clientStream.SendMsg(&pbstatus.Status{}), test.ShouldBeNil)
+	var info pbstatus.Status
+	test.That(t, clientStream.RecvMsg(&info), test.ShouldBeNil)
+	rtt, err := time.ParseDuration(info.GetMessage())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rtt, test.ShouldBeGreaterThan, 0)
+	test.That(t, clientCh.Closed(), test.ShouldBeFalse)
+	test.That(t, serverCh.Closed(), test.ShouldBeFalse)
+	test.That(t, server.Stats().KeepaliveTimeouts, test.ShouldEqual, 0)
+}
+
+func TestWebRTCKeepaliveMissedPings(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	// fakeServer answers only as many pings as it is told to.
+	fakeServer := func(t *testing.T, answers int64) *webrtcClientChannel {
+		t.Helper()
+		pc1, pc2, dc1, dc2 := setupWebRTCPeers(t)
+		t.Cleanup(func() {
+			utils.UncheckedError(pc1.GracefulClose())
+			utils.UncheckedError(pc2.GracefulClose())
+		})
+
+		serverCh := newBaseChannel(context.Background(), pc2, dc2, nil, nil, logger)
+		t.Cleanup(serverCh.Close)
+		var remaining atomic.Int64
+		remaining.Store(answers)
+		dc2.OnMessage(func(msg webrtc.DataChannelMessage) {
+			req := &webrtcpb.Request{}
+			test.That(t, proto.Unmarshal(msg.Data, req), test.ShouldBeNil)
+			if req.GetPing() == nil || remaining.Add(-1) < 0 {
+				return
+			}
+			test.That(t, serverCh.write(&webrtcpb.Response{
+				Stream: &webrtcpb.Stream{},
+				Type:   &webrtcpb.Response_Pong{Pong: &webrtcpb.Pong{Nonce: req.GetPing().GetNonce()}},
+			}), test.ShouldBeNil)
+		})
+
+		clientCh := newWebRTCClientChannel(pc1, dc1, nil, utils.Sublogger(logger, "client"), nil, nil)
+		t.Cleanup(func() {
+			utils.UncheckedError(clientCh.Close())
+		})
+		clientCh.startKeepalive(20*time.Millisecond, 2, clientCh.writePing)
+		<-clientCh.Ready()
+		<-serverCh.Ready()
+		return clientCh
+	}
+
+	t.Run("a peer that stops answering is closed", func(t *testing.T) {
+		clientCh := fakeServer(t, 2)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, clientCh.Closed(), test.ShouldBeTrue)
+		})
+		_, _, ok := clientCh.roundTripTime()
+		test.That(t, ok, test.ShouldBeTrue)
+	})
+
+	t.Run("a peer that never answers is not closed", func(t *testing.T) {
+		clientCh := fakeServer(t, 0)
+		time.Sleep(200 * time.Millisecond)
+		test.That(t, clientCh.Closed(), test.ShouldBeFalse)
+		_, _, ok := clientCh.roundTripTime()
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+}
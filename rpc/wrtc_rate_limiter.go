@@ -7,8 +7,6 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"go.viam.com/utils"
 	mongoutils "go.viam.com/utils/mongo"
@@ -48,6 +46,8 @@ type RateLimitConfig struct {
 	Window      time.Duration
 }
 
+var _ RateLimiter = (*MongoDBRateLimiter)(nil)
+
 // A MongoDBRateLimiter is a MongoDB implementation of a continuous sliding rate limiter designed to be used for
 // multi-node, distributed deployments.
 type MongoDBRateLimiter struct {
@@ -196,10 +196,27 @@ func (rl *MongoDBRateLimiter) Allow(ctx context.Context, key string) error {
 	// No match means rate limit exceeded
 	if result.MatchedCount == 0 {
 		rateLimitDenials.Inc(key)
-		return status.Errorf(codes.ResourceExhausted,
-			"request exceeds rate limit (limit: %d in %v) for %s",
-			rl.config.MaxRequests, rl.config.Window, key)
+		return RateLimitExceededError(key, rl.config, rl.retryAfter(ctx, key))
 	}
 
 	return nil
 }
+
+// retryAfter returns how long until the oldest request for key within the window leaves it. It
+// falls back to the whole window if that cannot be determined.
+func (rl *MongoDBRateLimiter) retryAfter(ctx context.Context, key string) time.Duration {
+	var doc struct {
+		Requests []time.Time `bson:"requests"`
+	}
+	if err := rl.rateLimitColl.FindOne(ctx, bson.M{"_id": key}).Decode(&doc); err != nil {
+		rl.logger.Debugw("failed to look up rate limit requests", "error", err, "key", key)
+		return rl.config.Window
+	}
+	now := time.Now()
+	for _, requested := range doc.Requests {
+		if retryAfter := requested.Add(rl.config.Window).Sub(now); retryAfter > 0 {
+			return min(retryAfter, rl.config.Window)
+		}
+	}
+	return rl.config.Window
+}
@@ -62,6 +62,10 @@ func TestMongoDBRateLimiter(t *testing.T) {
 		errStatus := status.Convert(err)
 		test.That(t, errStatus, test.ShouldNotBeNil)
 		test.That(t, errStatus.Code(), test.ShouldEqual, codes.ResourceExhausted)
+		retryAfter, ok := RateLimitRetryAfter(err)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, retryAfter, test.ShouldBeGreaterThan, 0)
+		test.That(t, retryAfter, test.ShouldBeLessThanOrEqualTo, config.Window)
 	})
 
 	t.Run("sliding window resets after duration", func(t *testing.T) {
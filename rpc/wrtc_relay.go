@@ -0,0 +1,254 @@
+package rpc
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/turn/v2"
+	"github.com/pkg/errors"
+
+	"go.viam.com/utils"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+)
+
+const (
+	// defaultRelayRealm is the TURN realm of a WebRTCRelay by default.
+	defaultRelayRealm = "viam"
+
+	// defaultRelayAllocationsPerEntity is how many relayed addresses an entity may hold at
+	// once by default.
+	defaultRelayAllocationsPerEntity = 10
+)
+
+var errRelayQuotaExceeded = errors.New("relay allocation quota exceeded")
+
+// WebRTCRelayOptions configure a WebRTCRelay.
+type WebRTCRelayOptions struct {
+	// ListenAddress is the UDP address to serve TURN and STUN on (e.g. ":3478").
+	ListenAddress string
+
+	// PublicIP is the IP address peers reach the relay at. It defaults to the IP address
+	// listened on, which must then be given.
+	PublicIP net.IP
+
+	// Realm is the TURN realm. Zero uses "viam".
+	Realm string
+
+	// CredentialsTTL is how long the credentials handed out for the relay are valid for.
+	// Zero uses 12 hours.
+	CredentialsTTL time.Duration
+
+	// MaxAllocationsPerEntity is how many relayed addresses a single entity may hold at once.
+	// Zero allows 10.
+	MaxAllocationsPerEntity int
+}
+
+// WebRTCRelayStats are stats of a WebRTCRelay.
+type WebRTCRelayStats struct {
+	// Allocations is how many relayed addresses each entity holds.
+	Allocations map[string]int
+
+	// RejectedAllocations is how many allocations were refused for exceeding a quota.
+	RejectedAllocations int64
+}
+
+// A WebRTCRelay is a TURN and STUN server, built on pion/turn, that relays WebRTC traffic
+// between peers that cannot reach each other directly, such as those behind symmetric NATs.
+// Once set on a WebRTCSignalingServer, it is advertised to the callers and answerers that
+// the signaling server authenticated, along with credentials for their entity. This way only
+// entities the rpc.Server auth handlers let in can relay through it, each within its quota.
+type WebRTCRelay struct {
+	opts   WebRTCRelayOptions
+	secret string
+	conn   net.PacketConn
+	server *turn.Server
+	logger utils.ZapCompatibleLogger
+
+	mu sync.Mutex
+	// allocatingEntity is the entity of the request authenticated last, which is the one
+	// allocating if an allocation follows since requests on the UDP listener are handled
+	// one at a time.
+	allocatingEntity    string
+	allocations         map[string]int
+	rejectedAllocations int64
+}
+
+// NewWebRTCRelay starts a WebRTCRelay listening on the given address.
+func NewWebRTCRelay(opts WebRTCRelayOptions, logger utils.ZapCompatibleLogger) (*WebRTCRelay, error) {
+	if opts.Realm == "" {
+		opts.Realm = defaultRelayRealm
+	}
+	if opts.CredentialsTTL <= 0 {
+		opts.CredentialsTTL = defaultTURNCredentialsTTL
+	}
+	if opts.MaxAllocationsPerEntity <= 0 {
+		opts.MaxAllocationsPerEntity = defaultRelayAllocationsPerEntity
+	}
+
+	// credentials only have to outlive the relay.
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenPacket("udp4", opts.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+	listenIP := conn.LocalAddr().(*net.UDPAddr).IP
+	if opts.PublicIP == nil {
+		if listenIP.IsUnspecified() {
+			utils.UncheckedError(conn.Close())
+			return nil, errors.New("expected a public IP when listening on all interfaces")
+		}
+		opts.PublicIP = listenIP
+	}
+
+	relay := &WebRTCRelay{
+		opts:        opts,
+		secret:      base64.StdEncoding.EncodeToString(secret),
+		conn:        conn,
+		logger:      logger,
+		allocations: map[string]int{},
+	}
+	relay.server, err = turn.NewServer(turn.ServerConfig{
+		Realm:         opts.Realm,
+		AuthHandler:   relay.authenticate,
+		LoggerFactory: WebRTCLoggerFactory{logger},
+		PacketConnConfigs: []turn.PacketConnConfig{{
+			PacketConn: conn,
+			RelayAddressGenerator: &relayAddressGenerator{
+				RelayAddressGeneratorStatic: &turn.RelayAddressGeneratorStatic{
+					RelayAddress: opts.PublicIP,
+					Address:      listenIP.String(),
+				},
+				relay: relay,
+			},
+		}},
+	})
+	if err != nil {
+		utils.UncheckedError(conn.Close())
+		return nil, err
+	}
+	logger.Infow("relay listening", "address", conn.LocalAddr().String(), "public_ip", opts.PublicIP.String())
+	return relay, nil
+}
+
+// Addr returns the address the relay listens on.
+func (r *WebRTCRelay) Addr() net.Addr {
+	return r.conn.LocalAddr()
+}
+
+// Stats returns stats of the relay.
+func (r *WebRTCRelay) Stats() WebRTCRelayStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	allocations := make(map[string]int, len(r.allocations))
+	for entity, count := range r.allocations {
+		allocations[entity] = count
+	}
+	return WebRTCRelayStats{
+		Allocations:         allocations,
+		RejectedAllocations: r.rejectedAllocations,
+	}
+}
+
+// Close stops the relay and closes all of its allocations.
+func (r *WebRTCRelay) Close() error {
+	return r.server.Close()
+}
+
+// iceServer returns the relay as an ICE server with credentials for the given entity.
+func (r *WebRTCRelay) iceServer(entity string) *webrtcpb.ICEServer {
+	expires := time.Now().Add(r.opts.CredentialsTTL).Truncate(time.Second)
+	username, password := TURNRESTCredentials(r.secret, entity, expires)
+	hostPort := net.JoinHostPort(r.opts.PublicIP.String(), strconv.Itoa(r.conn.LocalAddr().(*net.UDPAddr).Port))
+	return &webrtcpb.ICEServer{
+		Urls:       []string{"stun:" + hostPort, "turn:" + hostPort + "?transport=udp"},
+		Username:   username,
+		Credential: password,
+	}
+}
+
+// authenticate returns the key of credentials minted by iceServer that have not expired yet.
+func (r *WebRTCRelay) authenticate(username, realm string, srcAddr net.Addr) ([]byte, bool) {
+	expiry, entity, ok := strings.Cut(username, ":")
+	if !ok || entity == "" {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return nil, false
+	}
+	_, password := TURNRESTCredentials(r.secret, entity, time.Unix(expires, 0))
+	r.mu.Lock()
+	r.allocatingEntity = entity
+	r.mu.Unlock()
+	return turn.GenerateAuthKey(username, realm, password), true
+}
+
+// beginAllocation counts an allocation against the quota of the entity allocating.
+func (r *WebRTCRelay) beginAllocation() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entity := r.allocatingEntity
+	r.allocatingEntity = ""
+	if entity == "" {
+		return "", errors.New("expected an authenticated allocation")
+	}
+	if r.allocations[entity] >= r.opts.MaxAllocationsPerEntity {
+		r.rejectedAllocations++
+		r.logger.Warnw(errRelayQuotaExceeded.Error(), "entity", entity, "allocations", r.allocations[entity])
+		return "", errRelayQuotaExceeded
+	}
+	r.allocations[entity]++
+	return entity, nil
+}
+
+func (r *WebRTCRelay) endAllocation(entity string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allocations[entity]--
+	if r.allocations[entity] <= 0 {
+		delete(r.allocations, entity)
+	}
+}
+
+// A relayAddressGenerator allocates relayed addresses on the public IP of a relay within
+// the quota of the entity allocating.
+type relayAddressGenerator struct {
+	*turn.RelayAddressGeneratorStatic
+	relay *WebRTCRelay
+}
+
+func (g *relayAddressGenerator) AllocatePacketConn(network string, requestedPort int) (net.PacketConn, net.Addr, error) {
+	entity, err := g.relay.beginAllocation()
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, addr, err := g.RelayAddressGeneratorStatic.AllocatePacketConn(network, requestedPort)
+	if err != nil {
+		g.relay.endAllocation(entity)
+		return nil, nil, err
+	}
+	return &relayPacketConn{PacketConn: conn, end: func() {
+		g.relay.endAllocation(entity)
+	}}, addr, nil
+}
+
+// A relayPacketConn gives an allocation back to its entity's quota once closed.
+type relayPacketConn struct {
+	net.PacketConn
+	endOnce sync.Once
+	end     func()
+}
+
+func (conn *relayPacketConn) Close() error {
+	conn.endOnce.Do(conn.end)
+	return conn.PacketConn.Close()
+}
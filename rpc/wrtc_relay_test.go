@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/pion/turn/v2"
+	"go.viam.com/test"
+	"google.golang.org/grpc/metadata"
+
+	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	echoserver "go.viam.com/utils/rpc/examples/echo/server"
+	"go.viam.com/utils/testutils"
+)
+
+func TestWebRTCRelay(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	internalSignalingHost := "yeehaw"
+	rpcServer, err := NewServer(
+		logger,
+		WithAuthHandler("fake", AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+			return map[string]string{}, nil
+		})),
+		WithWebRTCServerOptions(WebRTCServerOptions{
+			Enable:                 true,
+			InternalSignalingHosts: []string{internalSignalingHost},
+			Relay: &WebRTCRelayOptions{
+				ListenAddress:           "127.0.0.1:0",
+				MaxAllocationsPerEntity: 2,
+			},
+		}),
+		WithDisableMulticastDNS(),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rpcServer.RegisterServiceServer(
+		context.Background(),
+		&echopb.EchoService_ServiceDesc,
+		&echoserver.Server{},
+		echopb.RegisterEchoServiceHandlerFromEndpoint,
+	), test.ShouldBeNil)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	errChan := make(chan error)
+	go func() {
+		errChan <- rpcServer.Serve(listener)
+	}()
+	defer func() {
+		test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		test.That(t, <-errChan, test.ShouldBeNil)
+	}()
+
+	relayServer := func(t *testing.T, entity string) *webrtcpb.ICEServer {
+		t.Helper()
+		conn, err := Dial(context.Background(), listener.Addr().String(), logger,
+			WithInsecure(), WithEntityCredentials(entity, Credentials{Type: "fake"}))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{
+			RPCHostMetadataField: internalSignalingHost,
+		}))
+		resp, err := webrtcpb.NewSignalingServiceClient(conn).OptionalWebRTCConfig(ctx, &webrtcpb.OptionalWebRTCConfigRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		iceServers := resp.GetConfig().GetAdditionalIceServers()
+		test.That(t, iceServers, test.ShouldHaveLength, 1)
+		return iceServers[0]
+	}
+
+	alice := relayServer(t, "alice")
+	test.That(t, alice.GetUrls(), test.ShouldHaveLength, 2)
+	test.That(t, alice.GetUrls()[0], test.ShouldStartWith, "stun:127.0.0.1:")
+	test.That(t, alice.GetUrls()[1], test.ShouldStartWith, "turn:127.0.0.1:")
+	test.That(t, alice.GetUsername(), test.ShouldEndWith, ":alice")
+	relayAddr := strings.TrimSuffix(strings.TrimPrefix(alice.GetUrls()[1], "turn:"), "?transport=udp")
+
+	// allocate returns how to close the allocation or nil if it was refused.
+	allocate := func(t *testing.T, username, password string) func() {
+		t.Helper()
+		clientConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		test.That(t, err, test.ShouldBeNil)
+		client, err := turn.NewClient(&turn.ClientConfig{
+			STUNServerAddr: relayAddr,
+			TURNServerAddr: relayAddr,
+			Username:       username,
+			Password:       password,
+			Conn:           clientConn,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, client.Listen(), test.ShouldBeNil)
+		closeClient := func() {
+			client.Close()
+			test.That(t, clientConn.Close(), test.ShouldBeNil)
+		}
+		relayConn, err := client.Allocate()
+		if err != nil {
+			closeClient()
+			return nil
+		}
+		return func() {
+			test.That(t, relayConn.Close(), test.ShouldBeNil)
+			closeClient()
+		}
+	}
+
+	t.Run("quota", func(t *testing.T) {
+		closeFirst := allocate(t, alice.GetUsername(), alice.GetCredential())
+		test.That(t, closeFirst, test.ShouldNotBeNil)
+		closeSecond := allocate(t, alice.GetUsername(), alice.GetCredential())
+		test.That(t, closeSecond, test.ShouldNotBeNil)
+		defer closeSecond()
+
+		closeThird := allocate(t, alice.GetUsername(), alice.GetCredential())
+		test.That(t, closeThird, test.ShouldBeNil)
+
+		// quotas are per entity.
+		bob := relayServer(t, "bob")
+		closeBob := allocate(t, bob.GetUsername(), bob.GetCredential())
+		test.That(t, closeBob, test.ShouldNotBeNil)
+		closeBob()
+
+		closeFirst()
+		closeThird = allocate(t, alice.GetUsername(), alice.GetCredential())
+		test.That(t, closeThird, test.ShouldNotBeNil)
+		closeThird()
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		closeAllocation := allocate(t, alice.GetUsername(), "wrong")
+		test.That(t, closeAllocation, test.ShouldBeNil)
+		// the entity is part of what is signed.
+		closeAllocation = allocate(t, strings.TrimSuffix(alice.GetUsername(), "alice")+"bob", alice.GetCredential())
+		test.That(t, closeAllocation, test.ShouldBeNil)
+	})
+
+	t.Run("relayed connection", func(t *testing.T) {
+		rtcConn, err := DialWebRTC(context.Background(), listener.Addr().String(), internalSignalingHost, logger,
+			WithInsecure(),
+			WithWebRTCOptions(DialWebRTCOptions{
+				SignalingInsecure:   true,
+				SignalingAuthEntity: "alice",
+				SignalingCreds:      Credentials{Type: "fake"},
+				ForceRelay:          true,
+			}),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, rtcConn.Close(), test.ShouldBeNil)
+		}()
+		resp, err := echopb.NewEchoServiceClient(rtcConn).Echo(context.Background(), &echopb.EchoRequest{Message: "hello"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+	})
+}
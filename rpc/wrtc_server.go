@@ -23,7 +23,7 @@ type webrtcServer struct {
 	logger   utils.ZapCompatibleLogger
 
 	peerConnsMu        sync.Mutex
-	peerConns          map[*webrtc.PeerConnection]struct{}
+	peerConns          map[*webrtc.PeerConnection]*webrtcServerChannel
 	peerConnsToClose   []*webrtc.PeerConnection
 	peerConnsClosingWg sync.WaitGroup
 
@@ -34,12 +34,34 @@ type webrtcServer struct {
 	unknownStreamDesc *grpc.StreamDesc
 	statsHandler      stats.Handler
 
+	// dataChannelPoolSize is how many additional data channels are opened per peer connection
+	// for clients to spread their streams across.
+	dataChannelPoolSize int
+
+	// streamWindowSize and connWindowSize are the flow control windows this server grants
+	// clients for request messages, per stream and per connection.
+	streamWindowSize int64
+	connWindowSize   int64
+
+	// keepaliveInterval and keepaliveMissedPings configure how clients are pinged.
+	keepaliveInterval    time.Duration
+	keepaliveMissedPings int
+
+	// iceRestartTimeout is how long disconnected peer connections are given to recover through
+	// an ICE restart by the client before they are closed.
+	iceRestartTimeout time.Duration
+
+	// authenticatePeer, if set, authenticates the entity of a peer by its DTLS certificate.
+	authenticatePeer func(ctx context.Context) (EntityInfo, error)
+
 	counters struct {
 		PeersActive             atomic.Int64
 		PeerConnectionSuccesses atomic.Int64
 		PeerConnectionErrors    atomic.Int64
 		PeerConnectionCloses    atomic.Int64
 		HeadersProcessed        atomic.Int64
+		KeepaliveTimeouts       atomic.Int64
+		GoAwaysSent             atomic.Int64
 
 		// TotalTimeConnectingMillis just counts successful connection attempts.
 		TotalTimeConnectingMillis atomic.Int64
@@ -54,10 +76,17 @@ type WebRTCGrpcStats struct {
 	PeerConnectionCloses      int64
 	HeadersProcessed          int64
 	TotalTimeConnectingMillis int64
+	KeepaliveTimeouts         int64
+	GoAwaysSent               int64
 
 	// When the FTDC frontend is more feature rich, we can remove this and let the frontend compute
 	// the value.
 	AverageTimeConnectingMillis float64
+
+	// AverageRTTMillis and AverageRTTJitterMillis average the smoothed round trip times, and
+	// their variation, of the active peers that have answered a ping.
+	AverageRTTMillis       float64
+	AverageRTTJitterMillis float64
 }
 
 // Stats returns stats.
@@ -69,11 +98,33 @@ func (srv *webrtcServer) Stats() WebRTCGrpcStats {
 		PeerConnectionCloses:      srv.counters.PeerConnectionCloses.Load(),
 		HeadersProcessed:          srv.counters.HeadersProcessed.Load(),
 		TotalTimeConnectingMillis: srv.counters.TotalTimeConnectingMillis.Load(),
+		KeepaliveTimeouts:         srv.counters.KeepaliveTimeouts.Load(),
+		GoAwaysSent:               srv.counters.GoAwaysSent.Load(),
 	}
 	if ret.PeerConnectionSuccesses > 0 {
 		ret.AverageTimeConnectingMillis = float64(ret.TotalTimeConnectingMillis) / float64(ret.PeerConnectionSuccesses)
 	}
 
+	srv.peerConnsMu.Lock()
+	channels := make([]*webrtcServerChannel, 0, len(srv.peerConns))
+	for _, ch := range srv.peerConns {
+		channels = append(channels, ch)
+	}
+	srv.peerConnsMu.Unlock()
+	var totalRTT, totalJitter time.Duration
+	var measured int
+	for _, ch := range channels {
+		if rtt, jitter, ok := ch.roundTripTime(); ok {
+			totalRTT += rtt
+			totalJitter += jitter
+			measured++
+		}
+	}
+	if measured > 0 {
+		ret.AverageRTTMillis = float64(totalRTT.Microseconds()) / 1000 / float64(measured)
+		ret.AverageRTTJitterMillis = float64(totalJitter.Microseconds()) / 1000 / float64(measured)
+	}
+
 	return ret
 }
 
@@ -86,27 +137,38 @@ type serviceInfo struct {
 
 // newWebRTCServer makes a new server with no registered services.
 func newWebRTCServer(logger utils.ZapCompatibleLogger) *webrtcServer {
-	return newWebRTCServerWithOptions(logger, nil, nil, nil, nil)
+	return newWebRTCServerWithOptions(logger, nil, nil, nil, nil, WebRTCServerOptions{})
 }
 
 // newWebRTCServerWithOptions makes a new server with no registered services that will
-// use the given interceptors, unknown stream handler, and stats handler.
+// use the given interceptors, unknown stream handler, stats handler, and the peer
+// connection related settings of the WebRTC options.
 func newWebRTCServerWithOptions(
 	logger utils.ZapCompatibleLogger,
 	unaryInt grpc.UnaryServerInterceptor,
 	streamInt grpc.StreamServerInterceptor,
 	unknownStreamDesc *grpc.StreamDesc,
 	statsHandler stats.Handler,
+	webrtcOpts WebRTCServerOptions,
 ) *webrtcServer {
 	srv := &webrtcServer{
 		handlers:          map[string]handlerFunc{},
 		services:          map[string]*serviceInfo{},
 		logger:            logger,
-		peerConns:         map[*webrtc.PeerConnection]struct{}{},
+		peerConns:         map[*webrtc.PeerConnection]*webrtcServerChannel{},
 		unaryInt:          unaryInt,
 		streamInt:         streamInt,
 		unknownStreamDesc: unknownStreamDesc,
 		statsHandler:      statsHandler,
+
+		dataChannelPoolSize: webrtcOpts.DataChannelPoolSize,
+		streamWindowSize:    flowControlWindowSize(webrtcOpts.InitialWindowSize, defaultStreamWindowSize),
+		connWindowSize:      flowControlWindowSize(webrtcOpts.InitialConnWindowSize, defaultConnWindowSize),
+
+		keepaliveInterval:    webrtcOpts.KeepaliveInterval,
+		keepaliveMissedPings: webrtcOpts.KeepaliveMissedPings,
+
+		iceRestartTimeout: webrtcOpts.ICERestartTimeout,
 	}
 	srv.workers = utils.NewBackgroundStoppableWorkers()
 	srv.workers.Add(srv.pcCloseLoop)
@@ -144,6 +206,24 @@ func (srv *webrtcServer) Stop() {
 	srv.logger.Info("lingering peer connections closed")
 }
 
+// goAway tells every connected client that the server is shutting down so that they make new
+// calls elsewhere.
+func (srv *webrtcServer) goAway() {
+	srv.peerConnsMu.Lock()
+	channels := make([]*webrtcServerChannel, 0, len(srv.peerConns))
+	for _, ch := range srv.peerConns {
+		channels = append(channels, ch)
+	}
+	srv.peerConnsMu.Unlock()
+	for _, ch := range channels {
+		if err := ch.writeGoAway(); err != nil {
+			srv.logger.Debugw("failed to send go away", "error", err)
+			continue
+		}
+		srv.counters.GoAwaysSent.Add(1)
+	}
+}
+
 // RegisterService registers the given implementation of a service to be handled via
 // WebRTC data channels. It extracts the unary and stream methods from a service description
 // and calls the methods on the implementation when requested via a data channel.
@@ -219,7 +299,7 @@ func (srv *webrtcServer) NewChannel(
 ) *webrtcServerChannel {
 	serverCh := newWebRTCServerChannel(srv, peerConn, dataChannel, authAudience, srv.logger)
 	srv.peerConnsMu.Lock()
-	srv.peerConns[peerConn] = struct{}{}
+	srv.peerConns[peerConn] = serverCh
 	srv.counters.PeersActive.Add(1)
 	srv.peerConnsMu.Unlock()
 	return serverCh
@@ -2,8 +2,11 @@ package rpc
 
 import (
 	"context"
+	"errors"
+	"math"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/viamrobotics/webrtc/v3"
 	"google.golang.org/grpc/metadata"
@@ -26,6 +29,19 @@ type webrtcServerChannel struct {
 	authAudience string
 	server       *webrtcServer
 	streams      map[uint64]*webrtcServerStream
+
+	// authEntity is the entity the peer authenticated as, worked out once on the first stream.
+	authEntityOnce sync.Once
+	authEntity     EntityInfo
+	authClaims     Claims
+
+	// connRecvWindow collects the bytes of flow controlled requests to hand back to the client.
+	connRecvWindow *recvWindow
+	// clientFlowControlled is set once the client says it honors the windows this server grants.
+	clientFlowControlled atomic.Bool
+	// connSendWindow is the connection flow control window the client grants for responses. It
+	// is unset until a client that supports flow control makes a stream and is guarded by mu.
+	connSendWindow *sendWindow
 }
 
 // newWebRTCServerChannel wraps the given WebRTC data channel to be used as the server end
@@ -50,13 +66,49 @@ func newWebRTCServerChannel(
 		webrtcBaseChannel: base,
 		server:            server,
 		streams:           make(map[uint64]*webrtcServerStream),
+		connRecvWindow:    newRecvWindow(server.connWindowSize),
 	}
 	dataChannel.OnMessage(ch.onChannelMessage)
+	ch.startKeepalive(server.keepaliveInterval, server.keepaliveMissedPings, ch.writePing)
+	ch.enableICERestart(server.iceRestartTimeout, nil)
+	if server.dataChannelPoolSize > 0 {
+		ch.activeBackgroundWorkers.Add(1)
+		go func() {
+			defer ch.activeBackgroundWorkers.Done()
+			ch.openDataChannelPool(server.dataChannelPoolSize)
+		}()
+	}
 	return ch
 }
 
+// openDataChannelPool opens poolSize additional data channels, once the primary data channel
+// is open, that clients may spread their streams across. Clients that do not know about
+// pooling ignore them and keep using the primary data channel.
+func (ch *webrtcServerChannel) openDataChannelPool(poolSize int) {
+	select {
+	case <-ch.ctx.Done():
+		return
+	case <-ch.Ready():
+	}
+	ordered := true
+	for i := 0; i < poolSize; i++ {
+		dc, err := ch.peerConn.CreateDataChannel(dataChannelPoolLabel, &webrtc.DataChannelInit{Ordered: &ordered})
+		if err != nil {
+			if ch.ctx.Err() == nil {
+				ch.webrtcBaseChannel.logger.Warnw("failed to open pooled data channel", "error", err)
+			}
+			return
+		}
+		if dc.ID() == nil {
+			utils.UncheckedError(dc.Close())
+			continue
+		}
+		ch.addPoolChannel(dc, ch.onChannelMessage)
+	}
+}
+
 func (ch *webrtcServerChannel) writeHeaders(stream *webrtcpb.Stream, headers *webrtcpb.ResponseHeaders) error {
-	return ch.webrtcBaseChannel.write(&webrtcpb.Response{
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Response{
 		Stream: stream,
 		Type: &webrtcpb.Response_Headers{
 			Headers: headers,
@@ -65,7 +117,7 @@ func (ch *webrtcServerChannel) writeHeaders(stream *webrtcpb.Stream, headers *we
 }
 
 func (ch *webrtcServerChannel) writeMessage(stream *webrtcpb.Stream, msg *webrtcpb.ResponseMessage) error {
-	return ch.webrtcBaseChannel.write(&webrtcpb.Response{
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Response{
 		Stream: stream,
 		Type: &webrtcpb.Response_Message{
 			Message: msg,
@@ -74,7 +126,7 @@ func (ch *webrtcServerChannel) writeMessage(stream *webrtcpb.Stream, msg *webrtc
 }
 
 func (ch *webrtcServerChannel) writeTrailers(stream *webrtcpb.Stream, trailers *webrtcpb.ResponseTrailers) error {
-	return ch.webrtcBaseChannel.write(&webrtcpb.Response{
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Response{
 		Stream: stream,
 		Type: &webrtcpb.Response_Trailers{
 			Trailers: trailers,
@@ -82,12 +134,97 @@ func (ch *webrtcServerChannel) writeTrailers(stream *webrtcpb.Stream, trailers *
 	})
 }
 
+func (ch *webrtcServerChannel) writeWindowUpdate(stream *webrtcpb.Stream, increment uint32) error {
+	return ch.webrtcBaseChannel.writeOn(stream.GetChannelId(), &webrtcpb.Response{
+		Stream: stream,
+		Type: &webrtcpb.Response_WindowUpdate{
+			WindowUpdate: &webrtcpb.WindowUpdate{Increment: increment},
+		},
+	})
+}
+
+func (ch *webrtcServerChannel) writePing(nonce uint64) error {
+	return ch.webrtcBaseChannel.write(&webrtcpb.Response{
+		Stream: &webrtcpb.Stream{},
+		Type: &webrtcpb.Response_Ping{
+			Ping: &webrtcpb.Ping{Nonce: nonce},
+		},
+	})
+}
+
+func (ch *webrtcServerChannel) writePong(nonce uint64) error {
+	return ch.webrtcBaseChannel.write(&webrtcpb.Response{
+		Stream: &webrtcpb.Stream{},
+		Type: &webrtcpb.Response_Pong{
+			Pong: &webrtcpb.Pong{Nonce: nonce},
+		},
+	})
+}
+
+func (ch *webrtcServerChannel) writeGoAway() error {
+	return ch.webrtcBaseChannel.write(&webrtcpb.Response{
+		Stream: &webrtcpb.Stream{},
+		Type:   &webrtcpb.Response_GoAway{GoAway: &webrtcpb.GoAway{}},
+	})
+}
+
+// handBackDiscarded hands the connection window bytes of a packet for a stream that is already
+// done back to the client so that they are not lost.
+func (ch *webrtcServerChannel) handBackDiscarded(stream *webrtcpb.Stream, msg *webrtcpb.PacketMessage) {
+	if msg == nil || !ch.clientFlowControlled.Load() {
+		return
+	}
+	cost := packetCost(len(msg.GetData()))
+	ch.connRecvWindow.receive(cost)
+	if increment := ch.connRecvWindow.consume(cost); increment > 0 {
+		if err := ch.writeWindowUpdate(&webrtcpb.Stream{ChannelId: stream.GetChannelId()}, increment); err != nil {
+			ch.webrtcBaseChannel.logger.Debugw("failed to write connection window update", "error", err)
+		}
+	}
+}
+
+// connSendWindowOfSize returns the connection flow control window the client grants for
+// responses, making it with the given size the first time.
+func (ch *webrtcServerChannel) connSendWindowOfSize(size uint32) *sendWindow {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.connSendWindow == nil {
+		ch.connSendWindow = newSendWindow(flowControlWindowSize(int32(min(size, math.MaxInt32)), defaultConnWindowSize))
+	}
+	return ch.connSendWindow
+}
+
 func (ch *webrtcServerChannel) removeStreamByID(id uint64) {
 	ch.mu.Lock()
 	delete(ch.streams, id)
 	ch.mu.Unlock()
 }
 
+// peerAuthEntity returns the entity the peer authenticated as with its DTLS certificate. If the
+// server does not authenticate peers that way or the certificate is not trusted, it falls back to
+// the audience, without claims.
+func (ch *webrtcServerChannel) peerAuthEntity(ctx context.Context) (EntityInfo, Claims) {
+	ch.authEntityOnce.Do(func() {
+		// TODO(GOUT-11): Handle auth; right now we assume successful auth to the signaler
+		// implies that auth should be allowed here, which is not 100% true.
+		// TODO(RSDK-890): use the correct entity (sub), not the audience (hosts)
+		ch.authEntity = EntityInfo{Entity: ch.authAudience}
+		if ch.server.authenticatePeer == nil {
+			return
+		}
+		entity, err := ch.server.authenticatePeer(ctx)
+		if err != nil {
+			if !errors.Is(err, errNotTLSAuthed) {
+				ch.webrtcBaseChannel.logger.Debugw("failed to authenticate peer certificate", "error", err)
+			}
+			return
+		}
+		ch.authEntity = entity
+		ch.authClaims = mtlsClaims(entity.Entity)
+	})
+	return ch.authEntity, ch.authClaims
+}
+
 func (ch *webrtcServerChannel) onChannelMessage(msg webrtc.DataChannelMessage) {
 	req := &webrtcpb.Request{}
 	err := proto.Unmarshal(msg.Data, req)
@@ -103,14 +240,36 @@ func (ch *webrtcServerChannel) onChannelMessage(msg webrtc.DataChannelMessage) {
 
 	id := stream.GetId()
 
+	switch r := req.GetType().(type) {
+	case *webrtcpb.Request_Ping:
+		if err := ch.writePong(r.Ping.GetNonce()); err != nil {
+			ch.webrtcBaseChannel.logger.Debugw("failed to answer ping", "error", err)
+		}
+		return
+	case *webrtcpb.Request_Pong:
+		ch.onPong(r.Pong.GetNonce())
+		return
+	}
+
 	ch.mu.Lock()
 	serverStream, ok := ch.streams[id]
+	if update, isUpdate := req.GetType().(*webrtcpb.Request_WindowUpdate); isUpdate && (id == 0 || !ok) {
+		// connection window updates have no stream and stream window updates may arrive after
+		// the stream is done.
+		connSendWindow := ch.connSendWindow
+		ch.mu.Unlock()
+		if id == 0 && connSendWindow != nil {
+			connSendWindow.add(int64(update.WindowUpdate.GetIncrement()))
+		}
+		return
+	}
 	if !ok {
 		// peek headers for timeout
 		headers, ok := req.GetType().(*webrtcpb.Request_Headers)
 		if !ok || headers.Headers == nil {
 			ch.webrtcBaseChannel.logger.Debugf("expected headers as first message but got %T, discard request", req.GetType())
 			ch.mu.Unlock()
+			ch.handBackDiscarded(stream, req.GetMessage().GetPacketMessage())
 			return
 		}
 
@@ -123,11 +282,13 @@ func (ch *webrtcServerChannel) onChannelMessage(msg webrtc.DataChannelMessage) {
 			handlerCtx, cancelCtx = context.WithTimeout(handlerCtx, timeout)
 		}
 		handlerCtx = ContextWithPeerConnection(handlerCtx, ch.peerConn)
+		handlerCtx = contextWithRoundTripTimer(handlerCtx, ch.webrtcBaseChannel)
 
-		// TODO(GOUT-11): Handle auth; right now we assume successful auth to the signaler
-		// implies that auth should be allowed here, which is not 100% true.
-		// TODO(RSDK-890): use the correct entity (sub), not the audience (hosts)
-		handlerCtx = ContextWithAuthEntity(handlerCtx, EntityInfo{Entity: ch.authAudience})
+		authEntity, authClaims := ch.peerAuthEntity(handlerCtx)
+		if authClaims != nil {
+			handlerCtx = contextWithAuthClaims(handlerCtx, authClaims)
+		}
+		handlerCtx = ContextWithAuthEntity(handlerCtx, authEntity)
 
 		if sh := ch.server.statsHandler; sh != nil {
 			handlerCtx = sh.TagRPC(handlerCtx, &stats.RPCTagInfo{FullMethodName: headers.Headers.GetMethod()})
@@ -3,6 +3,8 @@ package rpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"sync"
 	"testing"
 
@@ -548,3 +550,81 @@ func (srv *delayingEchoServer) Echo(ctx context.Context, req *echopb.EchoRequest
 	}
 	return resp, err
 }
+
+func TestWebRTCDataChannelPool(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+
+	for _, clientPoolSize := range []int{0, 1, 2} {
+		t.Run(fmt.Sprintf("client pool size %d", clientPoolSize), func(t *testing.T) {
+			pc1, pc2, dc1, dc2 := setupWebRTCPeers(t)
+			defer pc1.GracefulClose()
+			defer pc2.GracefulClose()
+
+			clientCh := newWebRTCClientChannel(pc1, dc1, nil, utils.Sublogger(logger, "client"), nil, nil)
+			defer func() {
+				test.That(t, clientCh.Close(), test.ShouldBeNil)
+			}()
+			if clientPoolSize > 0 {
+				clientCh.acceptDataChannelPool(clientPoolSize, nil)
+			}
+
+			server := newWebRTCServer(logger)
+			server.dataChannelPoolSize = 2
+			defer server.Stop()
+			server.RegisterService(&echopb.EchoService_ServiceDesc, &echoserver.Server{})
+
+			serverCh := newWebRTCServerChannel(server, pc2, dc2, nil, logger)
+			defer serverCh.Close()
+
+			<-clientCh.Ready()
+			<-serverCh.Ready()
+
+			testutils.WaitForAssertion(t, func(tb testing.TB) {
+				tb.Helper()
+				clientCh.mu.Lock()
+				defer clientCh.mu.Unlock()
+				test.That(tb, clientCh.poolChannelIDs, test.ShouldHaveLength, clientPoolSize)
+			})
+
+			// unary calls stay on the primary data channel; streams rotate through the pool.
+			test.That(t, clientCh.nextStreamID("/a/b", false).GetChannelId(), test.ShouldEqual, 0)
+			seen := map[uint32]struct{}{}
+			for i := 0; i < 2*clientPoolSize; i++ {
+				seen[clientCh.nextStreamID("/a/b", true).GetChannelId()] = struct{}{}
+			}
+			if clientPoolSize == 0 {
+				test.That(t, clientCh.nextStreamID("/a/b", true).GetChannelId(), test.ShouldEqual, 0)
+			} else {
+				test.That(t, seen, test.ShouldHaveLength, clientPoolSize)
+				test.That(t, seen, test.ShouldNotContainKey, uint32(0))
+			}
+
+			client := echopb.NewEchoServiceClient(clientCh)
+			resp, err := client.Echo(context.Background(), &echopb.EchoRequest{Message: "hello"})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, resp.GetMessage(), test.ShouldEqual, "hello")
+
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					stream, err := client.EchoMultiple(context.Background(), &echopb.EchoMultipleRequest{Message: "hey"})
+					test.That(t, err, test.ShouldBeNil)
+					var received string
+					for {
+						resp, err := stream.Recv()
+						if errors.Is(err, io.EOF) {
+							break
+						}
+						test.That(t, err, test.ShouldBeNil)
+						received += resp.GetMessage()
+					}
+					test.That(t, received, test.ShouldEqual, "hey")
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
@@ -7,10 +7,10 @@ import (
 	"math"
 	"sync/atomic"
 
-	protov1 "github.com/golang/protobuf/proto" //nolint:staticcheck
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/stats"
 	"google.golang.org/grpc/status"
@@ -37,6 +37,10 @@ type webrtcServerStream struct {
 	header          metadata.MD
 	trailer         metadata.MD
 	sendClosed      atomic.Bool
+
+	// acceptedCompression lists the compressors offered by the client that this server can
+	// decompress requests with.
+	acceptedCompression []string
 }
 
 // newWebRTCServerStream creates a gRPC stream from the given server channel with a
@@ -132,13 +136,15 @@ var maxResponseMessagePacketDataSize int
 func init() {
 	md, err := proto.Marshal(&webrtcpb.Response{
 		Stream: &webrtcpb.Stream{
-			Id: math.MaxUint64,
+			Id:        math.MaxUint64,
+			ChannelId: math.MaxUint16,
 		},
 		Type: &webrtcpb.Response_Message{
 			Message: &webrtcpb.ResponseMessage{
 				PacketMessage: &webrtcpb.PacketMessage{
-					Data: []byte{0x0},
-					Eom:  true,
+					Data:       []byte{0x0},
+					Eom:        true,
+					Compressed: true,
 				},
 			},
 		},
@@ -179,18 +185,19 @@ func (s *webrtcServerStream) SendMsg(m interface{}) (err error) {
 		return err
 	}
 
-	s.webrtcBaseStream.mu.RLock()
-	defer s.webrtcBaseStream.mu.RUnlock()
+	unlock, lock := s.webrtcBaseStream.mu.RUnlock, s.webrtcBaseStream.mu.RLock
+	lock()
+	defer unlock()
 
-	if v1Msg, ok := m.(protov1.Message); ok {
-		m = protov1.MessageV2(v1Msg)
-	}
-	data, err := proto.Marshal(m.(proto.Message))
+	data, compressed, err := s.webrtcBaseStream.encodeMessage(m)
 	if err != nil {
 		return err
 	}
 
 	if len(data) == 0 {
+		if err := s.webrtcBaseStream.waitForSendWindow("server", 0, unlock, lock); err != nil {
+			return err
+		}
 		return s.ch.writeMessage(s.stream, &webrtcpb.ResponseMessage{
 			PacketMessage: &webrtcpb.PacketMessage{
 				Eom: true,
@@ -204,12 +211,16 @@ func (s *webrtcServerStream) SendMsg(m interface{}) (err error) {
 			amountToSend = len(data)
 		}
 		packet := &webrtcpb.PacketMessage{
-			Data: data[:amountToSend],
+			Data:       data[:amountToSend],
+			Compressed: compressed,
 		}
 		data = data[amountToSend:]
 		if len(data) == 0 {
 			packet.Eom = true
 		}
+		if err := s.webrtcBaseStream.waitForSendWindow("server", amountToSend, unlock, lock); err != nil {
+			return err
+		}
 		if err := s.ch.writeMessage(s.stream, &webrtcpb.ResponseMessage{
 			PacketMessage: packet,
 		}); err != nil {
@@ -224,6 +235,10 @@ func (s *webrtcServerStream) onRequest(request *webrtcpb.Request) {
 	// misbehavior during validation. Additionally, clients can go away at any time, so failing to
 	// respond is likewise not an error.
 	switch r := request.GetType().(type) {
+	case *webrtcpb.Request_WindowUpdate:
+		if s.sendWindow != nil {
+			s.sendWindow.add(int64(r.WindowUpdate.GetIncrement()))
+		}
 	case *webrtcpb.Request_Headers:
 		if s.headersReceived {
 			s.closeWithSendError(status.Error(codes.InvalidArgument, "headers already received"))
@@ -255,8 +270,24 @@ func (s *webrtcServerStream) processHeaders(headers *webrtcpb.RequestHeaders) {
 		}
 	}
 
+	if err := s.negotiateCompression(headers); err != nil {
+		s.closeWithSendError(err)
+		return
+	}
+	s.negotiateFlowControl(headers)
+
 	s.ch.server.counters.HeadersProcessed.Add(1)
 	s.headersReceived = true
+	if _, ok := s.ctx.Deadline(); ok {
+		// Like gRPC, the call ends with DEADLINE_EXCEEDED once its deadline passes, even if the
+		// handler has not returned yet. The handler sees its context expire and anything it
+		// sends afterwards fails.
+		context.AfterFunc(s.ctx, func() {
+			if errors.Is(s.ctx.Err(), context.DeadlineExceeded) {
+				s.closeWithSendError(status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error()))
+			}
+		})
+	}
 	s.ch.server.workers.Add(func(ctx context.Context) {
 		if sh := s.ch.server.statsHandler; sh != nil {
 			sh.HandleRPC(s.ctx, &stats.Begin{})
@@ -269,6 +300,48 @@ func (s *webrtcServerStream) processHeaders(headers *webrtcpb.RequestHeaders) {
 	})
 }
 
+// negotiateCompression picks the compressors for this stream. Requests are decompressed with
+// the compressor the client names, and responses are compressed with the first compressor the
+// client accepts that is also registered here.
+func (s *webrtcServerStream) negotiateCompression(headers *webrtcpb.RequestHeaders) error {
+	if name := headers.GetCompression(); name != "" {
+		compressor := encoding.GetCompressor(name)
+		if compressor == nil {
+			return status.Errorf(codes.Unimplemented, "grpc: Decompressor is not installed for grpc-encoding %q", name)
+		}
+		s.recvCompressor = compressor
+	}
+	for _, name := range headers.GetAcceptedCompression() {
+		compressor := encoding.GetCompressor(name)
+		if compressor == nil {
+			continue
+		}
+		s.acceptedCompression = append(s.acceptedCompression, name)
+		if s.sendCompressor == nil {
+			s.sendCompressor = compressor
+		}
+	}
+	return nil
+}
+
+// negotiateFlowControl sets up flow control for this stream. Responses are flow controlled
+// when the client grants windows for them, and requests when the client says it honors the
+// windows this server grants.
+func (s *webrtcServerStream) negotiateFlowControl(headers *webrtcpb.RequestHeaders) {
+	windowSize := headers.GetWindowSize()
+	if windowSize == 0 {
+		return
+	}
+	s.writeWindowUpdate = s.ch.writeWindowUpdate
+	s.sendWindow = newSendWindow(int64(windowSize))
+	s.connSendWindow = s.ch.connSendWindowOfSize(headers.GetConnectionWindowSize())
+	if headers.GetFlowControlled() {
+		s.ch.clientFlowControlled.Store(true)
+		s.recvWindow = newRecvWindow(s.ch.server.streamWindowSize)
+		s.connRecvWindow = s.ch.connRecvWindow
+	}
+}
+
 func (s *webrtcServerStream) processMessage(msg *webrtcpb.RequestMessage) {
 	if s.recvClosed.Load() {
 		s.logger.Debug("message received after EOS")
@@ -279,27 +352,15 @@ func (s *webrtcServerStream) processMessage(msg *webrtcpb.RequestMessage) {
 			s.closeWithError(errors.New("expected RequestMessage.PacketMessgae to not be nil but it was"), false)
 			return
 		}
-		data, eop := s.webrtcBaseStream.processMessage(msg.GetPacketMessage())
-		if !eop {
+		data, eop, err := s.webrtcBaseStream.processMessage(msg.GetPacketMessage())
+		if err != nil {
+			s.closeWithSendError(err)
 			return
 		}
-		s.webrtcBaseStream.mu.Lock()
-		if s.recvClosed.Load() {
-			s.webrtcBaseStream.mu.Unlock()
+		if !eop {
 			return
 		}
-		msgCh := s.msgCh
-		s.webrtcBaseStream.activeSenders.Add(1)
-		s.webrtcBaseStream.mu.Unlock()
-
-		func() {
-			defer s.webrtcBaseStream.activeSenders.Done()
-			select {
-			case msgCh <- data:
-			case <-s.ctx.Done():
-				return
-			}
-		}()
+		s.webrtcBaseStream.enqueueMessage(data)
 	}
 	if msg.GetEos() {
 		s.CloseRecv()
@@ -311,6 +372,10 @@ func (s *webrtcServerStream) closeWithSendError(err error) {
 	if !s.sendClosed.CompareAndSwap(false, true) {
 		return
 	}
+	if s.sendWindow != nil {
+		// wake up a SendMsg waiting for the client to hand back window.
+		s.sendWindow.close()
+	}
 
 	defer func() {
 		s.webrtcBaseStream.mu.Lock()
@@ -356,9 +421,20 @@ func (s *webrtcServerStream) writeHeaders() error {
 	s.mu.RLock()
 	protoHeaders := metadataToProto(s.header)
 	s.mu.RUnlock()
-	return s.ch.writeHeaders(s.stream, &webrtcpb.ResponseHeaders{
-		Metadata: protoHeaders,
-	})
+	var compression string
+	if s.sendCompressor != nil {
+		compression = s.sendCompressor.Name()
+	}
+	headers := &webrtcpb.ResponseHeaders{
+		Metadata:            protoHeaders,
+		Compression:         compression,
+		AcceptedCompression: s.acceptedCompression,
+	}
+	if s.sendWindow != nil {
+		headers.WindowSize = uint32(s.ch.server.streamWindowSize)
+		headers.ConnectionWindowSize = uint32(s.ch.server.connWindowSize)
+	}
+	return s.ch.writeHeaders(s.stream, headers)
 }
 
 // ErrorToStatus converts an error to a gRPC status. A nil
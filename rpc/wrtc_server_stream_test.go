@@ -2,12 +2,19 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"go.viam.com/test"
+	pbstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.viam.com/utils"
 	"go.viam.com/utils/testutils"
@@ -51,3 +58,138 @@ func TestWebRTCServerStreamHeaderRace(t *testing.T) {
 	}()
 	wg.Wait()
 }
+
+func TestWebRTCDeadlinePropagation(t *testing.T) {
+	testutils.SkipUnlessInternet(t)
+	logger := golog.NewTestLogger(t)
+	pc1, pc2, dc1, dc2 := setupWebRTCPeers(t)
+	defer utils.UncheckedErrorFunc(pc1.GracefulClose)
+	defer utils.UncheckedErrorFunc(pc2.GracefulClose)
+
+	clientCh := newWebRTCClientChannel(pc1, dc1, nil, utils.Sublogger(logger, "client"), nil, nil)
+	defer func() {
+		test.That(t, clientCh.Close(), test.ShouldBeNil)
+	}()
+
+	server := newWebRTCServer(logger)
+	defer server.Stop()
+
+	type handlerResult struct {
+		ctxErr  error
+		sendErr error
+	}
+	handlerStarted := make(chan struct{}, 1)
+	handlerDone := make(chan handlerResult, 1)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "service_name",
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName: "deadline",
+				Handler: grpc.StreamHandler(func(srv any, stream grpc.ServerStream) error {
+					var remaining string
+					if deadline, ok := stream.Context().Deadline(); ok {
+						remaining = time.Until(deadline).String()
+					}
+					return stream.SendMsg(&pbstatus.Status{Message: remaining})
+				}),
+				ServerStreams: true,
+			},
+			{
+				// wait stays around after its context is done, ignoring it like a handler doing
+				// uninterruptible work would.
+				StreamName: "wait",
+				Handler: grpc.StreamHandler(func(srv any, stream grpc.ServerStream) error {
+					handlerStarted <- struct{}{}
+					<-stream.Context().Done()
+					time.Sleep(100 * time.Millisecond)
+					handlerDone <- handlerResult{
+						ctxErr:  stream.Context().Err(),
+						sendErr: stream.SendMsg(&pbstatus.Status{}),
+					}
+					return nil
+				}),
+				ServerStreams: true,
+			},
+		},
+	}, nil)
+
+	serverCh := newWebRTCServerChannel(server, pc2, dc2, nil, logger)
+	defer serverCh.Close()
+
+	<-clientCh.Ready()
+	<-serverCh.Ready()
+
+	call := func(ctx context.Context, method string) (grpc.ClientStream, error) {
+		clientStream, err := clientCh.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, method)
+		if err != nil {
+			return nil, err
+		}
+		if err := clientStream.SendMsg(&pbstatus.Status{}); err != nil {
+			return nil, err
+		}
+		return clientStream, clientStream.CloseSend()
+	}
+
+	t.Run("the deadline is propagated to the handler", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		clientStream, err := call(ctx, "/service_name/deadline")
+		test.That(t, err, test.ShouldBeNil)
+		var msg pbstatus.Status
+		test.That(t, clientStream.RecvMsg(&msg), test.ShouldBeNil)
+		remaining, err := time.ParseDuration(msg.GetMessage())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, remaining, test.ShouldBeBetweenOrEqual, 4*time.Second, 5*time.Second)
+
+		clientStream, err = call(context.Background(), "/service_name/deadline")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, clientStream.RecvMsg(&msg), test.ShouldBeNil)
+		test.That(t, msg.GetMessage(), test.ShouldBeEmpty)
+	})
+
+	t.Run("cancellation cancels the handler", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		clientStream, err := call(ctx, "/service_name/wait")
+		test.That(t, err, test.ShouldBeNil)
+		<-handlerStarted
+		cancel()
+
+		var msg pbstatus.Status
+		test.That(t, clientStream.RecvMsg(&msg), test.ShouldBeError, context.Canceled)
+		result := <-handlerDone
+		test.That(t, result.ctxErr, test.ShouldBeError, context.Canceled)
+		test.That(t, result.sendErr, test.ShouldNotBeNil)
+	})
+
+	t.Run("the server ends calls whose deadline passed", func(t *testing.T) {
+		// send the headers by hand so that only the server knows of the deadline.
+		method := "/service_name/wait"
+		clientStream, err := clientCh.newStream(context.Background(), clientCh.nextStreamID(method, true))
+		test.That(t, err, test.ShouldBeNil)
+		headers := makeRequestHeaders(context.Background(), method)
+		headers.Timeout = durationpb.New(50 * time.Millisecond)
+		test.That(t, clientStream.writeHeaders(headers), test.ShouldBeNil)
+		test.That(t, clientStream.SendMsg(&pbstatus.Status{}), test.ShouldBeNil)
+		<-handlerStarted
+
+		// the call ends before the handler returns.
+		var msg pbstatus.Status
+		err = clientStream.RecvMsg(&msg)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+		result := <-handlerDone
+		test.That(t, result.ctxErr, test.ShouldBeError, context.DeadlineExceeded)
+		test.That(t, result.sendErr, test.ShouldNotBeNil)
+	})
+
+	t.Run("calls are not started after the deadline", func(t *testing.T) {
+		headersProcessed := server.counters.HeadersProcessed.Load()
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		_, err := call(ctx, "/service_name/deadline")
+		test.That(t, errors.Is(err, context.DeadlineExceeded), test.ShouldBeTrue)
+		var msg pbstatus.Status
+		err = clientCh.Invoke(ctx, "/service_name/deadline", &pbstatus.Status{}, &msg)
+		test.That(t, errors.Is(err, context.DeadlineExceeded), test.ShouldBeTrue)
+		test.That(t, server.counters.HeadersProcessed.Load(), test.ShouldEqual, headersProcessed)
+	})
+}
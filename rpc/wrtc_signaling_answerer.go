@@ -13,12 +13,14 @@ import (
 	"github.com/pion/stun"
 	"github.com/pkg/errors"
 	"github.com/viamrobotics/webrtc/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"go.viam.com/utils"
 	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/trace"
 )
 
 const testDelayAnswererNegotiationVar = "TEST_DELAY_ANSWERER_NEGOTIATION"
@@ -262,6 +264,7 @@ func (ans *webrtcSignalingAnswerer) startAnswerer() {
 				aa.trickleEnabled = false
 			}
 			aa.offerSDP = initStage.Init.GetSdp()
+			aa.traceContext = initStage.Init.GetTraceContext()
 
 			answerCtx, answerCtxCancel := getDeadline(ctx, ans.logger, initStage)
 			if err = aa.connect(answerCtx); err != nil {
@@ -304,6 +307,8 @@ type answerAttempt struct {
 
 	trickleEnabled bool
 	offerSDP       string
+	// traceContext is the trace context of the caller the offer came from, if it was traced.
+	traceContext map[string]string
 
 	// When a connection attempt concludes, either with success or failure, we will fire a single
 	// message to the signaling server. This allows the signaling server to release resources
@@ -319,6 +324,13 @@ func (aa *answerAttempt) connect(ctx context.Context) (err error) {
 	connectionStartTime := time.Now()
 	deadline, _ := ctx.Deadline() // there will always be a deadline
 
+	ctx, span := trace.StartSpan(contextWithCallTrace(ctx, aa.traceContext), "Answerer::connect")
+	span.SetAttributes(attribute.String("uuid", aa.uuid))
+	defer func() {
+		endCallSpan(span, err)
+	}()
+	addCallEvent(span, "offer received")
+
 	// install an error handler while PeerConnection is nil
 	var pc *webrtc.PeerConnection
 	defer func() {
@@ -331,6 +343,18 @@ func (aa *answerAttempt) connect(ctx context.Context) (err error) {
 		}
 	}()
 
+	// Offers of ICE restarts renegotiate a peer connection this answerer already made.
+	restartPC, restartOffer, err := aa.server.peerConnToRestart(aa.offerSDP)
+	if err != nil {
+		aa.sendError(err)
+		aa.server.counters.PeerConnectionErrors.Add(1)
+		return err
+	}
+	if restartPC != nil {
+		addCallEvent(span, "ice restart")
+		return aa.restartICE(ctx, restartPC, restartOffer)
+	}
+
 	// If SOCKS proxy is indicated by environment, extend WebRTC config with an
 	// `OptionalWebRTCConfig` call to the signaling server. The usage of a SOCKS
 	// proxy indicates that the server may need a local TURN ICE candidate to
@@ -515,6 +539,7 @@ func (aa *answerAttempt) connect(ctx context.Context) (err error) {
 					return
 				}
 				iProto := iceCandidateToProto(icecandidate)
+				addCallEvent(span, "answerer candidate", candidateEventAttributes(iProto)...)
 				if err := aa.client.Send(&webrtcpb.AnswerResponse{
 					Uuid: aa.uuid,
 					Stage: &webrtcpb.AnswerResponse_Update{
@@ -564,6 +589,7 @@ func (aa *answerAttempt) connect(ctx context.Context) (err error) {
 		return err
 	}
 	close(initSent)
+	addCallEvent(span, "answer sent")
 
 	if aa.trickleEnabled {
 		done := make(chan struct{})
@@ -590,12 +616,14 @@ func (aa *answerAttempt) connect(ctx context.Context) (err error) {
 						aa.sendError(fmt.Errorf("uuid mismatch; have=%q want=%q", ansResp.GetUuid(), aa.uuid))
 						return
 					}
+					addCallEvent(span, "caller candidate", candidateEventAttributes(stage.Update.GetCandidate())...)
 					cand := iceCandidateFromProto(stage.Update.GetCandidate())
 					if err := pc.AddICECandidate(cand); err != nil {
 						aa.sendError(err)
 						return
 					}
 				case *webrtcpb.AnswerRequest_Done:
+					addCallEvent(span, "caller done")
 					return
 				case *webrtcpb.AnswerRequest_Error:
 					respStatus := status.FromProto(stage.Error.GetStatus())
@@ -617,6 +645,7 @@ func (aa *answerAttempt) connect(ctx context.Context) (err error) {
 	case <-serverChannel.Ready():
 		// Happy path
 		successful = true
+		addCallEvent(span, "connected")
 		aa.server.counters.PeerConnectionSuccesses.Add(1)
 		aa.server.counters.TotalTimeConnectingMillis.Add(time.Since(connectionStartTime).Milliseconds())
 	case <-ctx.Done():
@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
-	"go.opencensus.io/trace"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -19,6 +20,7 @@ import (
 
 	"go.viam.com/utils"
 	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/trace"
 )
 
 // A WebRTCSignalingServer implements a signaling service for WebRTC by exchanging
@@ -34,6 +36,9 @@ type WebRTCSignalingServer struct {
 	callQueue            WebRTCCallQueue
 	hostICEServers       map[string]hostICEServers
 	webrtcConfigProvider WebRTCConfigProvider
+	relay                *WebRTCRelay
+	admission            *webrtcCallAdmission
+	onlineHostsFilter    WebRTCOnlineHostsFilter
 	forHosts             map[string]struct{}
 
 	bgWorkers *utils.StoppableWorkers
@@ -67,6 +72,7 @@ func NewWebRTCSignalingServer(
 		callQueue:            callQueue,
 		hostICEServers:       map[string]hostICEServers{},
 		webrtcConfigProvider: webrtcConfigProvider,
+		admission:            newWebRTCCallAdmission(),
 		forHosts:             forHostsSet,
 		bgWorkers:            bgWorkers,
 		logger:               logger,
@@ -180,9 +186,13 @@ func (srv *WebRTCSignalingServer) asyncSendOfferError(host, uuid string, offerEr
 }
 
 // Call is a request/offer to start a caller with the connected answerer.
-func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcpb.SignalingService_CallServer) error {
-	ctx, span := trace.StartSpan(server.Context(), "SignalingServer::Call")
-	defer span.End()
+func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcpb.SignalingService_CallServer) (err error) {
+	// the call is traced as part of the trace of the caller, which the queue passes on to
+	// the answerer.
+	ctx, callSpan := trace.StartSpan(contextWithCallTrace(server.Context(), req.GetTraceContext()), "SignalingServer::Call")
+	defer func() {
+		endCallSpan(callSpan, err)
+	}()
 
 	ctx, cancel := context.WithTimeout(ctx, getDefaultOfferDeadline())
 	defer cancel()
@@ -191,14 +201,25 @@ func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcp
 	if err != nil {
 		return err
 	}
+	callSpan.SetAttributes(attribute.String("host", host))
 	if err := srv.validateHosts(host); err != nil {
 		return err
 	}
+	priority := srv.admission.priority(ctx)
+	callSpan.SetAttributes(attribute.String("priority", priority.String()))
+	answered, release, err := srv.admission.admit(host, priority)
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx = ContextWithWebRTCCallPriority(ctx, priority)
+
 	uuid, respCh, respDone, sendCancel, err := srv.callQueue.SendOfferInit(ctx, host, req.GetSdp(), req.GetDisableTrickle())
 	if err != nil {
 		return err
 	}
 	defer sendCancel()
+	addCallEvent(callSpan, "offer queued", attribute.String("uuid", uuid))
 
 	var haveInit bool
 	for {
@@ -211,6 +232,7 @@ func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcp
 			return nil
 		case resp = <-respCh:
 		}
+		answered()
 		if resp.Err != nil {
 			err := fmt.Errorf("error from answerer: %w", resp.Err)
 			srv.asyncSendOfferError(host, uuid, err)
@@ -224,6 +246,7 @@ func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcp
 		}
 		if !haveInit {
 			haveInit = true
+			addCallEvent(callSpan, "answer received")
 			if err := server.Send(&webrtcpb.CallResponse{
 				Uuid: uuid,
 				Stage: &webrtcpb.CallResponse_Init{
@@ -242,6 +265,7 @@ func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcp
 		}
 
 		ip := iceCandidateInitToProto(*resp.Candidate)
+		addCallEvent(callSpan, "answerer candidate", candidateEventAttributes(ip)...)
 		if err := server.Send(&webrtcpb.CallResponse{
 			Uuid: uuid,
 			Stage: &webrtcpb.CallResponse_Update{
@@ -260,9 +284,15 @@ func (srv *WebRTCSignalingServer) Call(req *webrtcpb.CallRequest, server webrtcp
 // CallUpdate is used to send additional info in relation to a Call.
 // In a world where https://github.com/grpc/grpc-web/issues/24 is fixed,
 // this should be removed in favor of a bidirectional stream on Call.
-func (srv *WebRTCSignalingServer) CallUpdate(ctx context.Context, req *webrtcpb.CallUpdateRequest) (*webrtcpb.CallUpdateResponse, error) {
-	ctx, span := trace.StartSpan(ctx, "SignalingServer::CallUpdate")
-	defer span.End()
+func (srv *WebRTCSignalingServer) CallUpdate(
+	ctx context.Context,
+	req *webrtcpb.CallUpdateRequest,
+) (_ *webrtcpb.CallUpdateResponse, err error) {
+	ctx, callSpan := trace.StartSpan(contextWithCallTrace(ctx, req.GetTraceContext()), "SignalingServer::CallUpdate")
+	callSpan.SetAttributes(attribute.String("uuid", req.GetUuid()))
+	defer func() {
+		endCallSpan(callSpan, err)
+	}()
 
 	ctx, cancel := context.WithTimeout(ctx, getDefaultOfferDeadline())
 	defer cancel()
@@ -275,15 +305,18 @@ func (srv *WebRTCSignalingServer) CallUpdate(ctx context.Context, req *webrtcpb.
 	}
 	switch u := req.GetUpdate().(type) {
 	case *webrtcpb.CallUpdateRequest_Candidate:
+		addCallEvent(callSpan, "caller candidate", candidateEventAttributes(u.Candidate)...)
 		cand := iceCandidateFromProto(u.Candidate)
 		if err := srv.callQueue.SendOfferUpdate(ctx, host, req.GetUuid(), cand); err != nil {
 			return nil, err
 		}
 	case *webrtcpb.CallUpdateRequest_Error:
+		addCallEvent(callSpan, "caller error", attribute.String("error", req.GetError().GetMessage()))
 		if err := srv.callQueue.SendOfferError(ctx, host, req.GetUuid(), status.ErrorProto(req.GetError())); err != nil {
 			return nil, err
 		}
 	case *webrtcpb.CallUpdateRequest_Done:
+		addCallEvent(callSpan, "caller done")
 		if err := srv.callQueue.SendOfferDone(ctx, host, req.GetUuid()); err != nil {
 			return nil, err
 		}
@@ -324,6 +357,54 @@ func (srv *WebRTCSignalingServer) additionalICEServers(ctx context.Context, host
 	return config.ICEServers, nil
 }
 
+// SetRelay makes the signaling server hand out the given relay to the callers and answerers
+// it authenticated, with credentials for their entity. Unauthenticated ones do not get it.
+func (srv *WebRTCSignalingServer) SetRelay(relay *WebRTCRelay) {
+	srv.mu.Lock()
+	srv.relay = relay
+	srv.mu.Unlock()
+}
+
+// withRelayICEServer returns the ICE servers along with the relay, if any, for the entity
+// authenticated in the context.
+func (srv *WebRTCSignalingServer) withRelayICEServer(ctx context.Context, iceServers []*webrtcpb.ICEServer) []*webrtcpb.ICEServer {
+	srv.mu.RLock()
+	relay := srv.relay
+	srv.mu.RUnlock()
+	if relay == nil {
+		return iceServers
+	}
+	entity, ok := ContextAuthEntity(ctx)
+	if !ok {
+		return iceServers
+	}
+	// the ICE servers may be cached for others.
+	return append(slices.Clone(iceServers), relay.iceServer(entity.Entity))
+}
+
+// A WebRTCOnlineHostsFilter returns which of the given online hosts the caller of
+// ListOnlineHosts, whose auth entity is in the context (see ContextAuthEntity), may see.
+type WebRTCOnlineHostsFilter func(ctx context.Context, hosts []string) ([]string, error)
+
+// SetOnlineHostsFilter enables ListOnlineHosts, listing only the hosts the filter lets the
+// caller see. ListOnlineHosts is refused until a filter is set since it would otherwise list the
+// hosts of every tenant to any caller.
+func (srv *WebRTCSignalingServer) SetOnlineHostsFilter(filter WebRTCOnlineHostsFilter) {
+	srv.mu.Lock()
+	srv.onlineHostsFilter = filter
+	srv.mu.Unlock()
+}
+
+// SetCallAdmission sets how calls are prioritized and capped per host.
+func (srv *WebRTCSignalingServer) SetCallAdmission(opts WebRTCCallAdmissionOptions) {
+	srv.admission.setOptions(opts)
+}
+
+// CallStats returns stats of the calls the server is handling.
+func (srv *WebRTCSignalingServer) CallStats() WebRTCCallStats {
+	return srv.admission.stats()
+}
+
 // Note: We expect but do not enforce one host for one answer. If this is not true, a race
 // can happen where we may double fetch additional ICE servers.
 func (srv *WebRTCSignalingServer) clearAdditionalICEServers(hosts []string) {
@@ -337,10 +418,8 @@ func (srv *WebRTCSignalingServer) clearAdditionalICEServers(hosts []string) {
 // Answer listens on call/offer queue for a single call responding with a corresponding SDP
 // and candidate updates/errors.
 // Note: See SinalingAnswer.answer for the complementary side of this process.
-func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_AnswerServer) error {
-	ctx, span := trace.StartSpan(server.Context(), "SignalingServer::Answer")
-	defer span.End()
-
+func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_AnswerServer) (err error) {
+	ctx := server.Context()
 	hosts, err := HostsFromCtx(ctx)
 	if err != nil {
 		return err
@@ -360,6 +439,7 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 	// for this answerer. We stop handling interactions because the stream's
 	// context (`ctx` here and below) is used in the `RecvOffer` call below this
 	// goroutine that waits for a caller to attempt to establish a connection.
+	presence := NewWebRTCAnswererPresence(answererVersionFromCtx(ctx))
 	if HeartbeatsAllowedFromCtx(ctx) {
 		utils.PanicCapturingGo(func() {
 			for {
@@ -372,7 +452,9 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 							"sending answer heartbeat failed",
 							"error", err,
 						)
+						continue
 					}
+					presence.Heartbeat()
 				case <-ctx.Done():
 					return
 				}
@@ -380,7 +462,7 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 		})
 	}
 
-	offer, err := srv.callQueue.RecvOffer(ctx, hosts)
+	offer, err := srv.callQueue.RecvOffer(ContextWithWebRTCAnswererPresence(ctx, presence), hosts)
 	if err != nil {
 		return err
 	}
@@ -389,9 +471,16 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 	if err != nil {
 		return err
 	}
+	iceServers = srv.withRelayICEServer(ctx, iceServers)
 
-	// initialize
+	// initialize; the span starts once there is an offer so that it is part of the trace of its
+	// caller rather than of the wait for it.
 	uuid := offer.UUID()
+	callCtx, callSpan := trace.StartSpan(contextWithCallTrace(ctx, offerTraceContext(offer)), "SignalingServer::Answer")
+	callSpan.SetAttributes(attribute.String("uuid", uuid), attribute.StringSlice("hosts", hosts))
+	defer func() {
+		endCallSpan(callSpan, err)
+	}()
 	if err := server.Send(&webrtcpb.AnswerRequest{
 		Uuid: uuid,
 		Stage: &webrtcpb.AnswerRequest_Init{
@@ -401,12 +490,14 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 					AdditionalIceServers: iceServers,
 					DisableTrickle:       offer.DisableTrickleICE(),
 				},
-				Deadline: timestamppb.New(offer.Deadline()),
+				Deadline:     timestamppb.New(offer.Deadline()),
+				TraceContext: callTraceContext(callCtx),
 			},
 		},
 	}); err != nil {
 		return err
 	}
+	addCallEvent(callSpan, "offer sent to answerer")
 
 	offerCtx, offerCtxCancel := context.WithDeadline(ctx, offer.Deadline())
 	var answererStoppedExchange atomic.Bool
@@ -433,6 +524,7 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 				return offerCtx.Err()
 			case <-offer.CallerDone():
 				callerErr := offer.CallerErr()
+				addCallEvent(callSpan, "caller done")
 				if callerErr != nil {
 					if err := server.Send(&webrtcpb.AnswerRequest{
 						Uuid: uuid,
@@ -448,6 +540,7 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 				return callerErr
 			case cand := <-offer.CallerCandidates():
 				ip := iceCandidateInitToProto(cand)
+				addCallEvent(callSpan, "caller candidate", candidateEventAttributes(ip)...)
 				if err := server.Send(&webrtcpb.AnswerRequest{
 					Uuid: uuid,
 					Stage: &webrtcpb.AnswerRequest_Update{
@@ -484,6 +577,7 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 				}
 				haveInit = true
 				init := s.Init
+				addCallEvent(callSpan, "answer received")
 
 				ans := WebRTCCallAnswer{InitialSDP: &init.Sdp}
 				if err := offer.AnswererRespond(server.Context(), ans); err != nil {
@@ -493,6 +587,7 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 				if !haveInit {
 					return errors.New("got update stage before init stage")
 				}
+				addCallEvent(callSpan, "answerer candidate", candidateEventAttributes(s.Update.GetCandidate())...)
 				cand := iceCandidateFromProto(s.Update.GetCandidate())
 				if err := offer.AnswererRespond(server.Context(), WebRTCCallAnswer{
 					Candidate: &cand,
@@ -503,9 +598,11 @@ func (srv *WebRTCSignalingServer) Answer(server webrtcpb.SignalingService_Answer
 				if !haveInit {
 					return errors.New("got done stage before init stage")
 				}
+				addCallEvent(callSpan, "answerer done")
 				return nil
 			case *webrtcpb.AnswerResponse_Error:
 				respStatus := status.FromProto(s.Error.GetStatus())
+				addCallEvent(callSpan, "answerer error", attribute.String("error", respStatus.Message()))
 				ans := WebRTCCallAnswer{Err: respStatus.Err()}
 				answererStoppedExchange.Store(true)
 				offerCtxCancel() // and stop exchange
@@ -565,11 +662,123 @@ func (srv *WebRTCSignalingServer) OptionalWebRTCConfig(
 	if err != nil {
 		return nil, err
 	}
+	iceServers = srv.withRelayICEServer(ctx, iceServers)
 	return &webrtcpb.OptionalWebRTCConfigResponse{Config: &webrtcpb.WebRTCConfig{
 		AdditionalIceServers: iceServers,
 	}}, nil
 }
 
+// ListOnlineHosts lists the hosts that have answerers connected, as known to the call queue,
+// that the caller may see. It is refused unless enabled with SetOnlineHostsFilter.
+func (srv *WebRTCSignalingServer) ListOnlineHosts(
+	ctx context.Context,
+	req *webrtcpb.ListOnlineHostsRequest,
+) (*webrtcpb.ListOnlineHostsResponse, error) {
+	ctx, span := trace.StartSpan(ctx, "SignalingServer::ListOnlineHosts")
+	defer span.End()
+
+	srv.mu.RLock()
+	filter := srv.onlineHostsFilter
+	srv.mu.RUnlock()
+	if filter == nil {
+		return nil, status.Error(codes.PermissionDenied, "listing online hosts is not enabled")
+	}
+
+	directory, err := srv.hostDirectory()
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := directory.HostStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(statuses))
+	for _, hostStatus := range statuses {
+		if srv.validateHosts(hostStatus.Host) != nil {
+			continue
+		}
+		hosts = append(hosts, hostStatus.Host)
+	}
+	visibleHosts, err := filter(ctx, hosts)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[string]struct{}, len(visibleHosts))
+	for _, host := range visibleHosts {
+		visible[host] = struct{}{}
+	}
+	resp := &webrtcpb.ListOnlineHostsResponse{}
+	for _, hostStatus := range statuses {
+		if _, ok := visible[hostStatus.Host]; !ok {
+			continue
+		}
+		resp.Hosts = append(resp.Hosts, hostStatusToProto(hostStatus))
+	}
+	return resp, nil
+}
+
+// GetHostStatus returns whether the host in the metadata has answerers connected, as known to
+// the call queue.
+func (srv *WebRTCSignalingServer) GetHostStatus(
+	ctx context.Context,
+	req *webrtcpb.GetHostStatusRequest,
+) (*webrtcpb.GetHostStatusResponse, error) {
+	ctx, span := trace.StartSpan(ctx, "SignalingServer::GetHostStatus")
+	defer span.End()
+
+	host, err := HostFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.validateHosts(host); err != nil {
+		return nil, err
+	}
+	directory, err := srv.hostDirectory()
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := directory.HostStatuses(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) != 1 {
+		return nil, fmt.Errorf("expected 1 status but got %d", len(statuses))
+	}
+	return &webrtcpb.GetHostStatusResponse{Status: hostStatusToProto(statuses[0])}, nil
+}
+
+func (srv *WebRTCSignalingServer) hostDirectory() (WebRTCHostDirectory, error) {
+	directory, ok := srv.callQueue.(WebRTCHostDirectory)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "call queue does not know which hosts are online")
+	}
+	return directory, nil
+}
+
+func hostStatusToProto(hostStatus WebRTCHostStatus) *webrtcpb.HostStatus {
+	protoStatus := &webrtcpb.HostStatus{
+		Host:            hostStatus.Host,
+		Online:          hostStatus.Answerers > 0,
+		Answerers:       uint32(hostStatus.Answerers),
+		AnswererVersion: hostStatus.AnswererVersion,
+	}
+	if !hostStatus.LastHeartbeat.IsZero() {
+		protoStatus.LastHeartbeat = timestamppb.New(hostStatus.LastHeartbeat)
+	}
+	return protoStatus
+}
+
+// answererVersionFromCtx identifies the client software of an answerer from its metadata.
+func answererVersionFromCtx(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, field := range []string{ViamClientMetadataField, UserAgentMetadataField} {
+		if values := md.Get(field); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
+
 // Close cancels all active workers and waits to cleanly close all background workers.
 func (srv *WebRTCSignalingServer) Close() {
 	srv.bgWorkers.Stop()
@@ -16,8 +16,10 @@ import (
 	"github.com/viamrobotics/webrtc/v3"
 	"go.viam.com/test"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.viam.com/utils"
@@ -96,6 +98,37 @@ func testWebRTCSignaling(t *testing.T, signalingCallQueue WebRTCCallQueue, logge
 			}()
 			signalClient := webrtcpb.NewSignalingServiceClient(cc)
 
+			// listing online hosts is refused until the server decides who may see which hosts.
+			_, err = signalClient.ListOnlineHosts(context.Background(), &webrtcpb.ListOnlineHostsRequest{})
+			test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+			signalingServer.SetOnlineHostsFilter(func(ctx context.Context, onlineHosts []string) ([]string, error) {
+				return lo.Intersect(onlineHosts, []string{host}), nil
+			})
+
+			// the answerer is known to be online without placing a call.
+			testutils.WaitForAssertion(t, func(tb testing.TB) {
+				tb.Helper()
+				hostCtx := metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{"rpc-host": host}))
+				statusResp, err := signalClient.GetHostStatus(hostCtx, &webrtcpb.GetHostStatusRequest{})
+				test.That(tb, err, test.ShouldBeNil)
+				test.That(tb, statusResp.GetStatus().GetHost(), test.ShouldEqual, host)
+				test.That(tb, statusResp.GetStatus().GetOnline(), test.ShouldBeTrue)
+				test.That(tb, statusResp.GetStatus().GetAnswerers(), test.ShouldBeGreaterThan, 0)
+				test.That(tb, statusResp.GetStatus().GetAnswererVersion(), test.ShouldStartWith, "grpc-go/")
+				test.That(tb, statusResp.GetStatus().GetLastHeartbeat(), test.ShouldNotBeNil)
+
+				listResp, err := signalClient.ListOnlineHosts(context.Background(), &webrtcpb.ListOnlineHostsRequest{})
+				test.That(tb, err, test.ShouldBeNil)
+				onlineHosts := lo.Map(listResp.GetHosts(), func(status *webrtcpb.HostStatus, _ int) string {
+					return status.GetHost()
+				})
+				test.That(tb, onlineHosts, test.ShouldResemble, []string{host})
+			})
+			offlineCtx := metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{"rpc-host": "offline"}))
+			statusResp, err := signalClient.GetHostStatus(offlineCtx, &webrtcpb.GetHostStatusRequest{})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, statusResp.GetStatus().GetOnline(), test.ShouldBeFalse)
+
 			callClient, err := signalClient.Call(context.Background(), &webrtcpb.CallRequest{})
 			test.That(t, err, test.ShouldBeNil)
 			_, err = callClient.Recv()
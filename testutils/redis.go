@@ -0,0 +1,55 @@
+package testutils
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/multierr"
+
+	"go.viam.com/utils"
+)
+
+func backingRedisURI() (string, bool) {
+	redisURI, ok := os.LookupEnv("TEST_REDIS_URI")
+	if !ok || redisURI == "" {
+		return "", false
+	}
+	return redisURI, true
+}
+
+// BackingRedisClient returns a backing Redis client to use. If TEST_REDIS_URI is set
+// (e.g. redis://localhost:6379/0), the client connects to that server; otherwise an
+// in-process fake Redis server is started for the duration of the test. The client is
+// closed when the test finishes.
+func BackingRedisClient(tb testing.TB) redis.UniversalClient {
+	tb.Helper()
+
+	var opts *redis.Options
+	if redisURI, ok := backingRedisURI(); ok {
+		var err error
+		opts, err = redis.ParseURL(redisURI)
+		if err != nil {
+			skipWithError(tb, err)
+			return nil
+		}
+	} else {
+		server := miniredis.RunT(tb)
+		opts = &redis.Options{Addr: server.Addr()}
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		skipWithError(tb, multierr.Combine(err, client.Close()))
+		return nil
+	}
+	tb.Cleanup(func() {
+		utils.UncheckedError(client.Close())
+	})
+	return client
+}